@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/batch"
+	"github.com/martinemde/skillet/internal/budget"
+	"github.com/martinemde/skillet/internal/color"
+	"github.com/martinemde/skillet/internal/command"
+	"github.com/martinemde/skillet/internal/executor"
+	"github.com/martinemde/skillet/internal/exitcode"
+	"github.com/martinemde/skillet/internal/resolver"
+	"github.com/martinemde/skillet/internal/skill"
+)
+
+// defaultBatchJobs is how many inputs `skillet batch` runs at once
+const defaultBatchJobs = 4
+
+// runBatch handles the `skillet batch <skill> --inputs <file|glob>` subcommand
+func runBatch(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet batch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		inputs         = flags.String("inputs", "", "Inputs: a glob of files, or a `file` with one input per line (- for stdin)")
+		jobs           = flags.Int("j", defaultBatchJobs, "Run `N` inputs at once")
+		logDir         = flags.String("log-dir", "", "Save each input's stream-json log in `dir` (default skillet-batch-<time>)")
+		model          = flags.String("model", "", "Override model to use (overrides SKILL.md setting)")
+		permissionMode = flags.String("permission-mode", "", "Override permission mode (default: acceptEdits)")
+		maxCost        = flags.Float64("max-cost", 0, "Stop each input's run when its estimated cost passes this many USD")
+		maxTurns       = flags.Int("max-turns", 0, "Stop each input's run after this many turns")
+		backendName    = flags.String("backend", "", "Agent backend: claude, replay:<file>, or a backend from the config file")
+		auditLogPath   = flags.String("audit-log", "", "Append a JSONL audit log of each input's run to this file")
+		offline        = flags.Bool("offline", false, "Resolve URLs and git sources only from the cache")
+		colorFlag      = flags.String("color", "auto", "Control color output (auto, always, never)")
+	)
+	// Read by applyConfigDefaults
+	flags.String("profile", "", "Apply a named profile from the config files")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet batch [options] <skill> --inputs <file|glob>")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "Runs a skill once per input, several at a time, and summarizes the results.")
+		_, _ = fmt.Fprintln(stderr, "A glob passes each matching file as the skill's argument. An inputs file has")
+		_, _ = fmt.Fprintln(stderr, "one input per line: arguments as you'd type them, or a JSON object of named")
+		_, _ = fmt.Fprintln(stderr, "arguments.")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	positional, err := installFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		flags.Usage()
+		return usageError("skill required")
+	}
+	if *inputs == "" {
+		flags.Usage()
+		return usageError("--inputs required")
+	}
+	if err := applyConfigDefaults(flags); err != nil {
+		return err
+	}
+	limits, err := flagLimits(*maxCost, *maxTurns)
+	if err != nil {
+		return err
+	}
+
+	res, err := resolver.New()
+	if err != nil {
+		return err
+	}
+	res.Offline = *offline
+	result, err := res.Resolve(positional[0])
+	if err != nil {
+		return fmt.Errorf("failed to resolve skill or command: %w", err)
+	}
+	defer result.Cleanup()
+
+	runner, err := newUnattendedRunner(*backendName, *auditLogPath)
+	if err != nil {
+		return err
+	}
+
+	batchInputs, err := batch.LoadInputs(*inputs, os.Stdin)
+	if err != nil {
+		return err
+	}
+	if *logDir == "" {
+		*logDir = "skillet-batch-" + time.Now().Format("20060102-150405")
+	}
+
+	// Each input parses the resource with its own arguments
+	execute := func(ctx context.Context, input batch.Input, stdout, stderr io.Writer) error {
+		config, limits, err := resourceConfig(result, input.Args, *model, *permissionMode, limits)
+		if err != nil {
+			return err
+		}
+		return runner.run(ctx, positional[0], result, config, limits, stdout, stderr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	useColors := color.ShouldUseColors(*colorFlag)
+	progress := batch.NewProgress(stderr, len(batchInputs), isTerminal(stderr), useColors)
+	results, err := batch.Run(ctx, batchInputs, batch.Options{
+		Jobs:      *jobs,
+		LogDir:    *logDir,
+		SkillName: positional[0],
+		SkillPath: result.Path,
+		Execute:   execute,
+		Progress:  progress,
+	})
+	progress.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := batch.WriteSummary(stdout, results); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return exitcode.ErrInterrupted
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Status() != batch.StatusOK {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s failed", failed, len(results), pluralize(len(results), "input", "inputs"))
+	}
+	return nil
+}
+
+// resourceConfig parses a resolved skill or command with input and builds
+// the executor config for an unattended run, without the prompt server, and
+// its limits
+func resourceConfig(result *resolver.ResolveResult, input arguments.Input, model, permissionMode string, limits budget.Limits) (executor.Config, budget.Limits, error) {
+	var parsedSkill *skill.Skill
+	var cmd *command.Command
+	var err error
+	switch result.Type {
+	case resolver.ResourceTypeSkill:
+		parsedSkill, err = skill.ParseWithInput(result.Path, result.BaseURL, input)
+	case resolver.ResourceTypeCommand:
+		cmd, err = command.ParseWithInput(result.Path, result.BaseURL, input)
+	}
+	if err != nil {
+		return executor.Config{}, limits, err
+	}
+
+	schema, err := resolveOutputSchema("", parsedSkill)
+	if err != nil {
+		return executor.Config{}, limits, err
+	}
+	limits = resourceLimits(limits, parsedSkill, cmd)
+	config := executor.Config{
+		Prompt:         resolvePromptFromResource("", parsedSkill, cmd),
+		SystemPrompt:   buildSystemPromptFromResource(parsedSkill, cmd),
+		Model:          resolveString(model, resourceModel(parsedSkill, cmd)),
+		AllowedTools:   resourceAllowedTools(parsedSkill, cmd),
+		PermissionMode: permissionMode,
+		MaxTurns:       limits.MaxTurns,
+	}
+	if schema != nil {
+		config.JSONSchema = schema.String()
+	}
+	return config, limits, nil
+}
+
+// isTerminal reports whether w is a terminal, for redrawing progress in place
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/martinemde/skillet/internal/exitcode"
+	"github.com/martinemde/skillet/internal/gitsource"
+	"github.com/martinemde/skillet/internal/httpcache"
+	"github.com/martinemde/skillet/internal/index"
+	"github.com/martinemde/skillet/internal/resolver"
+	"github.com/martinemde/skillet/internal/xdg"
+)
+
+// defaultPruneAge is how long an unused cache entry is kept by `skillet cache prune`
+const defaultPruneAge = 30 * 24 * time.Hour
+
+// runCache handles the `skillet cache list|clear|prune` subcommand
+func runCache(args []string, stdout, stderr io.Writer) error {
+	usage := func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet cache <list|clear|prune> [options]")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "Manages skills downloaded from URLs and git sources.")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "  list    Show cached URLs and git refs")
+		_, _ = fmt.Fprintln(stderr, "  clear   Remove everything from the cache, including the skill index")
+		_, _ = fmt.Fprintln(stderr, "  prune   Remove entries unused for --older-than (default 720h)")
+	}
+	if len(args) == 0 {
+		usage()
+		return usageError("cache command required")
+	}
+
+	cacheDir, err := xdg.CacheDir()
+	if err != nil {
+		return err
+	}
+	httpCache := httpcache.New(filepath.Join(cacheDir, resolver.HTTPCacheDir), true)
+	gitDir := filepath.Join(cacheDir, resolver.GitCacheDir)
+
+	switch args[0] {
+	case "list":
+		if len(args) > 1 {
+			return usageError("unexpected arguments: %v", args[1:])
+		}
+		return listCache(stdout, httpCache, gitDir)
+	case "clear":
+		if len(args) > 1 {
+			return usageError("unexpected arguments: %v", args[1:])
+		}
+		if err := httpCache.Clear(); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(gitDir); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		if err := os.Remove(filepath.Join(cacheDir, index.FileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		_, _ = fmt.Fprintf(stdout, "Cleared %s\n", cacheDir)
+		return nil
+	case "prune":
+		flags := flag.NewFlagSet("skillet cache prune", flag.ContinueOnError)
+		flags.SetOutput(stderr)
+		olderThan := flags.Duration("older-than", defaultPruneAge, "Remove entries not used within `duration`")
+		if err := flags.Parse(args[1:]); err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+		if flags.NArg() > 0 {
+			return usageError("unexpected arguments: %v", flags.Args())
+		}
+
+		before := time.Now().Add(-*olderThan)
+		removed, err := httpCache.Prune(before)
+		if err != nil {
+			return err
+		}
+		removedGit, err := gitsource.Prune(gitDir, before)
+		if err != nil {
+			return err
+		}
+		removed += removedGit
+		_, _ = fmt.Fprintf(stdout, "Removed %d cached %s\n", removed, pluralize(removed, "entry", "entries"))
+		return nil
+	default:
+		usage()
+		return usageError("unknown cache command: %s", args[0])
+	}
+}
+
+// listCache prints cached URLs and git refs, most recently used first
+func listCache(stdout io.Writer, httpCache *httpcache.Cache, gitDir string) error {
+	entries, err := httpCache.List()
+	if err != nil {
+		return err
+	}
+	refs, err := gitsource.List(gitDir)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 && len(refs) == 0 {
+		_, _ = fmt.Fprintln(stdout, "Cache is empty")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", e.URL, formatSize(e.Size), e.UsedAt.Local().Format(time.DateTime))
+	}
+	for _, r := range refs {
+		src := gitsource.Source{Repo: r.Repo, Ref: r.Ref}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", src, r.Commit[:12], r.UsedAt.Local().Format(time.DateTime))
+	}
+	return tw.Flush()
+}
+
+// formatSize formats a byte count for display
+func formatSize(n int64) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.1fkB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/martinemde/skillet/internal/exitcode"
+	"github.com/martinemde/skillet/internal/install"
+	"github.com/martinemde/skillet/internal/resolver"
+	"github.com/martinemde/skillet/internal/skillpath"
+)
+
+// installFlags parses a subcommand's flags, which may appear before or
+// after its arguments, and returns the arguments
+func installFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, exitcode.Wrap(exitcode.Usage, err)
+	}
+	var positional []string
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return nil, exitcode.Wrap(exitcode.Usage, err)
+		}
+	}
+	return positional, nil
+}
+
+// newInstaller creates an installer for the project, or the user with --user.
+// Checking for updates ignores skillet.lock so it sees the latest content.
+func newInstaller(user, offline, ignoreLock bool) (*install.Installer, error) {
+	res, err := resolver.New()
+	if err != nil {
+		return nil, err
+	}
+	res.Offline = offline
+	if ignoreLock {
+		res.Lock = nil
+	}
+
+	scope := skillpath.ScopeProject
+	if user {
+		scope = skillpath.ScopeUser
+	}
+	return install.New(scope, res)
+}
+
+// runInstall handles the `skillet install <source>` subcommand
+func runInstall(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet install", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		user    = flags.Bool("user", false, "Install into ~/.claude/skills instead of the project")
+		name    = flags.String("name", "", "Install as `[namespace:]name` instead of the skill's own name")
+		force   = flags.Bool("force", false, "Replace an existing skill")
+		offline = flags.Bool("offline", false, "Resolve URLs and git sources only from the cache")
+	)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet install [options] <path|url|git source>")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "Copies a skill into .claude/skills and records where it came from.")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	positional, err := installFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		flags.Usage()
+		return usageError("skill source required")
+	}
+
+	in, err := newInstaller(*user, *offline, false)
+	if err != nil {
+		return err
+	}
+	result, err := in.Install(positional[0], *name, *force)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "Installed %s %s to %s\n", result.Name, result.Installed.Version(), result.Path)
+	return nil
+}
+
+// runUninstall handles the `skillet uninstall <name>...` subcommand
+func runUninstall(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet uninstall", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	user := flags.Bool("user", false, "Uninstall from ~/.claude/skills instead of the project")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet uninstall [options] <[namespace:]name>...")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	names, err := installFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		flags.Usage()
+		return usageError("skill name required")
+	}
+
+	in, err := newInstaller(*user, false, false)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := in.Uninstall(name); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "Uninstalled %s\n", name)
+	}
+	return nil
+}
+
+// runUpdate handles the `skillet update [name...]` subcommand
+func runUpdate(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet update", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		user  = flags.Bool("user", false, "Update skills in ~/.claude/skills instead of the project")
+		force = flags.Bool("force", false, "Overwrite local changes to installed skills")
+	)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet update [options] [[namespace:]name...]")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "Reinstalls installed skills whose source has changed.")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	names, err := installFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	in, err := newInstaller(*user, false, true)
+	if err != nil {
+		return err
+	}
+	statuses, err := in.Check(names...)
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for _, status := range statuses {
+		switch {
+		case status.Err != nil:
+			return fmt.Errorf("failed to check %s: %w", status.Name, status.Err)
+		case !status.Outdated:
+			continue
+		}
+		result, err := in.Update(status.Name, *force)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "Updated %s %s → %s\n", status.Name, status.Installed.Version(), result.Installed.Version())
+		updated++
+	}
+	if updated == 0 {
+		_, _ = fmt.Fprintln(stdout, "All installed skills are up to date")
+	}
+	return nil
+}
+
+// runOutdated handles the `skillet outdated` subcommand
+func runOutdated(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet outdated", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	user := flags.Bool("user", false, "Check skills in ~/.claude/skills instead of the project")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet outdated [options] [[namespace:]name...]")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "Lists installed skills whose source has changed or whose files were edited.")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	names, err := installFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	in, err := newInstaller(*user, false, true)
+	if err != nil {
+		return err
+	}
+	statuses, err := in.Check(names...)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	drifted := 0
+	for _, status := range statuses {
+		var state string
+		switch {
+		case status.Err != nil:
+			state = "error: " + status.Err.Error()
+		case status.Outdated && status.Modified:
+			state = "outdated, modified locally"
+		case status.Outdated:
+			state = "outdated"
+		case status.Modified:
+			state = "modified locally"
+		default:
+			continue
+		}
+		if drifted == 0 {
+			_, _ = fmt.Fprintln(tw, "NAME\tINSTALLED\tLATEST\tSTATUS\tSOURCE")
+		}
+		drifted++
+		latest := status.Latest
+		if latest == "" {
+			latest = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", status.Name, status.Installed.Version(), latest, state, status.Installed.Source)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if drifted == 0 {
+		_, _ = fmt.Fprintln(stdout, "All installed skills are up to date")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/color"
+	"github.com/martinemde/skillet/internal/exitcode"
+	"github.com/martinemde/skillet/internal/lint"
+	"github.com/martinemde/skillet/internal/resourcepath"
+)
+
+// runLint handles the `skillet lint [paths...]` subcommand
+func runLint(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		format    = flags.String("format", lint.FormatText, "Output format: "+strings.Join(lint.Formats, ", "))
+		strict    = flags.Bool("strict", false, "Exit non-zero on warnings as well as errors")
+		colorFlag = flags.String("color", "auto", "Control color output (auto, always, never)")
+	)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet lint [options] [paths...]")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "Lints every discovered skill and command, or the given files and directories.")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	// Allow flags both before and after paths
+	if err := flags.Parse(args); err != nil {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	var paths []string
+	for flags.NArg() > 0 {
+		paths = append(paths, flags.Arg(0))
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+	}
+
+	var targets []lint.Target
+	var err error
+	if len(paths) > 0 {
+		targets, err = lint.TargetsFromPaths(paths)
+	} else {
+		targets, err = lint.DiscoverTargets()
+	}
+	if err != nil {
+		return err
+	}
+
+	diags := lint.Files(targets)
+	for i := range diags {
+		diags[i].Path = lintDisplayPath(diags[i].Path, *format)
+	}
+
+	if err := lint.Write(stdout, *format, diags, len(targets), color.ShouldUseColors(*colorFlag)); err != nil {
+		return err
+	}
+
+	if lint.HasErrors(diags) || (*strict && len(diags) > 0) {
+		return fmt.Errorf("lint found %d %s", len(diags), pluralize(len(diags), "problem", "problems"))
+	}
+	return nil
+}
+
+// lintDisplayPath shortens paths for people in text output, and keeps them
+// relative to the working directory for tools reading JSON or SARIF
+func lintDisplayPath(path, format string) string {
+	if format == lint.FormatText || format == "" {
+		if filepath.IsAbs(path) {
+			return resourcepath.RelativePath(path)
+		}
+		return path
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return abs
+}
+
+func pluralize(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
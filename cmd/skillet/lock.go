@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/martinemde/skillet/internal/exitcode"
+	"github.com/martinemde/skillet/internal/lockfile"
+	"github.com/martinemde/skillet/internal/resolver"
+)
+
+// runLock handles the `skillet lock [sources...]` subcommand
+func runLock(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet lock", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet lock [sources...]")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintf(stderr, "Pins URLs and git sources to their current content in %s.\n", lockfile.FileName)
+		_, _ = fmt.Fprintln(stderr, "Without sources, re-locks every source already in the file.")
+	}
+
+	// Allow flags both before and after sources
+	if err := flags.Parse(args); err != nil {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	var sources []string
+	for flags.NArg() > 0 {
+		sources = append(sources, flags.Arg(0))
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+	}
+
+	res, err := resolver.New()
+	if err != nil {
+		return err
+	}
+	lock := res.Lock
+
+	if len(sources) == 0 {
+		for source := range lock.Skills {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+	}
+	if len(sources) == 0 {
+		flags.Usage()
+		return fmt.Errorf("nothing to lock: %s has no sources", lockfile.FileName)
+	}
+
+	// Resolve current content rather than what's already locked
+	res.Lock = nil
+	for _, source := range sources {
+		if !resolver.IsRemote(source) {
+			return fmt.Errorf("%s is not a URL or git source", source)
+		}
+		result, err := res.Resolve(source)
+		if err != nil {
+			return fmt.Errorf("failed to lock %s: %w", source, err)
+		}
+		result.Cleanup()
+
+		lock.Set(source, lockfile.Entry{Commit: result.Commit, SHA256: result.SHA256})
+		pinned := "sha256:" + result.SHA256[:12]
+		if result.Commit != "" {
+			pinned = "commit " + result.Commit[:12] + ", " + pinned
+		}
+		_, _ = fmt.Fprintf(stdout, "Locked %s (%s)\n", source, pinned)
+	}
+
+	if err := lock.Save(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "Wrote %s\n", lock.Path())
+	return nil
+}
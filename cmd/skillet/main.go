@@ -2,24 +2,34 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/audit"
+	"github.com/martinemde/skillet/internal/budget"
 	"github.com/martinemde/skillet/internal/color"
 	"github.com/martinemde/skillet/internal/command"
 	"github.com/martinemde/skillet/internal/commandpath"
 	"github.com/martinemde/skillet/internal/completion"
+	"github.com/martinemde/skillet/internal/config"
 	"github.com/martinemde/skillet/internal/converter"
 	"github.com/martinemde/skillet/internal/discovery"
 	"github.com/martinemde/skillet/internal/executor"
+	"github.com/martinemde/skillet/internal/exitcode"
 	"github.com/martinemde/skillet/internal/formatter"
+	"github.com/martinemde/skillet/internal/index"
+	"github.com/martinemde/skillet/internal/jsonschema"
 	"github.com/martinemde/skillet/internal/mcpserver"
 	"github.com/martinemde/skillet/internal/promptserver"
 	"github.com/martinemde/skillet/internal/resolver"
@@ -29,26 +39,35 @@ import (
 
 const version = "0.1.0"
 
+// resumePrompt is sent when resuming a session without an explicit --prompt
+const resumePrompt = "Continue where you left off."
+
 // boolFlags contains all flags that don't take a value
 var boolFlags = map[string]bool{
-	"-version":  true,
-	"--version": true,
-	"-help":     true,
-	"--help":    true,
-	"-list":     true,
-	"--list":    true,
-	"-verbose":  true,
-	"--verbose": true,
-	"-debug":    true,
-	"--debug":   true,
-	"-usage":    true,
-	"--usage":   true,
-	"-dry-run":  true,
-	"--dry-run": true,
-	"-q":        true,
-	"--quiet":   true,
-	"-mcp":      true,
-	"--mcp":     true,
+	"-version":   true,
+	"--version":  true,
+	"-help":      true,
+	"--help":     true,
+	"-list":      true,
+	"--list":     true,
+	"-verbose":   true,
+	"--verbose":  true,
+	"-debug":     true,
+	"--debug":    true,
+	"-usage":     true,
+	"--usage":    true,
+	"-dry-run":   true,
+	"--dry-run":  true,
+	"-q":         true,
+	"--quiet":    true,
+	"-mcp":       true,
+	"--mcp":      true,
+	"-continue":  true,
+	"--continue": true,
+	"-offline":   true,
+	"--offline":  true,
+	"-update":    true,
+	"--update":   true,
 }
 
 // optionalValueFlags are flags that can optionally take a value.
@@ -63,7 +82,7 @@ var optionalValueFlags = map[string]string{
 func main() {
 	if err := run(os.Args, os.Stdout, os.Stderr); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitcode.FromError(err))
 	}
 }
 
@@ -133,6 +152,55 @@ func run(args []string, stdout, stderr io.Writer) error {
 		return runCompletion(args[2:], stdout, stderr)
 	}
 
+	// Handle new subcommand before flag parsing
+	if len(args) > 1 && args[1] == "new" {
+		return runNew(args[2:], stdout, stderr)
+	}
+
+	// Handle lint subcommand before flag parsing
+	if len(args) > 1 && args[1] == "lint" {
+		return runLint(args[2:], stdout, stderr)
+	}
+
+	// Handle test subcommand before flag parsing
+	if len(args) > 1 && args[1] == "test" {
+		return runTest(args[2:], stdout, stderr)
+	}
+
+	// Handle lock subcommand before flag parsing
+	if len(args) > 1 && args[1] == "lock" {
+		return runLock(args[2:], stdout, stderr)
+	}
+
+	// Handle package management subcommands before flag parsing
+	if len(args) > 1 {
+		switch args[1] {
+		case "install":
+			return runInstall(args[2:], stdout, stderr)
+		case "uninstall":
+			return runUninstall(args[2:], stdout, stderr)
+		case "update":
+			return runUpdate(args[2:], stdout, stderr)
+		case "outdated":
+			return runOutdated(args[2:], stdout, stderr)
+		}
+	}
+
+	// Handle cache subcommand before flag parsing
+	if len(args) > 1 && args[1] == "cache" {
+		return runCache(args[2:], stdout, stderr)
+	}
+
+	// Handle batch subcommand before flag parsing
+	if len(args) > 1 && args[1] == "batch" {
+		return runBatch(args[2:], stdout, stderr)
+	}
+
+	// Handle pipeline subcommand before flag parsing
+	if len(args) > 1 && args[1] == "pipeline" {
+		return runPipeline(args[2:], stdout, stderr)
+	}
+
 	flags := flag.NewFlagSet(args[0], flag.ExitOnError)
 	flags.SetOutput(stderr)
 
@@ -142,7 +210,7 @@ func run(args []string, stdout, stderr io.Writer) error {
 		listSkills     = flags.Bool("list", false, "List all available skills and commands")
 		verbose        = flags.Bool("verbose", false, "Show detailed output including thinking and tool details")
 		debug          = flags.Bool("debug", false, "Print raw JSON stream to stderr")
-		showUsage      = flags.Bool("usage", false, "Show token usage statistics")
+		showUsage      = flags.Bool("usage", false, "Show token usage and cost statistics")
 		dryRun         = flags.Bool("dry-run", false, "Show the command that would be executed without running it")
 		quiet          = flags.Bool("q", false, "Quiet mode - suppress all output except errors")
 		parseInput     = flags.String("parse", "", "Parse and format stream-json input (file path or - for stdin)")
@@ -151,7 +219,17 @@ func run(args []string, stdout, stderr io.Writer) error {
 		allowedTools   = flags.String("allowed-tools", "", "Override allowed tools (overrides SKILL.md setting)")
 		permissionMode = flags.String("permission-mode", "", "Override permission mode (default: acceptEdits)")
 		outputFormat   = flags.String("output-format", "", "Override output format (default: stream-json)")
+		format         = flags.String("format", "", "Formatted output: json or ndjson (default: terminal)")
+		jsonSchema     = flags.String("json-schema", "", "JSON Schema for structured output (inline JSON or file path)")
 		taskList       = flags.String("task-list", "", "Task list ID to use (sets CLAUDE_CODE_TASK_LIST_ID)")
+		resume         = flags.String("resume", "", "Resume a previous Claude session by ID")
+		continueLast   = flags.Bool("continue", false, "Continue the most recent Claude session in this directory")
+		backendName    = flags.String("backend", "", "Agent backend: claude, replay:<file>, or a backend from the config file")
+		auditLogPath   = flags.String("audit-log", "", "Append a JSONL audit log of the run to this file")
+		maxCost        = flags.Float64("max-cost", 0, "Stop the run when its estimated cost passes this many USD")
+		maxTurns       = flags.Int("max-turns", 0, "Stop the run after this many turns")
+		offline        = flags.Bool("offline", false, "Resolve URLs and git sources only from the cache")
+		update         = flags.Bool("update", false, "Accept and record remote skills that changed since skillet.lock")
 		colorFlag      = flags.String("color", "auto", "Control color output (auto, always, never)")
 		completePrefix = flags.String("complete", "", "Output completion names matching prefix (for shell completion)")
 		convertToSkill = flags.String("convert-to-skill", "", "Convert command to skill (optionally specify output path)")
@@ -161,23 +239,33 @@ func run(args []string, stdout, stderr io.Writer) error {
 	flags.BoolVar(quiet, "quiet", false, "Quiet mode - suppress all output except errors")
 	// Add alias for --prompt
 	flags.StringVar(prompt, "prompt", "", "Prompt to pass to Claude (required if no skill provided)")
+	// Read by applyConfigDefaults
+	flags.String("profile", "", "Apply a named profile from the config files")
+
+	// Pull out skill arguments given as --name=value before parsing skillet's own flags
+	remaining, namedArgs := separateArgumentFlags(args[1:], flags)
 
 	// Separate flags from positional arguments to support flags in any position
-	flagArgs, posArgs := separateFlags(args[1:])
+	flagArgs, posArgs := separateFlags(remaining)
 
 	if err := flags.Parse(flagArgs); err != nil {
 		return err
 	}
 
-	// Configure global color profile early, before any rendering
-	color.ConfigureColorProfile(*colorFlag)
-
 	if *showVersion {
 		_, _ = fmt.Fprintf(stdout, "skillet version %s\n", version)
 		return nil
 	}
 
-	if *showHelp {
+	// Fill in flags that weren't given from the config files and environment
+	if err := applyConfigDefaults(flags); err != nil {
+		return err
+	}
+
+	// Configure global color profile early, before any rendering
+	color.ConfigureColorProfile(*colorFlag)
+
+	if *showHelp && len(posArgs) == 0 {
 		printHelp(stdout, *colorFlag)
 		return nil
 	}
@@ -192,9 +280,13 @@ func run(args []string, stdout, stderr io.Writer) error {
 		return nil
 	}
 
+	if *format != "" && !formatter.IsJSONFormat(*format) {
+		return usageError("invalid --format %q (must be %s or %s)", *format, formatter.FormatJSON, formatter.FormatNDJSON)
+	}
+
 	// Handle --parse mode: format stream-json input without running claude
 	if *parseInput != "" {
-		return runParseMode(*parseInput, stdout, *verbose, *debug, *showUsage, *colorFlag, *quiet)
+		return runParseMode(*parseInput, stdout, *verbose, *debug, *showUsage, *colorFlag, *quiet, *format)
 	}
 
 	// Parse skill or command if provided
@@ -203,41 +295,50 @@ func run(args []string, stdout, stderr io.Writer) error {
 	var resourceName string
 	var resourcePath string
 	if len(posArgs) > 0 {
-		result, err := resolver.Resolve(posArgs[0])
+		res, err := resolver.New()
 		if err != nil {
-			return fmt.Errorf("failed to resolve skill or command: %w", err)
+			return err
 		}
-		if result.IsURL {
-			defer func() { _ = os.Remove(result.Path) }()
+		res.Offline = *offline
+		res.Update = *update
+		result, err := res.Resolve(posArgs[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve skill or command: %w", err)
 		}
+		defer result.Cleanup()
 
 		resourcePath = result.Path
 
 		// Arguments are everything after the skill/command name
-		arguments := strings.Join(posArgs[1:], " ")
+		input := arguments.Input{Positional: posArgs[1:], Named: namedArgs}
 
 		switch result.Type {
 		case resolver.ResourceTypeSkill:
-			if result.BaseURL != "" {
-				parsedSkill, err = skill.ParseWithBaseDir(result.Path, result.BaseURL, arguments)
-			} else {
-				parsedSkill, err = skill.Parse(result.Path, arguments)
-			}
+			parsedSkill, err = skill.ParseWithInput(result.Path, result.BaseURL, input)
 			if err != nil {
-				return fmt.Errorf("failed to parse skill file: %w", err)
+				return argumentError(err, "failed to parse skill file", *showHelp, stdout, stderr)
 			}
 			resourceName = parsedSkill.Name
 		case resolver.ResourceTypeCommand:
-			if result.BaseURL != "" {
-				cmd, err = command.ParseWithBaseDir(result.Path, result.BaseURL, arguments)
-			} else {
-				cmd, err = command.Parse(result.Path, arguments)
-			}
+			cmd, err = command.ParseWithInput(result.Path, result.BaseURL, input)
 			if err != nil {
-				return fmt.Errorf("failed to parse command file: %w", err)
+				return argumentError(err, "failed to parse command file", *showHelp, stdout, stderr)
 			}
 			resourceName = cmd.Name
 		}
+
+		// Show the skill's own usage for `skillet <skill> --help`
+		if *showHelp {
+			_, _ = fmt.Fprint(stdout, resourceUsage(parsedSkill, cmd))
+			return nil
+		}
+	} else if len(namedArgs) > 0 {
+		names := make([]string, 0, len(namedArgs))
+		for name := range namedArgs {
+			names = append(names, "--"+name)
+		}
+		sort.Strings(names)
+		return usageError("unknown flag: %s", strings.Join(names, ", "))
 	}
 
 	// Handle --convert-to-skill mode
@@ -252,16 +353,40 @@ func run(args []string, stdout, stderr io.Writer) error {
 		return runConvertToSkill(stdout, resourcePath, *convertToSkill, *model, *allowedTools, *forceConvert, *colorFlag)
 	}
 
+	// Resuming a session doesn't need a skill or prompt to continue from
+	resuming := *resume != "" || *continueLast
+
+	if *format != "" && *outputFormat != "" {
+		return usageError("--format and --output-format cannot be used together")
+	}
+	if *jsonSchema != "" && *outputFormat != "" {
+		return usageError("--json-schema and --output-format cannot be used together")
+	}
+
 	// Require --prompt when no skill/command is provided
-	if parsedSkill == nil && cmd == nil && *prompt == "" {
+	if parsedSkill == nil && cmd == nil && *prompt == "" && !resuming {
 		printHelp(stdout, *colorFlag)
 		return nil
 	}
 
+	schema, err := resolveOutputSchema(*jsonSchema, parsedSkill)
+	if err != nil {
+		return err
+	}
+	limits, err := flagLimits(*maxCost, *maxTurns)
+	if err != nil {
+		return err
+	}
+	limits = resourceLimits(limits, parsedSkill, cmd)
+	var schemaArg string
+	if schema != nil {
+		schemaArg = schema.String()
+	}
+
 	// Get skillet path for MCP permission prompts
 	skilletPath, _ := os.Executable()
 
-	// Start prompt server for handling AskUserQuestion from MCP
+	// Start prompt server for handling AskUserQuestion and permission approval from MCP
 	promptSrv, err := promptserver.New()
 	if err != nil {
 		return fmt.Errorf("failed to create prompt server: %w", err)
@@ -269,7 +394,7 @@ func run(args []string, stdout, stderr io.Writer) error {
 
 	// Build executor config with resolved values
 	config := executor.Config{
-		Prompt:           resolvePromptFromResource(*prompt, parsedSkill, cmd),
+		Prompt:           resolvePrompt(*prompt, parsedSkill, cmd, resuming),
 		SystemPrompt:     buildSystemPromptFromResource(parsedSkill, cmd),
 		Model:            resolveString(*model, resourceModel(parsedSkill, cmd)),
 		AllowedTools:     resolveString(*allowedTools, resourceAllowedTools(parsedSkill, cmd)),
@@ -278,13 +403,48 @@ func run(args []string, stdout, stderr io.Writer) error {
 		SkilletPath:      skilletPath,
 		PromptSocketPath: promptSrv.SocketPath(),
 		TaskListID:       resolveTaskListID(*taskList),
+		Resume:           *resume,
+		Continue:         *continueLast,
+		JSONSchema:       schemaArg,
+		MaxTurns:         limits.MaxTurns,
+	}
+
+	auditLog, err := resolveAuditLog(*auditLogPath)
+	if err != nil {
+		return err
 	}
+	config.Env = auditLog.Env()
+
+	// Set up context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	// Create pipe for output
 	pr, pw := io.Pipe()
 
-	// Create executor
-	exec := executor.New(config, pw, stderr)
+	// The audit log reads its own copy of the stream
+	var agentOutput io.Writer = pw
+	auditReader, auditWriter := io.Pipe()
+	if auditLog != nil {
+		agentOutput = io.MultiWriter(pw, auditWriter)
+	}
+
+	// The budget watcher stops the agent when it goes over a limit
+	var watcher *budget.Watcher
+	if limits.Enabled() {
+		watcher = budget.NewWatcher(limits, cancel)
+		agentOutput = io.MultiWriter(agentOutput, watcher)
+	}
+
+	// Create executor for the selected backend
+	backend, err := resolveBackend(*backendName)
+	if err != nil {
+		return err
+	}
+	exec, err := executor.New(backend, config, agentOutput, stderr)
+	if err != nil {
+		return err
+	}
 
 	// Handle dry-run
 	if *dryRun {
@@ -292,11 +452,25 @@ func run(args []string, stdout, stderr io.Writer) error {
 		return nil
 	}
 
+	if err := auditLog.Start(audit.Run{
+		Skill:   resourceName,
+		Path:    resourcePath,
+		Backend: backend.Name,
+		Argv:    exec.Argv(),
+	}); err != nil {
+		return err
+	}
+
 	// Create formatter
 	// In quiet mode, discard all output (only program errors go to stderr)
+	// With an output schema, the validated JSON still goes to stdout in quiet mode
 	output := stdout
+	progress := stderr
 	if *quiet {
-		output = io.Discard
+		progress = io.Discard
+		if schema == nil || formatter.IsJSONFormat(*format) {
+			output = io.Discard
+		}
 	}
 
 	// If user explicitly set --output-format, we're in passthrough mode
@@ -309,12 +483,11 @@ func run(args []string, stdout, stderr io.Writer) error {
 		SkillName:       resourceName,
 		SkillPath:       resourcePath,
 		Color:           *colorFlag,
+		Format:          *format,
+		Schema:          schema,
+		Progress:        progress,
 	})
 
-	// Set up context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	// Start prompt server for IPC with MCP child processes
 	if err := promptSrv.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start prompt server: %w", err)
@@ -337,10 +510,25 @@ func run(args []string, stdout, stderr io.Writer) error {
 		errChan <- form.Format(pr)
 	}()
 
+	// Record tool calls in the audit log as they complete
+	type auditOutcome struct {
+		summary *formatter.Summary
+		err     error
+	}
+	auditChan := make(chan auditOutcome, 1)
+	if auditLog != nil {
+		go func() {
+			summary, err := auditLog.Stream(auditReader, resourceName, resourcePath)
+			_, _ = io.Copy(io.Discard, auditReader) // Keep the agent unblocked after a stream error
+			auditChan <- auditOutcome{summary, err}
+		}()
+	}
+
 	// Run executor
 	go func() {
 		err := exec.Execute(ctx)
 		_ = pw.Close() // Close the writer when execution is done
+		_ = auditWriter.Close()
 		errChan <- err
 	}()
 
@@ -357,18 +545,105 @@ func run(args []string, stdout, stderr io.Writer) error {
 		}
 	}
 
-	if execErr != nil {
-		return fmt.Errorf("execution failed: %w", execErr)
+	// A limit, a signal, or a failed result explains the failure better than
+	// the agent's exit status
+	var runErr error
+	var resultErr *formatter.ResultError
+	switch {
+	case watcher.Err() != nil:
+		runErr = watcher.Err()
+	case ctx.Err() != nil:
+		runErr = exitcode.ErrInterrupted
+	case errors.As(execErr, &resultErr), errors.As(formatErr, &resultErr):
+		runErr = resultErr
+	case execErr != nil:
+		runErr = fmt.Errorf("execution failed: %w", execErr)
+	case formatErr != nil:
+		runErr = fmt.Errorf("formatting failed: %w", formatErr)
 	}
-	if formatErr != nil {
-		return fmt.Errorf("formatting failed: %w", formatErr)
+
+	if auditLog != nil {
+		outcome := <-auditChan
+		if err := auditLog.Finish(outcome.summary, runErr); err != nil {
+			return err
+		}
+		if outcome.err != nil {
+			return outcome.err
+		}
 	}
 
-	return nil
+	return runErr
+}
+
+// separateArgumentFlags removes --name=value arguments that aren't skillet flags.
+// These are named skill arguments; a bare --name is treated as --name=true.
+// Returns (remaining args, named arguments).
+func separateArgumentFlags(args []string, flags *flag.FlagSet) ([]string, map[string]string) {
+	remaining := make([]string, 0, len(args))
+	named := make(map[string]string)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			remaining = append(remaining, arg)
+			continue
+		}
+
+		name, value, hasEquals := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if flags.Lookup(name) != nil {
+			remaining = append(remaining, arg)
+			// Keep a known flag's separate value with it so it isn't mistaken for an argument
+			_, isOptional := optionalValueFlags[arg]
+			if !hasEquals && !boolFlags[arg] && !isOptional && i+1 < len(args) {
+				i++
+				remaining = append(remaining, args[i])
+			}
+			continue
+		}
+
+		if !hasEquals {
+			value = "true"
+		}
+		named[name] = value
+	}
+
+	return remaining, named
+}
+
+// argumentError reports a skill or command parse failure. Invalid arguments
+// also print the resource's usage, to stdout when it was requested with --help.
+func argumentError(err error, context string, showHelp bool, stdout, stderr io.Writer) error {
+	var argErr *arguments.Error
+	if !errors.As(err, &argErr) {
+		return exitcode.Wrap(exitcode.InvalidResource, fmt.Errorf("%s: %w", context, err))
+	}
+	if showHelp {
+		_, _ = fmt.Fprint(stdout, argErr.Usage)
+		return nil
+	}
+	_, _ = fmt.Fprint(stderr, argErr.Usage)
+	return argErr
+}
+
+// usageError is an error in how skillet was invoked, which exits with
+// exitcode.Usage
+func usageError(format string, args ...any) error {
+	return exitcode.Wrap(exitcode.Usage, fmt.Errorf(format, args...))
+}
+
+// resourceUsage returns the usage message for a skill or command
+func resourceUsage(s *skill.Skill, c *command.Command) string {
+	if s != nil {
+		return s.Usage()
+	}
+	if c != nil {
+		return c.Usage()
+	}
+	return ""
 }
 
 // runParseMode formats stream-json input from a file or stdin
-func runParseMode(input string, stdout io.Writer, verbose, debug, showUsage bool, colorMode string, quiet bool) error {
+func runParseMode(input string, stdout io.Writer, verbose, debug, showUsage bool, colorMode string, quiet bool, format string) error {
 	var reader io.Reader
 
 	if input == "-" {
@@ -394,6 +669,7 @@ func runParseMode(input string, stdout io.Writer, verbose, debug, showUsage bool
 		Debug:     debug,
 		ShowUsage: showUsage,
 		Color:     colorMode,
+		Format:    format,
 	})
 
 	return form.Format(reader)
@@ -405,7 +681,7 @@ func runCompletion(args []string, stdout, stderr io.Writer) error {
 		shells := completion.SupportedShells()
 		_, _ = fmt.Fprintf(stderr, "Usage: skillet completion <shell>\n")
 		_, _ = fmt.Fprintf(stderr, "Supported shells: %s\n", strings.Join(shells, ", "))
-		return fmt.Errorf("shell argument required")
+		return usageError("shell argument required")
 	}
 
 	shell := args[0]
@@ -475,8 +751,18 @@ func printHelp(w io.Writer, colorMode string) {
 
 	usage := lipgloss.JoinVertical(lipgloss.Left,
 		sectionStyle.Render("Usage:"),
-		"  skillet [options] <skill-path>",
+		"  skillet [options] <skill-path> [arguments...] [--name=value...]",
 		"  skillet --prompt <prompt> [options]",
+		"  skillet <skill-path> --help",
+		"  skillet new [--user] [--template <name>] <[namespace:]name>",
+		"  skillet lint [--format text|json|sarif] [paths...]",
+		"  skillet test [--live|--record] [--junit <file>] <skill>",
+		"  skillet batch [-j <n>] [--log-dir <dir>] <skill> --inputs <file|glob>",
+		"  skillet pipeline run [options] [pipeline.yaml]",
+		"  skillet install [--user] [--name <[namespace:]name>] <path|url|git source>",
+		"  skillet uninstall|update|outdated [--user] [names...]",
+		"  skillet lock [sources...]",
+		"  skillet cache list|clear|prune [--older-than <duration>]",
 	)
 
 	description := lipgloss.JoinVertical(lipgloss.Left,
@@ -493,6 +779,8 @@ func printHelp(w io.Writer, colorMode string) {
 		"  • A skill name in .claude/skills/ "+codeStyle.Render("(e.g., skill-name)"),
 		"  • A command name in .claude/commands/ "+codeStyle.Render("(e.g., command-name)"),
 		"  • A URL to a skill/command file "+codeStyle.Render("(e.g., https://example.com/skill.md)"),
+		"  • A URL to a skill bundle "+codeStyle.Render("(e.g., https://example.com/skill.tar.gz#path/to/skill)"),
+		"  • A git source "+codeStyle.Render("(e.g., github:owner/repo/path/to/skill@v1)"),
 	)
 
 	options := lipgloss.JoinVertical(lipgloss.Left,
@@ -502,7 +790,7 @@ func printHelp(w io.Writer, colorMode string) {
 		fmt.Sprintf("  %s              List available skills and commands", optionStyle.Render("--list")),
 		fmt.Sprintf("  %s           Show detailed output with thinking and tool details", optionStyle.Render("--verbose")),
 		fmt.Sprintf("  %s             Print raw JSON stream to stderr (for debugging)", optionStyle.Render("--debug")),
-		fmt.Sprintf("  %s             Show token usage statistics after execution", optionStyle.Render("--usage")),
+		fmt.Sprintf("  %s             Show token usage and cost after execution", optionStyle.Render("--usage")),
 		fmt.Sprintf("  %s           Show the command without running it", optionStyle.Render("--dry-run")),
 		fmt.Sprintf("  %s, %s         Suppress all output except errors", optionStyle.Render("-q"), optionStyle.Render("--quiet")),
 		fmt.Sprintf("  %s             Format stream-json input (file or - for stdin)", optionStyle.Render("--parse")),
@@ -511,12 +799,33 @@ func printHelp(w io.Writer, colorMode string) {
 		fmt.Sprintf("  %s     Allowed tools (overrides skill setting)", optionStyle.Render("--allowed-tools")),
 		fmt.Sprintf("  %s   Permission mode (default: acceptEdits)", optionStyle.Render("--permission-mode")),
 		fmt.Sprintf("  %s         Task list ID (sets CLAUDE_CODE_TASK_LIST_ID)", optionStyle.Render("--task-list")),
+		fmt.Sprintf("  %s            Resume a previous session by ID", optionStyle.Render("--resume")),
+		fmt.Sprintf("  %s          Continue the most recent session", optionStyle.Render("--continue")),
+		fmt.Sprintf("  %s           Agent backend: claude, replay:<file>, or configured", optionStyle.Render("--backend")),
+		fmt.Sprintf("  %s         Append a JSONL audit log of the run to a file", optionStyle.Render("--audit-log")),
+		fmt.Sprintf("  %s          Stop the run past this estimated cost in USD", optionStyle.Render("--max-cost")),
+		fmt.Sprintf("  %s         Stop the run after this many turns", optionStyle.Render("--max-turns")),
+		fmt.Sprintf("  %s           Run URLs and git sources only from the cache", optionStyle.Render("--offline")),
+		fmt.Sprintf("  %s            Accept remote skills that changed since skillet.lock", optionStyle.Render("--update")),
+		fmt.Sprintf("  %s            Normalized output: json or ndjson", optionStyle.Render("--format")),
+		fmt.Sprintf("  %s       Validate result against a JSON Schema (JSON or file)", optionStyle.Render("--json-schema")),
 		fmt.Sprintf("  %s     Output format (overrides pretty formatting)", optionStyle.Render("--output-format")),
 		fmt.Sprintf("  %s             Color output: auto, always, never", optionStyle.Render("--color")),
+		fmt.Sprintf("  %s           Apply a named profile from the config files", optionStyle.Render("--profile")),
 		fmt.Sprintf("  %s  Convert command to skill (optional: output path)", optionStyle.Render("--convert-to-skill")),
 		fmt.Sprintf("  %s             Overwrite existing skill when converting", optionStyle.Render("--force")),
 	)
 
+	exitCodes := lipgloss.JoinVertical(lipgloss.Left,
+		sectionStyle.Render("Exit Codes:"),
+		"  0    Success                     5    The run ended with an error result",
+		"  1    Other failure               6    The run reached its turn limit",
+		"  2    Invalid flags or arguments  7    The run reached its budget",
+		"  3    Skill or command not found  8    Result didn't match the output schema",
+		"  4    Invalid skill or command    127  Claude CLI not installed",
+		"                                   130  Interrupted",
+	)
+
 	// Render examples with markdown
 	examplesBlock := `~~~sh
 # Run a skill by exact path
@@ -531,6 +840,40 @@ skillet skill-name
 # Run a skill from a URL
 skillet https://raw.githubusercontent.com/user/repo/main/skill.md
 
+# Pass named arguments declared in the skill's frontmatter
+skillet review main.go --mode=thorough
+
+# Show the arguments a skill accepts
+skillet review --help
+
+# Create a new skill in .claude/skills from a template
+skillet new my-skill --template script
+
+# Run a skill over every matching file, four at a time
+skillet batch summarize-transcript --inputs 'transcripts/*.txt' -j 4
+
+# Run the skills in pipeline.yaml, handing each result to the next step
+skillet pipeline run
+
+# Use the ci profile's model, permission mode, and audit log
+skillet review --profile ci
+
+# Check all skills and commands for problems
+skillet lint
+
+# Run a skill's tests/ cases, replaying recorded sessions
+skillet test skill-name --junit report.xml
+
+# Run a previously downloaded skill without the network
+skillet --offline github:owner/repo/skill@v1
+
+# Install a skill into .claude/skills and check it for updates later
+skillet install github:owner/repo/skill@main
+skillet outdated
+
+# Pin a remote skill's content in skillet.lock
+skillet lock github:owner/repo/skill@v1
+
 # Run with a custom prompt (with skill)
 skillet --prompt "Analyze this code" skill-name
 
@@ -542,6 +885,18 @@ skillet --dry-run skill-name
 
 # Show verbose output and usage statistics
 skillet --verbose --usage skill-name
+
+# Stop a run that passes $2 or 30 turns
+skillet --max-cost 2 --max-turns 30 skill-name
+
+# Emit normalized JSON events for scripts
+skillet --format=ndjson skill-name
+
+# Print only the result as JSON matching a schema
+skillet --json-schema schema.json skill-name | jq .
+
+# Resume a skill run that stopped halfway
+skillet --resume <session-id> skill-name
 ~~~`
 
 	examples := lipgloss.JoinVertical(lipgloss.Left,
@@ -582,6 +937,7 @@ Your skill instructions go here...
 		usage,
 		description,
 		options,
+		exitCodes,
 		examples,
 		skillFormat,
 		footer,
@@ -599,7 +955,14 @@ func listAvailable(w io.Writer, colorMode string) error {
 		return fmt.Errorf("failed to initialize skill path: %w", err)
 	}
 
-	skillDisc := discovery.New(skillPath)
+	// Discovery and frontmatter parsing go through the index
+	ix, err := index.Default()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ix.Save() }()
+
+	skillDisc := discovery.NewWithFinder(skillPath, ix.SkillFinder())
 	skills, err := skillDisc.Discover()
 	if err != nil {
 		return fmt.Errorf("failed to discover skills: %w", err)
@@ -611,7 +974,7 @@ func listAvailable(w io.Writer, colorMode string) error {
 		return fmt.Errorf("failed to initialize command path: %w", err)
 	}
 
-	cmdDisc := command.NewDiscoverer(cmdPath)
+	cmdDisc := command.NewDiscovererWithFinder(cmdPath, ix.CommandFinder())
 	commands, err := cmdDisc.Discover()
 	if err != nil {
 		return fmt.Errorf("failed to discover commands: %w", err)
@@ -628,6 +991,7 @@ func listAvailable(w io.Writer, colorMode string) error {
 	notInvocableNameStyle := lipgloss.NewStyle()
 	notInvocablePathStyle := lipgloss.NewStyle()
 	notInvocableLabelStyle := lipgloss.NewStyle()
+	invalidLabelStyle := lipgloss.NewStyle()
 	noItemsStyle := lipgloss.NewStyle().Italic(true)
 	namespaceStyle := lipgloss.NewStyle().Italic(true)
 
@@ -651,7 +1015,8 @@ func listAvailable(w io.Writer, colorMode string) error {
 		notInvocableLabelStyle = notInvocableLabelStyle.
 			Foreground(lipgloss.Color("8")).
 			Italic(true)
-		noItemsStyle = noItemsStyle.Foreground(lipgloss.Color("8")) // Dim
+		invalidLabelStyle = invalidLabelStyle.Foreground(lipgloss.Color("1")) // Red
+		noItemsStyle = noItemsStyle.Foreground(lipgloss.Color("8"))           // Dim
 		namespaceStyle = namespaceStyle.Foreground(lipgloss.Color("8"))
 	}
 
@@ -665,6 +1030,7 @@ func listAvailable(w io.Writer, colorMode string) error {
 		notInvocableNameStyle:  notInvocableNameStyle,
 		notInvocablePathStyle:  notInvocablePathStyle,
 		notInvocableLabelStyle: notInvocableLabelStyle,
+		invalidLabelStyle:      invalidLabelStyle,
 		namespaceStyle:         namespaceStyle,
 	}
 
@@ -691,10 +1057,9 @@ func listAvailable(w io.Writer, colorMode string) error {
 				Path:         discovery.RelativePath(s),
 				Overshadowed: s.Overshadowed,
 			}
-			// Parse skill to check user-invocable status (ignore errors, default to invocable)
-			if parsed, err := skill.Parse(s.Path, ""); err == nil {
-				item.NotUserInvocable = !parsed.IsUserInvocable()
-			}
+			summary := ix.SkillSummary(s.Path)
+			item.NotUserInvocable = summary.NotUserInvocable
+			item.Invalid = summary.Invalid
 			skillItems[i] = item
 		}
 		lines = append(lines, formatResourceList(skillItems, styles)...)
@@ -720,6 +1085,7 @@ func listAvailable(w io.Writer, colorMode string) error {
 				SourceName:   c.Source.Name,
 				Path:         command.RelativePath(c),
 				Overshadowed: c.Overshadowed,
+				Invalid:      ix.CommandSummary(c.Path).Invalid,
 			}
 		}
 		lines = append(lines, formatResourceList(cmdItems, styles)...)
@@ -738,6 +1104,7 @@ type listableItem struct {
 	Path             string
 	Overshadowed     bool
 	NotUserInvocable bool // user-invocable: false in frontmatter
+	Invalid          bool // Fails to parse; see skillet lint
 }
 
 // listStyles holds the lipgloss styles for resource listing
@@ -750,6 +1117,7 @@ type listStyles struct {
 	notInvocableNameStyle  lipgloss.Style
 	notInvocablePathStyle  lipgloss.Style
 	notInvocableLabelStyle lipgloss.Style
+	invalidLabelStyle      lipgloss.Style
 	namespaceStyle         lipgloss.Style
 }
 
@@ -790,7 +1158,11 @@ func formatResourceList(items []listableItem, styles listStyles) []string {
 		} else {
 			name := styles.nameStyle.Render(item.Name) + " " + styles.namespaceStyle.Render("("+sourceInfo+")")
 			path := styles.pathStyle.Render(item.Path)
-			lines = append(lines, fmt.Sprintf("  %s%s  %s", name, padding, path))
+			label := ""
+			if item.Invalid {
+				label = styles.invalidLabelStyle.Render(" (invalid: run skillet lint)")
+			}
+			lines = append(lines, fmt.Sprintf("  %s%s  %s%s", name, padding, path, label))
 		}
 	}
 	return lines
@@ -826,6 +1198,48 @@ func resourceAllowedTools(s *skill.Skill, c *command.Command) string {
 	return ""
 }
 
+// resourceLimits fills in limits not set by flags from the skill or command
+func resourceLimits(limits budget.Limits, s *skill.Skill, c *command.Command) budget.Limits {
+	if s != nil {
+		if limits.MaxCost == 0 {
+			limits.MaxCost = s.MaxCost
+		}
+		if limits.MaxTurns == 0 {
+			limits.MaxTurns = s.MaxTurns
+		}
+	}
+	if c != nil {
+		if limits.MaxCost == 0 {
+			limits.MaxCost = c.MaxCost
+		}
+		if limits.MaxTurns == 0 {
+			limits.MaxTurns = c.MaxTurns
+		}
+	}
+	return limits
+}
+
+// flagLimits returns the limits set by --max-cost and --max-turns
+func flagLimits(maxCost float64, maxTurns int) (budget.Limits, error) {
+	if maxCost < 0 {
+		return budget.Limits{}, usageError("--max-cost must not be negative")
+	}
+	if maxTurns < 0 {
+		return budget.Limits{}, usageError("--max-turns must not be negative")
+	}
+	return budget.Limits{MaxCost: maxCost, MaxTurns: maxTurns}, nil
+}
+
+// resolvePrompt returns the prompt to send to Claude. When resuming a session
+// without an explicit prompt, a continuation prompt is used instead of the
+// resource description so the previous conversation picks up where it stopped.
+func resolvePrompt(cliPrompt string, s *skill.Skill, c *command.Command, resuming bool) string {
+	if cliPrompt == "" && resuming {
+		return resumePrompt
+	}
+	return resolvePromptFromResource(cliPrompt, s, c)
+}
+
 func resolvePromptFromResource(cliPrompt string, s *skill.Skill, c *command.Command) string {
 	if cliPrompt != "" {
 		return cliPrompt
@@ -839,6 +1253,103 @@ func resolvePromptFromResource(cliPrompt string, s *skill.Skill, c *command.Comm
 	return ""
 }
 
+// resolveOutputSchema compiles the schema for structured output.
+// The --json-schema flag (inline JSON or a file path) overrides the skill's output-schema.
+func resolveOutputSchema(flagValue string, s *skill.Skill) (*jsonschema.Schema, error) {
+	var data []byte
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(flagValue), "{"):
+		data = []byte(flagValue)
+	case flagValue != "":
+		content, err := os.ReadFile(flagValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --json-schema: %w", err)
+		}
+		data = content
+	case s != nil:
+		content, err := s.OutputSchemaJSON()
+		if err != nil {
+			return nil, err
+		}
+		data = content
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	schema, err := jsonschema.Compile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load output schema: %w", err)
+	}
+	return schema, nil
+}
+
+// resolveBackend selects the agent backend from the --backend flag, falling
+// back to the config file's default backend and then the Claude CLI
+func resolveBackend(name string) (executor.Backend, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return executor.Backend{}, err
+	}
+	if name == "" {
+		name = cfg.Backend
+	}
+	return executor.SelectBackend(name, cfg.Backends)
+}
+
+// resolveAuditLog opens the audit log from the --audit-log flag or the
+// config file's audit-log. It returns nil when auditing is off.
+func resolveAuditLog(path string) (*audit.Log, error) {
+	if path == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, err
+		}
+		path = cfg.AuditLog
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return audit.New(path)
+}
+
+// applyConfigDefaults sets flags that weren't given on the command line
+// from the layered config: config files, the --profile, then SKILLET_* variables
+func applyConfigDefaults(flags *flag.FlagSet) error {
+	if f := flags.Lookup("profile"); f != nil {
+		config.SetProfile(f.Value.String())
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	defaults := map[string]string{
+		"color":           cfg.Color,
+		"model":           cfg.Model,
+		"permission-mode": cfg.PermissionMode,
+	}
+	if cfg.Verbose != nil {
+		defaults["verbose"] = strconv.FormatBool(*cfg.Verbose)
+	}
+	if cfg.Usage != nil {
+		defaults["usage"] = strconv.FormatBool(*cfg.Usage)
+	}
+	for name, value := range defaults {
+		if value == "" || set[name] || flags.Lookup(name) == nil {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("invalid %s %q in config: %w", name, value, err)
+		}
+	}
+	return nil
+}
+
 func resolveString(override, fallback string) string {
 	if override != "" {
 		return override
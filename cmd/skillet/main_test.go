@@ -2,10 +2,18 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/martinemde/skillet/internal/exitcode"
 )
 
 func TestRun_Version(t *testing.T) {
@@ -825,3 +833,801 @@ func TestRun_ConvertToSkill_WithOverrides(t *testing.T) {
 		t.Errorf("Skill should have allowed-tools: Bash,Read, got: %s", string(content))
 	}
 }
+
+func TestRun_DryRunWithResume(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--dry-run", "--resume", "abc-123", "../../testdata/simple-skill/SKILL.md"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "--resume abc-123") {
+		t.Errorf("Dry-run should include --resume, got: %s", output)
+	}
+	if !strings.Contains(output, resumePrompt) {
+		t.Errorf("Dry-run should use the resume prompt, got: %s", output)
+	}
+}
+
+func TestRun_DryRunContinueWithoutSkill(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--continue", "--dry-run"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "Would execute:") {
+		t.Errorf("--continue should not require a skill or prompt, got: %s", output)
+	}
+	if !strings.Contains(output, "--continue") {
+		t.Errorf("Dry-run should include --continue, got: %s", output)
+	}
+}
+
+func TestSeparateFlags_ContinueFlag(t *testing.T) {
+	flagArgs, posArgs := separateFlags([]string{"--continue", "skill-name"})
+
+	if len(flagArgs) != 1 || flagArgs[0] != "--continue" {
+		t.Errorf("Expected [--continue], got %v", flagArgs)
+	}
+	if len(posArgs) != 1 || posArgs[0] != "skill-name" {
+		t.Errorf("Expected [skill-name], got %v", posArgs)
+	}
+}
+
+func TestRun_ParseWithJSONFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--parse", "../../testdata/parse/tool-operations.jsonl", "--format=ndjson"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, `"type":"tool"`) || !strings.Contains(output, `"target":"Print hello"`) {
+		t.Errorf("Output should contain normalized tool events, got: %s", output)
+	}
+	if strings.Contains(output, "Starting") {
+		t.Errorf("JSON output should not contain terminal formatting, got: %s", output)
+	}
+}
+
+func TestRun_InvalidFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--format=xml", "--dry-run", "../../testdata/simple-skill/SKILL.md"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "invalid --format") {
+		t.Errorf("Expected invalid --format error, got: %v", err)
+	}
+}
+
+func TestRun_FormatWithOutputFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--format=json", "--output-format=json", "--dry-run", "../../testdata/simple-skill/SKILL.md"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("Expected conflicting format error, got: %v", err)
+	}
+}
+
+func TestRun_DryRunWithSkillOutputSchema(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--dry-run", "../../testdata/output-schema-skill"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, `--json-schema {"properties":{"severity"`) {
+		t.Errorf("Dry-run should pass the skill's output schema, got: %s", output)
+	}
+}
+
+func TestRun_DryRunJSONSchemaFlagOverridesSkill(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--dry-run", "--json-schema", `{"type":"array"}`, "../../testdata/output-schema-skill"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, `--json-schema {"type":"array"}`) {
+		t.Errorf("Dry-run should use the --json-schema flag, got: %s", output)
+	}
+}
+
+func TestRun_InvalidJSONSchema(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--dry-run", "--json-schema", "{not json", "--prompt", "hi"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "failed to load output schema") {
+		t.Errorf("Expected output schema error, got: %v", err)
+	}
+}
+
+func TestRun_DryRunWithNamedArguments(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--dry-run", "../../testdata/arguments-skill", "main.go", "--mode=thorough", "--limit=3"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "Review main.go in thorough mode with at most 3 comments.") {
+		t.Errorf("Dry-run should interpolate named arguments, got: %s", output)
+	}
+}
+
+func TestRun_InvalidArgumentPrintsUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--dry-run", "../../testdata/arguments-skill", "main.go", "--mode=slow"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), `argument "mode" must be one of fast, thorough`) {
+		t.Fatalf("Expected enum validation error, got: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Usage: skillet arguments-skill <file> [mode] [limit]") {
+		t.Errorf("Invalid arguments should print usage to stderr, got: %s", stderr.String())
+	}
+}
+
+func TestRun_SkillHelp(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "../../testdata/arguments-skill", "--help"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "--mode   fast|thorough  Review depth (default: fast)") {
+		t.Errorf("Skill --help should print the skill's usage, got: %s", stdout.String())
+	}
+}
+
+func TestRun_UnknownFlagWithoutSkill(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "--dry-run", "--prompt", "hi", "--bogus=1"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "unknown flag: --bogus") {
+		t.Errorf("Expected unknown flag error, got: %v", err)
+	}
+}
+
+func TestSeparateArgumentFlags(t *testing.T) {
+	flags := flag.NewFlagSet("skillet", flag.ContinueOnError)
+	flags.String("prompt", "", "")
+	flags.Bool("verbose", false, "")
+
+	remaining, named := separateArgumentFlags(
+		[]string{"review", "--prompt", "--x=not-an-arg", "--mode=fast", "--verbose", "--strict", "main.go"},
+		flags,
+	)
+
+	wantRemaining := []string{"review", "--prompt", "--x=not-an-arg", "--verbose", "main.go"}
+	if strings.Join(remaining, " ") != strings.Join(wantRemaining, " ") {
+		t.Errorf("remaining = %v, want %v", remaining, wantRemaining)
+	}
+	if named["mode"] != "fast" || named["strict"] != "true" || len(named) != 2 {
+		t.Errorf("named = %v, want mode=fast strict=true", named)
+	}
+}
+
+func TestRun_NewSkill(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "new", "review", "--dir", dir, "--description", "Review code", "--color", "never"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "✓ Created skill review") {
+		t.Errorf("Should report the created skill, got: %s", stdout.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "review", "SKILL.md")); err != nil {
+		t.Errorf("SKILL.md should be created: %v", err)
+	}
+}
+
+func TestRun_NewRequiresName(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "new"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "skill name required") {
+		t.Errorf("Expected name required error, got: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Usage: skillet new") {
+		t.Errorf("Should print usage, got: %s", stderr.String())
+	}
+}
+
+func TestRun_Lint(t *testing.T) {
+	dir := t.TempDir()
+	skillDir := filepath.Join(dir, "skills", "broken")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: other\ndescription: Broken skill\n---\n\nRun {baseDir}/scripts/missing.sh\n"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"skillet", "lint", "--format", "json", dir}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "lint found 2 problems") {
+		t.Errorf("Expected lint failure, got: %v", err)
+	}
+	for _, rule := range []string{`"rule": "name-mismatch"`, `"rule": "missing-file"`} {
+		if !strings.Contains(stdout.String(), rule) {
+			t.Errorf("Output should contain %s, got: %s", rule, stdout.String())
+		}
+	}
+}
+
+func TestRun_LintStrict(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: "+filepath.Base(dir)+"\ndescription: Test\n---\n\nDo $ARGUMENTS\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"skillet", "lint", "--color", "never", dir}, &stdout, &stderr); err != nil {
+		t.Errorf("Warnings should not fail without --strict: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "0 errors, 1 warning") {
+		t.Errorf("Should summarize the warning, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "lint", "--strict", "--color", "never", dir}, &stdout, &stderr); err == nil {
+		t.Error("Warnings should fail with --strict")
+	}
+}
+
+func TestFormatResourceList_Invalid(t *testing.T) {
+	lines := formatResourceList([]listableItem{
+		{Name: "broken", SourceName: "project", Path: ".claude/skills/broken/SKILL.md", Invalid: true},
+	}, listStyles{})
+
+	if len(lines) != 1 || !strings.Contains(lines[0], "(invalid: run skillet lint)") {
+		t.Errorf("Invalid items should be labeled, got: %v", lines)
+	}
+}
+
+func TestRun_Test(t *testing.T) {
+	junit := filepath.Join(t.TempDir(), "report.xml")
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "test", "../../testdata/tested-skill", "--junit", junit, "--color", "never"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v\n%s", err, stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "✓ summarize (replay") || !strings.Contains(stdout.String(), "2 passed, 0 failed") {
+		t.Errorf("Should report passing replayed cases, got: %s", stdout.String())
+	}
+	if data, err := os.ReadFile(junit); err != nil || !strings.Contains(string(data), `<testsuite name="tested-skill" tests="2"`) {
+		t.Errorf("Should write a JUnit report, got %v: %s", err, data)
+	}
+}
+
+func TestRun_TestFilter(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "test", "--run", "nothing-matches", "../../testdata/tested-skill"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), `no tests match "nothing-matches"`) {
+		t.Errorf("Expected no match error, got: %v", err)
+	}
+}
+
+func TestRun_TestRequiresSkill(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "test"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "skill required") {
+		t.Errorf("Expected skill required error, got: %v", err)
+	}
+}
+
+func TestRun_BackendReplay(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"skillet", "../../testdata/simple-skill/SKILL.md", "--backend", "replay:../../testdata/parse/tool-operations.jsonl", "--format", "json"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), `"result": "Completed successfully"`) {
+		t.Errorf("Should format the replayed session, got: %s", stdout.String())
+	}
+}
+
+func TestRun_AuditLog(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"skillet", "../../testdata/simple-skill/SKILL.md", "--backend", "replay:../../testdata/parse/tool-operations.jsonl", "--audit-log", logPath, "-q"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Audit log not written: %v", err)
+	}
+	var types []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record struct {
+			Type string `json:"type"`
+			Tool string `json:"tool"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid audit record %q: %v", line, err)
+		}
+		types = append(types, strings.TrimSpace(record.Type+" "+record.Tool))
+	}
+	want := "run,tool Read,tool Glob,tool Bash,result"
+	if got := strings.Join(types, ","); got != want {
+		t.Errorf("Audit records = %s, want %s", got, want)
+	}
+}
+
+func TestRun_BackendFromConfig(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "skillet"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	configFile := "backend: pinned\nbackends:\n  pinned:\n    command: [/opt/claude/bin/claude, \"{args}\"]\n"
+	if err := os.WriteFile(filepath.Join(configHome, "skillet", "config.yaml"), []byte(configFile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"skillet", "--dry-run", "../../testdata/simple-skill/SKILL.md"}, &stdout, &stderr); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "/opt/claude/bin/claude -p --verbose") {
+		t.Errorf("Should use the configured default backend, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "--dry-run", "--backend", "claude", "../../testdata/simple-skill/SKILL.md"}, &stdout, &stderr); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Would execute:\nclaude -p") {
+		t.Errorf("--backend should override the config default, got: %s", stdout.String())
+	}
+
+	err := run([]string{"skillet", "--dry-run", "--backend", "missing", "../../testdata/simple-skill/SKILL.md"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), `unknown backend "missing"`) {
+		t.Errorf("Expected unknown backend error, got: %v", err)
+	}
+}
+
+func TestRun_ConfigLayers(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("SKILLET_PROFILE", "")
+	t.Setenv("SKILLET_MODEL", "")
+	if err := os.MkdirAll(filepath.Join(configHome, "skillet"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	userConfig := "model: sonnet\nprofiles:\n  ci:\n    model: haiku\n    permission-mode: plan\n"
+	if err := os.WriteFile(filepath.Join(configHome, "skillet", "config.yaml"), []byte(userConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	skillPath, err := filepath.Abs("../../testdata/simple-skill/SKILL.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := t.TempDir()
+	if err := os.Mkdir(filepath.Join(project, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, ".skillet.yaml"), []byte("model: opus\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	dryRun := func(args ...string) string {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if err := run(append([]string{"skillet", "--dry-run", skillPath}, args...), &stdout, &stderr); err != nil {
+			t.Fatalf("Run %v failed: %v", args, err)
+		}
+		return stdout.String()
+	}
+
+	if got := dryRun(); !strings.Contains(got, "--model opus") {
+		t.Errorf(".skillet.yaml should override the user config, got: %s", got)
+	}
+	if got := dryRun("--profile", "ci"); !strings.Contains(got, "--model haiku") || !strings.Contains(got, "--permission-mode plan") {
+		t.Errorf("--profile ci should apply the profile, got: %s", got)
+	}
+	t.Setenv("SKILLET_MODEL", "claude-env")
+	if got := dryRun("--profile", "ci"); !strings.Contains(got, "--model claude-env") {
+		t.Errorf("SKILLET_MODEL should override the profile, got: %s", got)
+	}
+	if got := dryRun("--profile", "ci", "--model", "claude-flag"); !strings.Contains(got, "--model claude-flag") {
+		t.Errorf("--model should override everything, got: %s", got)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = run([]string{"skillet", "--dry-run", "--profile", "missing", skillPath}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), `unknown profile "missing"`) {
+		t.Errorf("Expected unknown profile error, got: %v", err)
+	}
+}
+
+func TestRun_Batch(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logDir := filepath.Join(dir, "logs")
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"skillet", "batch", "../../testdata/simple-skill/SKILL.md", "--inputs", filepath.Join(dir, "*.txt"), "-j", "2", "--log-dir", logDir, "--backend", "replay:../../testdata/parse/tool-operations.jsonl"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Batch failed: %v\n%s", err, stderr.String())
+	}
+	if got := stdout.String(); !strings.Contains(got, "2 succeeded, 0 failed") || !strings.Contains(got, filepath.Join(logDir, "001-")) {
+		t.Errorf("Unexpected summary:\n%s", got)
+	}
+	logs, err := filepath.Glob(filepath.Join(logDir, "*.jsonl"))
+	if err != nil || len(logs) != 2 {
+		t.Errorf("Expected a log per input, got %v, %v", logs, err)
+	}
+
+	err = run([]string{"skillet", "batch", "../../testdata/simple-skill/SKILL.md"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "--inputs required") {
+		t.Errorf("Expected --inputs required, got: %v", err)
+	}
+}
+
+func TestRun_UnattendedAuditAndPolicy(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	dir := t.TempDir()
+	recording, err := filepath.Abs("../../testdata/parse/tool-operations.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	skillPath, err := filepath.Abs("../../testdata/simple-skill/SKILL.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A backend that replays the recording, so the agent command line is audited
+	logPath := filepath.Join(dir, "audit.jsonl")
+	configFile := fmt.Sprintf("audit-log: %s\nbackends:\n  rec:\n    command: [sh, -c, 'cat %s', sh, \"{args}\"]\n", logPath, recording)
+	if err := os.MkdirAll(filepath.Join(configHome, "skillet"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, "skillet", "config.yaml"), []byte(configFile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	inputs := filepath.Join(dir, "inputs.txt")
+	if err := os.WriteFile(inputs, []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pipelinePath := filepath.Join(dir, "pipeline.yaml")
+	if err := os.WriteFile(pipelinePath, []byte(fmt.Sprintf("steps:\n  - id: only\n    skill: %s\n", skillPath)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"skillet", "batch", skillPath, "--inputs", inputs, "--log-dir", filepath.Join(dir, "logs"), "--backend", "rec"}, &stdout, &stderr); err != nil {
+		t.Fatalf("Batch failed: %v\n%s", err, stderr.String())
+	}
+	if err := run([]string{"skillet", "pipeline", "run", pipelinePath, "--backend", "rec"}, &stdout, &stderr); err != nil {
+		t.Fatalf("Pipeline failed: %v\n%s", err, stderr.String())
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Audit log not written: %v", err)
+	}
+	runs := make(map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record struct {
+			Type  string   `json:"type"`
+			RunID string   `json:"run_id"`
+			Argv  []string `json:"argv"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid audit record %q: %v", line, err)
+		}
+		runs[record.RunID] = append(runs[record.RunID], record.Type)
+		if record.Type == "run" && !slices.Contains(record.Argv, "mcp__skillet__prompt") {
+			t.Errorf("The permission prompt tool should be attached so the policy applies, got argv %v", record.Argv)
+		}
+	}
+	if len(runs) != 3 {
+		t.Errorf("Expected a run ID for each input and step, got %v", runs)
+	}
+	for id, types := range runs {
+		if len(types) < 2 || types[0] != "run" || types[len(types)-1] != "result" {
+			t.Errorf("Run %s records = %v, want run ... result", id, types)
+		}
+	}
+}
+
+func TestRun_Pipeline(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	skillPath, err := filepath.Abs("../../testdata/simple-skill/SKILL.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	content := fmt.Sprintf("steps:\n  - id: first\n    skill: %s\n  - id: second\n    skill: %s\n    args: ['{{ .steps.first.result }}']\n", skillPath, skillPath)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = run([]string{"skillet", "pipeline", "run", path, "--color", "never", "--backend", "replay:../../testdata/parse/tool-operations.jsonl"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Pipeline failed: %v\n%s", err, stderr.String())
+	}
+	if got := stdout.String(); !strings.Contains(got, "▸ first") || !strings.Contains(got, "✓ second") {
+		t.Errorf("Unexpected output:\n%s", got)
+	}
+
+	err = run([]string{"skillet", "pipeline", "run", path, "--backend", "replay:missing.jsonl"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "step first failed") {
+		t.Errorf("Expected the pipeline to stop at the first step, got: %v", err)
+	}
+
+	err = run([]string{"skillet", "pipeline", "start"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), `unknown pipeline command "start"`) {
+		t.Errorf("Expected an unknown command error, got: %v", err)
+	}
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	recording := func(result string) string {
+		path := filepath.Join(t.TempDir(), "recording.jsonl")
+		content := `{"type":"system","subtype":"init","session_id":"s","tools":[],"model":"m","cwd":"/tmp"}` + "\n" + result + "\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return "replay:" + path
+	}
+	skill := "../../testdata/simple-skill/SKILL.md"
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"success", []string{skill, "--backend", "replay:../../testdata/parse/tool-operations.jsonl"}, exitcode.OK},
+		{"usage", []string{"--format", "yaml", skill}, exitcode.Usage},
+		{"not found", []string{"no-such-skill-anywhere"}, exitcode.NotFound},
+		{"invalid skill", []string{"../../testdata/invalid-skill/SKILL.md"}, exitcode.InvalidResource},
+		{"error result", []string{skill, "--backend", recording(`{"type":"result","subtype":"error_during_execution","is_error":true,"result":"It broke"}`)}, exitcode.ResultError},
+		{"max turns", []string{skill, "--backend", recording(`{"type":"result","subtype":"error_max_turns","is_error":true}`)}, exitcode.MaxTurns},
+		{"max budget", []string{skill, "--backend", recording(`{"type":"result","subtype":"error_max_budget_usd","is_error":true,"result":""}`)}, exitcode.MaxBudget},
+		{"pipeline usage", []string{"pipeline", "start"}, exitcode.Usage},
+		{"negative limit", []string{"--max-cost=-1", skill}, exitcode.Usage},
+		{"turn limit", []string{skill, "--max-turns", "2", "--backend", "replay:../../testdata/parse/tool-operations.jsonl"}, exitcode.MaxTurns},
+		{"under turn limit", []string{skill, "--max-turns", "3", "--backend", "replay:../../testdata/parse/tool-operations.jsonl"}, exitcode.OK},
+		{"cost limit", []string{skill, "--max-cost", "1", "--backend", recording(`{"type":"assistant","message":{"id":"msg_1","model":"claude-sonnet-4-5","role":"assistant","content":[],"usage":{"input_tokens":10,"output_tokens":100000}}}`)}, exitcode.MaxBudget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(append([]string{"skillet"}, tt.args...), &stdout, &stderr)
+			if got := exitcode.FromError(err); got != tt.want {
+				t.Errorf("exit code = %d, want %d (error: %v)", got, tt.want, err)
+			}
+		})
+	}
+
+	// Without claude on the PATH
+	t.Setenv("PATH", t.TempDir())
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"skillet", skill}, &stdout, &stderr)
+	if got := exitcode.FromError(err); got != exitcode.AgentNotFound {
+		t.Errorf("exit code = %d, want %d (error: %v)", got, exitcode.AgentNotFound, err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "claude is not installed") {
+		t.Errorf("Expected a not installed error, got: %v", err)
+	}
+}
+
+func TestRun_CacheAndOffline(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cached/SKILL.md" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("---\nname: cached\ndescription: Cached skill\n---\nDo the thing\n"))
+	}))
+	skillURL := server.URL + "/cached/SKILL.md"
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"skillet", "--offline", "--dry-run", skillURL}, &stdout, &stderr); err == nil || !strings.Contains(err.Error(), "not in cache") {
+		t.Errorf("Expected an offline miss before caching, got: %v", err)
+	}
+	if err := run([]string{"skillet", "--dry-run", skillURL}, &stdout, &stderr); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	server.Close()
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "--offline", "--dry-run", skillURL}, &stdout, &stderr); err != nil {
+		t.Fatalf("Offline run from cache failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Do the thing") {
+		t.Errorf("Offline run should use the cached skill, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "cache", "list"}, &stdout, &stderr); err != nil {
+		t.Fatalf("cache list failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), skillURL) {
+		t.Errorf("cache list should show the skill URL, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "cache", "prune", "--older-than", "1h"}, &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "Removed 0 cached entries") {
+		t.Errorf("cache prune should keep recent entries, got: %v %s", err, stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "cache", "clear"}, &stdout, &stderr); err != nil {
+		t.Fatalf("cache clear failed: %v", err)
+	}
+	stdout.Reset()
+	if err := run([]string{"skillet", "cache", "list"}, &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "Cache is empty") {
+		t.Errorf("cache list after clear = %v %s", err, stdout.String())
+	}
+
+	if err := run([]string{"skillet", "cache", "bogus"}, &stdout, &stderr); err == nil {
+		t.Error("Expected an error for an unknown cache command")
+	}
+}
+
+func TestRun_LockAndUpdate(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	content := "---\nname: pinned\ndescription: Pinned skill\n---\nVersion one\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pinned/SKILL.md" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+	skillURL := server.URL + "/pinned/SKILL.md"
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"skillet", "lock"}, &stdout, &stderr); err == nil || !strings.Contains(err.Error(), "nothing to lock") {
+		t.Errorf("Expected nothing to lock, got: %v", err)
+	}
+	if err := run([]string{"skillet", "lock", "pinned"}, &stdout, &stderr); err == nil || !strings.Contains(err.Error(), "not a URL or git source") {
+		t.Errorf("Expected local skills to be rejected, got: %v", err)
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "lock", skillURL}, &stdout, &stderr); err != nil {
+		t.Fatalf("lock failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Locked "+skillURL) {
+		t.Errorf("lock should report the source, got: %s", stdout.String())
+	}
+	lock, err := os.ReadFile("skillet.lock")
+	if err != nil || !strings.Contains(string(lock), skillURL) {
+		t.Fatalf("skillet.lock should list the source: %v\n%s", err, lock)
+	}
+
+	if err := run([]string{"skillet", "--dry-run", skillURL}, &stdout, &stderr); err != nil {
+		t.Fatalf("Run of a locked skill failed: %v", err)
+	}
+
+	content = "---\nname: pinned\ndescription: Pinned skill\n---\nVersion two\n"
+	if err := run([]string{"skillet", "--dry-run", skillURL}, &stdout, &stderr); err == nil || !strings.Contains(err.Error(), "changed since it was locked") {
+		t.Errorf("Expected a lock mismatch for changed content, got: %v", err)
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "--dry-run", "--update", skillURL}, &stdout, &stderr); err != nil {
+		t.Fatalf("Run with --update failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Version two") {
+		t.Errorf("--update should run the new content, got: %s", stdout.String())
+	}
+	if err := run([]string{"skillet", "--dry-run", skillURL}, &stdout, &stderr); err != nil {
+		t.Errorf("--update should record the new hash, got: %v", err)
+	}
+}
+
+func TestRun_InstallUpdateUninstall(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "greet")
+	if err := os.MkdirAll(source, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeSource := func(body string) {
+		t.Helper()
+		content := "---\nname: greet\ndescription: Say hello\n---\n" + body
+		if err := os.WriteFile(filepath.Join(source, "SKILL.md"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSource("Say hello\n")
+
+	project := t.TempDir()
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"skillet", "install", source, "--name", "team:greet"}, &stdout, &stderr); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, ".claude", "skills", "team", "greet", "SKILL.md")); err != nil {
+		t.Fatalf("install should copy the skill into the project: %v", err)
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "outdated"}, &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "up to date") {
+		t.Errorf("outdated after install = %v %s", err, stdout.String())
+	}
+
+	writeSource("Say hello loudly\n")
+	stdout.Reset()
+	if err := run([]string{"skillet", "outdated"}, &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "team:greet") || !strings.Contains(stdout.String(), "outdated") {
+		t.Errorf("outdated should report the changed source, got: %v %s", err, stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"skillet", "update"}, &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "Updated team:greet") {
+		t.Errorf("update = %v %s", err, stdout.String())
+	}
+	content, _ := os.ReadFile(filepath.Join(project, ".claude", "skills", "team", "greet", "SKILL.md"))
+	if !strings.Contains(string(content), "loudly") {
+		t.Errorf("update should install the new content, got: %s", content)
+	}
+
+	if err := run([]string{"skillet", "uninstall", "team:greet"}, &stdout, &stderr); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, ".claude", "skills", "team")); !os.IsNotExist(err) {
+		t.Error("uninstall should remove the skill")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/martinemde/skillet/internal/color"
+	"github.com/martinemde/skillet/internal/exitcode"
+	"github.com/martinemde/skillet/internal/scaffold"
+)
+
+// runNew handles the `skillet new <name>` subcommand
+func runNew(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet new", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		user          = flags.Bool("user", false, "Create the skill in ~/.claude/skills instead of the project")
+		templateName  = flags.String("template", scaffold.DefaultTemplate, "Template to create the skill from")
+		description   = flags.String("description", "", "Skill description")
+		outputDir     = flags.String("dir", "", "Skills directory to create the skill in (overrides --user)")
+		force         = flags.Bool("force", false, "Overwrite an existing skill")
+		listTemplates = flags.Bool("list-templates", false, "List available templates")
+		colorFlag     = flags.String("color", "auto", "Control color output (auto, always, never)")
+	)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet new [options] <[namespace:]name>")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	// Allow flags both before and after the name
+	if err := flags.Parse(args); err != nil {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	var positional []string
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+	}
+
+	if *listTemplates {
+		names, err := scaffold.Templates()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			_, _ = fmt.Fprintln(stdout, name)
+		}
+		return nil
+	}
+
+	if len(positional) != 1 {
+		flags.Usage()
+		return usageError("skill name required")
+	}
+
+	scope := scaffold.ScopeProject
+	if *user {
+		scope = scaffold.ScopeUser
+	}
+
+	result, err := scaffold.Create(scaffold.Config{
+		Name:        positional[0],
+		Description: *description,
+		Scope:       scope,
+		OutputDir:   *outputDir,
+		Template:    *templateName,
+		Force:       *force,
+	})
+	if err != nil {
+		return err
+	}
+
+	printNewSkill(stdout, result, *description == "", *colorFlag)
+	return nil
+}
+
+// printNewSkill reports the created skill in the same style as --convert-to-skill
+func printNewSkill(w io.Writer, result *scaffold.Result, placeholderDescription bool, colorMode string) {
+	successStyle := lipgloss.NewStyle().Bold(true)
+	labelStyle := lipgloss.NewStyle()
+	valueStyle := lipgloss.NewStyle()
+	guidanceStyle := lipgloss.NewStyle().Italic(true)
+
+	if color.ShouldUseColors(colorMode) {
+		successStyle = successStyle.Foreground(lipgloss.Color("2")) // Green
+		labelStyle = labelStyle.Foreground(lipgloss.Color("8"))     // Dim
+		valueStyle = valueStyle.Foreground(lipgloss.Color("7"))     // Light gray
+		guidanceStyle = guidanceStyle.Foreground(lipgloss.Color("3"))
+	}
+
+	name := result.SkillName
+	if result.Namespace != "" {
+		name = strings.ReplaceAll(result.Namespace, "/", ":") + ":" + name
+	}
+
+	_, _ = fmt.Fprintln(w, successStyle.Render("✓ Created skill "+name))
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintf(w, "  %s    %s\n", labelStyle.Render("Skill:"), valueStyle.Render(result.SkillPath))
+	_, _ = fmt.Fprintf(w, "  %s %s\n", labelStyle.Render("Template:"), valueStyle.Render(result.Template))
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprintln(w, labelStyle.Render("  Files:"))
+	for _, file := range result.Files {
+		_, _ = fmt.Fprintf(w, "    %s\n", file)
+	}
+
+	if placeholderDescription {
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, labelStyle.Render("  You may want to review:"))
+		_, _ = fmt.Fprintf(w, "    %s %s\n", guidanceStyle.Render("•"), "description - explain what the skill does and when Claude should use it")
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/color"
+	"github.com/martinemde/skillet/internal/exitcode"
+	"github.com/martinemde/skillet/internal/pipeline"
+	"github.com/martinemde/skillet/internal/resolver"
+)
+
+// runPipeline handles the `skillet pipeline run [pipeline.yaml]` subcommand
+func runPipeline(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet pipeline run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		verbose        = flags.Bool("verbose", false, "Show detailed output including thinking and tool details")
+		showUsage      = flags.Bool("usage", false, "Show token usage statistics after each step")
+		model          = flags.String("model", "", "Override model to use for every step")
+		permissionMode = flags.String("permission-mode", "", "Override permission mode (default: acceptEdits)")
+		maxCost        = flags.Float64("max-cost", 0, "Stop each step when its estimated cost passes this many USD")
+		maxTurns       = flags.Int("max-turns", 0, "Stop each step after this many turns")
+		backendName    = flags.String("backend", "", "Agent backend: claude, replay:<file>, or a backend from the config file")
+		auditLogPath   = flags.String("audit-log", "", "Append a JSONL audit log of each step's run to this file")
+		offline        = flags.Bool("offline", false, "Resolve URLs and git sources only from the cache")
+		colorFlag      = flags.String("color", "auto", "Control color output (auto, always, never)")
+	)
+	// Read by applyConfigDefaults
+	flags.String("profile", "", "Apply a named profile from the config files")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet pipeline run [options] [file]")
+		_, _ = fmt.Fprintf(stderr, "\nRuns the skills in a pipeline file (default %s), passing each step's\n", pipeline.FileName)
+		_, _ = fmt.Fprintln(stderr, "result to the steps that need it. Stops at the first failed step unless")
+		_, _ = fmt.Fprintln(stderr, "it's marked continue-on-error.")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	if len(args) == 0 || args[0] != "run" {
+		flags.Usage()
+		if len(args) == 0 {
+			return usageError("pipeline command required")
+		}
+		return usageError("unknown pipeline command %q", args[0])
+	}
+
+	positional, err := installFlags(flags, args[1:])
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		flags.Usage()
+		return usageError("unexpected arguments: %v", positional[1:])
+	}
+	path := pipeline.FileName
+	if len(positional) == 1 {
+		path = positional[0]
+	}
+	if err := applyConfigDefaults(flags); err != nil {
+		return err
+	}
+	limits, err := flagLimits(*maxCost, *maxTurns)
+	if err != nil {
+		return err
+	}
+	color.ConfigureColorProfile(*colorFlag)
+
+	p, err := pipeline.Load(path)
+	if err != nil {
+		return err
+	}
+
+	// Resolve every step before running any, so a typo doesn't fail halfway
+	res, err := resolver.New()
+	if err != nil {
+		return err
+	}
+	res.Offline = *offline
+	resources := make(map[string]*resolver.ResolveResult, len(p.Steps))
+	for _, step := range p.Steps {
+		result, err := res.Resolve(step.Skill)
+		if err != nil {
+			return fmt.Errorf("step %s: failed to resolve skill or command: %w", step.ID, err)
+		}
+		defer result.Cleanup()
+		resources[step.ID] = result
+	}
+
+	runner, err := newUnattendedRunner(*backendName, *auditLogPath)
+	if err != nil {
+		return err
+	}
+
+	execute := func(ctx context.Context, step *pipeline.Step, input arguments.Input, stdout, stderr io.Writer) error {
+		config, limits, err := resourceConfig(resources[step.ID], input, *model, *permissionMode, limits)
+		if err != nil {
+			return err
+		}
+		return runner.run(ctx, step.Skill, resources[step.ID], config, limits, stdout, stderr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	results := pipeline.Run(ctx, p, pipeline.Options{
+		Execute:   execute,
+		Output:    stdout,
+		Verbose:   *verbose,
+		ShowUsage: *showUsage,
+		Color:     *colorFlag,
+	})
+
+	if ctx.Err() != nil {
+		return exitcode.ErrInterrupted
+	}
+	for _, r := range results {
+		if r.Status() == pipeline.StatusFailed && !r.Step.ContinueOnError {
+			// Exit as the failed step would have on its own
+			code := exitcode.FromError(r.Err)
+			if r.Summary != nil && r.Summary.IsError {
+				code = exitcode.FromSubtype(r.Summary.Subtype)
+			}
+			return exitcode.Wrap(code, fmt.Errorf("pipeline %s stopped: step %s failed", p.Name, r.Step.ID))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/martinemde/skillet/internal/color"
+	"github.com/martinemde/skillet/internal/exitcode"
+	"github.com/martinemde/skillet/internal/harness"
+	"github.com/martinemde/skillet/internal/resolver"
+)
+
+// runTest handles the `skillet test <skill>` subcommand
+func runTest(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("skillet test", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		live      = flags.Bool("live", false, "Run Claude even when a case has a recording")
+		record    = flags.Bool("record", false, "Run Claude and save each session as the case's recording")
+		junit     = flags.String("junit", "", "Write a JUnit XML report to `file`")
+		runFilter = flags.String("run", "", "Only run cases whose name contains `text`")
+		keep      = flags.Bool("keep", false, "Keep temp workspaces and print their paths")
+		backend   = flags.String("backend", "", "Agent backend for live runs: claude, replay:<file>, or configured")
+		colorFlag = flags.String("color", "auto", "Control color output (auto, always, never)")
+	)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: skillet test [options] <skill>")
+		_, _ = fmt.Fprintln(stderr)
+		_, _ = fmt.Fprintln(stderr, "Runs the test cases in the skill's tests/ directory.")
+		_, _ = fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	// Allow flags both before and after the skill
+	if err := flags.Parse(args); err != nil {
+		return exitcode.Wrap(exitcode.Usage, err)
+	}
+	var positional []string
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return exitcode.Wrap(exitcode.Usage, err)
+		}
+	}
+
+	if len(positional) != 1 {
+		flags.Usage()
+		return usageError("skill required")
+	}
+
+	result, err := resolver.Resolve(positional[0])
+	if err != nil {
+		return fmt.Errorf("failed to resolve skill: %w", err)
+	}
+	if result.IsURL {
+		result.Cleanup()
+		return fmt.Errorf("skillet test requires a local skill")
+	}
+	if result.Type != resolver.ResourceTypeSkill {
+		return fmt.Errorf("skillet test requires a skill, %s is a command", positional[0])
+	}
+
+	agentBackend, err := resolveBackend(*backend)
+	if err != nil {
+		return err
+	}
+
+	cases, err := harness.LoadCases(filepath.Dir(result.Path))
+	if err != nil {
+		return err
+	}
+	if *runFilter != "" {
+		var selected []*harness.Case
+		for _, c := range cases {
+			if strings.Contains(c.Name, *runFilter) {
+				selected = append(selected, c)
+			}
+		}
+		if len(selected) == 0 {
+			return fmt.Errorf("no tests match %q", *runFilter)
+		}
+		cases = selected
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	results := harness.Run(ctx, cases, harness.Options{
+		SkillPath:     result.Path,
+		SystemPrompt:  buildSkillSystemPrompt,
+		Live:          *live,
+		Record:        *record,
+		KeepWorkspace: *keep,
+		Backend:       agentBackend,
+	})
+
+	if err := harness.WriteText(stdout, results, color.ShouldUseColors(*colorFlag)); err != nil {
+		return err
+	}
+
+	if *junit != "" {
+		f, err := os.Create(*junit)
+		if err != nil {
+			return fmt.Errorf("failed to write JUnit report: %w", err)
+		}
+		skillName := filepath.Base(filepath.Dir(result.Path))
+		if err := harness.WriteJUnit(f, skillName, results); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write JUnit report: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write JUnit report: %w", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return exitcode.ErrInterrupted
+	}
+	failed := 0
+	for _, r := range results {
+		if !r.Passed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s failed", failed, len(results), pluralize(len(results), "test", "tests"))
+	}
+	return nil
+}
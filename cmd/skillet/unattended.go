@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/martinemde/skillet/internal/audit"
+	"github.com/martinemde/skillet/internal/budget"
+	"github.com/martinemde/skillet/internal/executor"
+	"github.com/martinemde/skillet/internal/formatter"
+	"github.com/martinemde/skillet/internal/resolver"
+)
+
+// unattendedRunner runs skills for batch and pipeline, where nobody can answer
+// prompts. The MCP permission tool is attached without a prompt server, so
+// the skillet policy still denies tools and denies what it would ask about.
+// Each run is audited under its own run ID.
+type unattendedRunner struct {
+	backend     executor.Backend
+	skilletPath string
+	auditLog    *audit.Log // Template for each run's log; nil when auditing is off
+}
+
+// newUnattendedRunner resolves the backend and audit log for unattended runs
+func newUnattendedRunner(backendName, auditLogPath string) (*unattendedRunner, error) {
+	backend, err := resolveBackend(backendName)
+	if err != nil {
+		return nil, err
+	}
+	auditLog, err := resolveAuditLog(auditLogPath)
+	if err != nil {
+		return nil, err
+	}
+	skilletPath, _ := os.Executable()
+	return &unattendedRunner{
+		backend:     backend,
+		skilletPath: skilletPath,
+		auditLog:    auditLog,
+	}, nil
+}
+
+// run runs the agent for a resolved skill or command, writing stream-json to
+// stdout. It returns a *budget.ExceededError when the run goes over a limit.
+func (r *unattendedRunner) run(ctx context.Context, name string, resource *resolver.ResolveResult, config executor.Config, limits budget.Limits, stdout, stderr io.Writer) error {
+	auditLog, err := r.auditLog.NewRun()
+	if err != nil {
+		return err
+	}
+	config.SkilletPath = r.skilletPath
+	config.Env = append(config.Env, auditLog.Env()...)
+
+	// The audit log reads its own copy of the stream
+	auditReader, auditWriter := io.Pipe()
+	if auditLog != nil {
+		stdout = io.MultiWriter(stdout, auditWriter)
+	}
+
+	// The budget watcher stops the agent when it goes over a limit
+	var watcher *budget.Watcher
+	if limits.Enabled() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		watcher = budget.NewWatcher(limits, cancel)
+		stdout = io.MultiWriter(stdout, watcher)
+	}
+
+	exec, err := executor.New(r.backend, config, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	if err := auditLog.Start(audit.Run{
+		Skill:   name,
+		Path:    resource.Path,
+		Backend: r.backend.Name,
+		Argv:    exec.Argv(),
+	}); err != nil {
+		return err
+	}
+
+	type auditOutcome struct {
+		summary *formatter.Summary
+		err     error
+	}
+	auditChan := make(chan auditOutcome, 1)
+	if auditLog != nil {
+		go func() {
+			summary, err := auditLog.Stream(auditReader, name, resource.Path)
+			_, _ = io.Copy(io.Discard, auditReader) // Keep the agent unblocked after a stream error
+			auditChan <- auditOutcome{summary, err}
+		}()
+	}
+
+	runErr := exec.Execute(ctx)
+	_ = auditWriter.Close()
+	if watcher.Err() != nil {
+		runErr = watcher.Err()
+	}
+
+	if auditLog != nil {
+		outcome := <-auditChan
+		if err := auditLog.Finish(outcome.summary, runErr); err != nil {
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+		if runErr == nil {
+			runErr = outcome.err
+		}
+	}
+	return runErr
+}
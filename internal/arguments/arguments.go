@@ -0,0 +1,307 @@
+// Package arguments handles the typed, named arguments a skill or command
+// declares in its frontmatter. It binds command-line input to declarations,
+// validates types and required fields, and interpolates values into content.
+package arguments
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Argument types
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypePath    = "path"
+)
+
+var (
+	// placeholderRegex matches $ARGUMENTS, ${name} and $1, $2, ... variable
+	// references, capturing the name or position
+	placeholderRegex = regexp.MustCompile(`\$ARGUMENTS|\$\{([A-Za-z_][A-Za-z0-9_-]*)\}|\$([1-9][0-9]*)`)
+	// nameRegex validates declared argument names
+	nameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+)
+
+// Declaration describes an argument declared in frontmatter
+type Declaration struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type,omitempty"` // string (default), integer, number, boolean, or path
+	Required    bool     `yaml:"required,omitempty"`
+	Default     string   `yaml:"default,omitempty"`
+	Enum        []string `yaml:"enum,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// Input is the raw argument input from the command line
+type Input struct {
+	Positional []string          // Arguments after the skill name, in order
+	Named      map[string]string // Arguments given as --name=value
+	Raw        string            // Exact $ARGUMENTS text; empty means join Positional
+	Dir        string            // Directory relative path arguments are checked in; empty means the working directory
+}
+
+// FromString creates input from a single argument string, splitting on whitespace
+func FromString(s string) Input {
+	return Input{Positional: strings.Fields(s), Raw: s}
+}
+
+// Values are the bound argument values
+type Values struct {
+	Positional []string
+	Named      map[string]string // Declared arguments, including defaults
+	Raw        string
+}
+
+// Error reports invalid arguments along with usage for the skill or command
+type Error struct {
+	Err   error
+	Usage string
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks that declarations are well formed
+func Validate(decls []Declaration) error {
+	seen := make(map[string]bool)
+	for _, d := range decls {
+		if !nameRegex.MatchString(d.Name) {
+			return fmt.Errorf("invalid argument name %q", d.Name)
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("duplicate argument %q", d.Name)
+		}
+		seen[d.Name] = true
+
+		switch d.typ() {
+		case TypeString, TypeInteger, TypeNumber, TypeBoolean, TypePath:
+		default:
+			return fmt.Errorf("argument %q has unknown type %q", d.Name, d.Type)
+		}
+	}
+	return nil
+}
+
+// Resolve binds input to declarations and validates the result.
+// Positional arguments fill declarations in order, skipping those given by name.
+func Resolve(decls []Declaration, input Input) (Values, error) {
+	values := Values{
+		Positional: input.Positional,
+		Named:      make(map[string]string),
+		Raw:        input.Raw,
+	}
+
+	if err := Validate(decls); err != nil {
+		return values, err
+	}
+
+	declared := make(map[string]bool)
+	for _, d := range decls {
+		declared[d.Name] = true
+	}
+	for name := range input.Named {
+		if !declared[name] {
+			return values, fmt.Errorf("unknown argument --%s", name)
+		}
+	}
+
+	next := 0
+	for _, d := range decls {
+		value, ok := input.Named[d.Name]
+		if !ok && next < len(input.Positional) {
+			value, ok = input.Positional[next], true
+			next++
+		}
+		if !ok {
+			if d.Default != "" {
+				values.Named[d.Name] = d.Default
+				continue
+			}
+			if d.Required {
+				return values, fmt.Errorf("missing required argument %q", d.Name)
+			}
+			continue
+		}
+
+		if err := d.check(value, input.Dir); err != nil {
+			return values, err
+		}
+		values.Named[d.Name] = value
+	}
+
+	return values, nil
+}
+
+// check validates a value against the declaration's type and enum
+func (d Declaration) check(value, dir string) error {
+	switch d.typ() {
+	case TypeInteger:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("argument %q must be an integer, got %q", d.Name, value)
+		}
+	case TypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("argument %q must be a number, got %q", d.Name, value)
+		}
+	case TypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("argument %q must be true or false, got %q", d.Name, value)
+		}
+	case TypePath:
+		p := value
+		if dir != "" && !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		if _, err := os.Stat(p); err != nil {
+			return fmt.Errorf("argument %q must be an existing path, got %q", d.Name, value)
+		}
+	}
+
+	if len(d.Enum) > 0 {
+		for _, allowed := range d.Enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("argument %q must be one of %s, got %q", d.Name, strings.Join(d.Enum, ", "), value)
+	}
+
+	return nil
+}
+
+func (d Declaration) typ() string {
+	if d.Type == "" {
+		return TypeString
+	}
+	return d.Type
+}
+
+// Interpolate replaces $ARGUMENTS, $1, $2, ... and ${name} in content.
+// $N is only replaced when arguments are declared, and references beyond both
+// the given and declared arguments are left as-is, so shell snippets like
+// awk's $1 survive. If content has no placeholders and arguments were given, they are appended
+// as "ARGUMENTS: <value>" per the agentskills.io spec.
+func Interpolate(content string, decls []Declaration, values Values) string {
+	all := values.Raw
+	if all == "" {
+		all = strings.Join(values.Positional, " ")
+	}
+
+	// One pass, so placeholders inside substituted values aren't expanded
+	replaced := false
+	limit := max(len(values.Positional), len(decls))
+	content = placeholderRegex.ReplaceAllStringFunc(content, func(match string) string {
+		groups := placeholderRegex.FindStringSubmatch(match)
+		switch {
+		case groups[1] != "":
+			value, ok := values.Named[groups[1]]
+			if !ok && !isDeclared(decls, groups[1]) {
+				return match
+			}
+			replaced = true
+			return value
+		case groups[2] != "":
+			// $N is only replaced when arguments are declared
+			n, err := strconv.Atoi(groups[2])
+			if len(decls) == 0 || err != nil || n > limit {
+				return match
+			}
+			replaced = true
+			if n <= len(values.Positional) {
+				return values.Positional[n-1]
+			}
+			return values.Named[decls[n-1].Name]
+		default:
+			replaced = true
+			return all
+		}
+	})
+
+	if !replaced && all != "" {
+		content = content + "\n\nARGUMENTS: " + all
+	}
+
+	return content
+}
+
+func isDeclared(decls []Declaration, name string) bool {
+	for _, d := range decls {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage returns a usage message for a skill or command with the given declarations
+func Usage(name, argumentHint string, decls []Declaration) string {
+	var b strings.Builder
+
+	b.WriteString("Usage: skillet " + name)
+	switch {
+	case len(decls) > 0:
+		for _, d := range decls {
+			if d.Required && d.Default == "" {
+				b.WriteString(" <" + d.Name + ">")
+			} else {
+				b.WriteString(" [" + d.Name + "]")
+			}
+		}
+	case argumentHint != "":
+		b.WriteString(" " + argumentHint)
+	default:
+		b.WriteString(" [arguments...]")
+	}
+	b.WriteString("\n")
+
+	if len(decls) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\nArguments can be given in order or by name (--name=value):\n")
+
+	nameWidth, typeWidth := 0, 0
+	for _, d := range decls {
+		nameWidth = max(nameWidth, len(d.Name)+2)
+		typeWidth = max(typeWidth, len(d.typeLabel()))
+	}
+
+	for _, d := range decls {
+		line := fmt.Sprintf("  %-*s  %-*s", nameWidth, "--"+d.Name, typeWidth, d.typeLabel())
+		var details []string
+		if d.Description != "" {
+			details = append(details, d.Description)
+		}
+		if d.Default != "" {
+			details = append(details, "(default: "+d.Default+")")
+		} else if d.Required {
+			details = append(details, "(required)")
+		}
+		if len(details) > 0 {
+			line += "  " + strings.Join(details, " ")
+		}
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	return b.String()
+}
+
+// typeLabel describes the type for usage output, listing enum values when set
+func (d Declaration) typeLabel() string {
+	if len(d.Enum) > 0 {
+		return strings.Join(d.Enum, "|")
+	}
+	return d.typ()
+}
@@ -0,0 +1,247 @@
+package arguments
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var reviewDecls = []Declaration{
+	{Name: "file", Type: TypePath, Required: true, Description: "File to review"},
+	{Name: "mode", Enum: []string{"fast", "thorough"}, Default: "fast", Description: "Review depth"},
+	{Name: "limit", Type: TypeInteger},
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   Input
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "positional fills declarations in order",
+			input: Input{Positional: []string{"arguments.go", "thorough", "3"}},
+			want:  map[string]string{"file": "arguments.go", "mode": "thorough", "limit": "3"},
+		},
+		{
+			name:  "named arguments skip positional binding",
+			input: Input{Positional: []string{"arguments.go"}, Named: map[string]string{"limit": "5"}},
+			want:  map[string]string{"file": "arguments.go", "mode": "fast", "limit": "5"},
+		},
+		{
+			name:  "named and positional mixed",
+			input: Input{Positional: []string{"thorough"}, Named: map[string]string{"file": "arguments.go"}},
+			want:  map[string]string{"file": "arguments.go", "mode": "thorough"},
+		},
+		{
+			name:    "missing required",
+			input:   Input{},
+			wantErr: `missing required argument "file"`,
+		},
+		{
+			name:    "path must exist",
+			input:   Input{Positional: []string{"does-not-exist.go"}},
+			wantErr: `argument "file" must be an existing path`,
+		},
+		{
+			name:  "path relative to dir",
+			input: Input{Positional: []string{"arguments.go"}, Dir: "."},
+			want:  map[string]string{"file": "arguments.go", "mode": "fast"},
+		},
+		{
+			name:    "path missing from dir",
+			input:   Input{Positional: []string{"arguments.go"}, Dir: "testdata-missing"},
+			wantErr: `argument "file" must be an existing path`,
+		},
+		{
+			name:    "enum",
+			input:   Input{Positional: []string{"arguments.go", "slow"}},
+			wantErr: `argument "mode" must be one of fast, thorough, got "slow"`,
+		},
+		{
+			name:    "integer",
+			input:   Input{Positional: []string{"arguments.go"}, Named: map[string]string{"limit": "many"}},
+			wantErr: `argument "limit" must be an integer`,
+		},
+		{
+			name:    "unknown named argument",
+			input:   Input{Positional: []string{"arguments.go"}, Named: map[string]string{"verbose": "true"}},
+			wantErr: "unknown argument --verbose",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Resolve(reviewDecls, tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if len(values.Named) != len(tt.want) {
+				t.Errorf("Named = %v, want %v", values.Named, tt.want)
+			}
+			for k, v := range tt.want {
+				if values.Named[k] != v {
+					t.Errorf("Named[%q] = %q, want %q", k, values.Named[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		decls   []Declaration
+		wantErr string
+	}{
+		{"valid", reviewDecls, ""},
+		{"bad name", []Declaration{{Name: "my file"}}, "invalid argument name"},
+		{"duplicate", []Declaration{{Name: "a"}, {Name: "a"}}, "duplicate argument"},
+		{"unknown type", []Declaration{{Name: "a", Type: "list"}}, "unknown type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.decls)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	values := Values{
+		Positional: []string{"main.go"},
+		Named:      map[string]string{"file": "main.go", "mode": "fast"},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		decls   []Declaration
+		values  Values
+		want    string
+	}{
+		{
+			name:    "named and positional",
+			content: "Review ${file} ($1) in ${mode} mode, $2 too",
+			decls:   reviewDecls,
+			values:  values,
+			want:    "Review main.go (main.go) in fast mode, fast too",
+		},
+		{
+			name:    "unset optional is empty",
+			content: "Limit: ${limit}.",
+			decls:   reviewDecls,
+			values:  values,
+			want:    "Limit: .",
+		},
+		{
+			name:    "all arguments",
+			content: "Args: $ARGUMENTS",
+			values:  Values{Positional: []string{"a", "b"}},
+			want:    "Args: a b",
+		},
+		{
+			name:    "out of range positional left alone",
+			content: "awk '{print $1}'",
+			want:    "awk '{print $1}'",
+		},
+		{
+			name:    "undeclared name left alone",
+			content: "echo ${HOME}",
+			values:  Values{Positional: []string{"x"}},
+			want:    "echo ${HOME}\n\nARGUMENTS: x",
+		},
+		{
+			name:    "append when no placeholders",
+			content: "Do it",
+			values:  Values{Positional: []string{"now"}},
+			want:    "Do it\n\nARGUMENTS: now",
+		},
+		{
+			name:    "no append when positional used",
+			content: "Do $1",
+			decls:   []Declaration{{Name: "when"}},
+			values:  Values{Positional: []string{"now"}, Named: map[string]string{"when": "now"}},
+			want:    "Do now",
+		},
+		{
+			name:    "positional left alone without declarations",
+			content: "Run awk '{print $1}'",
+			values:  Values{Positional: []string{"data.txt"}},
+			want:    "Run awk '{print $1}'\n\nARGUMENTS: data.txt",
+		},
+		{
+			name:    "placeholders in values aren't expanded",
+			content: "A=$ARGUMENTS F=${file} M=$2",
+			decls:   reviewDecls,
+			values: Values{
+				Positional: []string{"cost $1", "fast"},
+				Named:      map[string]string{"file": "cost $1", "mode": "fast"},
+				Raw:        "cost ${mode} $1",
+			},
+			want: "A=cost ${mode} $1 F=cost $1 M=fast",
+		},
+		{
+			name:    "raw text is kept as-is",
+			content: "Run $ARGUMENTS",
+			values:  Values{Positional: []string{"a", "b"}, Raw: "a  b"},
+			want:    "Run a  b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Interpolate(tt.content, tt.decls, tt.values)
+			if got != tt.want {
+				t.Errorf("Interpolate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	usage := Usage("review", "", reviewDecls)
+
+	expected := []string{
+		"Usage: skillet review <file> [mode] [limit]",
+		"--file   path           File to review (required)",
+		"--mode   fast|thorough  Review depth (default: fast)",
+		"--limit  integer",
+	}
+	for _, want := range expected {
+		if !strings.Contains(usage, want) {
+			t.Errorf("Usage should contain %q, got:\n%s", want, usage)
+		}
+	}
+}
+
+func TestUsage_ArgumentHint(t *testing.T) {
+	usage := Usage("deploy", "<environment>", nil)
+	if usage != "Usage: skillet deploy <environment>\n" {
+		t.Errorf("Usage() = %q", usage)
+	}
+}
+
+func TestError_Unwrap(t *testing.T) {
+	inner := errors.New("missing")
+	var err error = &Error{Err: inner, Usage: "Usage: skillet x\n"}
+	if !errors.Is(err, inner) || err.Error() != "missing" {
+		t.Errorf("Error should wrap and report the inner error, got %v", err)
+	}
+}
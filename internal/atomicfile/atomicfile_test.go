@@ -0,0 +1,40 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.json")
+
+	for _, content := range []string{"first", "second"} {
+		if err := Write(path, []byte(content)); err != nil {
+			t.Fatalf("Write(%q) error: %v", content, err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile error: %v", err)
+		}
+		if string(got) != content {
+			t.Errorf("file = %q, want %q", got, content)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dir has %d entries, want only the written file", len(entries))
+	}
+}
+
+func TestWrite_MissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "file.json")
+	if err := Write(path, []byte("data")); err == nil {
+		t.Error("Write to a missing directory succeeded, want error")
+	}
+}
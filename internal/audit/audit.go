@@ -0,0 +1,276 @@
+// Package audit appends a JSONL record of each run to an audit log: who ran
+// which skill, the agent command, every tool call and permission decision,
+// and the final result.
+package audit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+// Environment variables that pass the audit log to the MCP permission server
+const (
+	PathEnvVar  = "SKILLET_AUDIT_LOG"
+	RunIDEnvVar = "SKILLET_AUDIT_RUN_ID"
+)
+
+// Record types
+const (
+	TypeRun        = "run"        // A run started
+	TypeTool       = "tool"       // A tool call completed
+	TypePermission = "permission" // A permission prompt was answered
+	TypeResult     = "result"     // A run finished
+)
+
+// Record is one line of the audit log. Fields not used by a record type are omitted.
+type Record struct {
+	Type  string    `json:"type"`
+	Time  time.Time `json:"time"`
+	RunID string    `json:"run_id"`
+
+	// run
+	User    string   `json:"user,omitempty"`
+	Host    string   `json:"host,omitempty"`
+	WorkDir string   `json:"workdir,omitempty"`
+	Skill   string   `json:"skill,omitempty"`
+	Path    string   `json:"path,omitempty"`
+	SHA256  string   `json:"sha256,omitempty"`
+	Backend string   `json:"backend,omitempty"`
+	Argv    []string `json:"argv,omitempty"`
+
+	// tool and permission
+	Tool       string         `json:"tool,omitempty"`
+	Input      map[string]any `json:"input,omitempty"`
+	Status     string         `json:"status,omitempty"`
+	ResultSize int            `json:"result_size,omitempty"` // Bytes of tool output
+	Decision   string         `json:"decision,omitempty"`
+	Reason     string         `json:"reason,omitempty"`
+
+	// result
+	Result    string           `json:"result,omitempty"`
+	Subtype   string           `json:"subtype,omitempty"`
+	IsError   bool             `json:"is_error,omitempty"`
+	Usage     *formatter.Usage `json:"usage,omitempty"`
+	SessionID string           `json:"session_id,omitempty"`
+	ElapsedMS int64            `json:"elapsed_ms,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// Run describes a run for its opening record
+type Run struct {
+	Skill   string   // Skill or command name
+	Path    string   // Resolved skill or command file
+	Backend string   // Agent backend name
+	Argv    []string // Agent command line
+}
+
+// Log appends records for one run to an audit log file.
+// A nil *Log discards records, so callers need not check whether auditing is on.
+type Log struct {
+	path  string
+	runID string
+	mu    sync.Mutex
+}
+
+// New returns a log for a new run appending to path
+func New(path string) (*Log, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit log path: %w", err)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to create run ID: %w", err)
+	}
+	return &Log{path: abs, runID: hex.EncodeToString(id)}, nil
+}
+
+// NewRun returns a log for another run appending to the same file, with its
+// own run ID. It returns nil for a nil log.
+func (l *Log) NewRun() (*Log, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return New(l.path)
+}
+
+// FromEnv returns the log of the run that started this process, or nil
+func FromEnv() *Log {
+	path := os.Getenv(PathEnvVar)
+	if path == "" {
+		return nil
+	}
+	return &Log{path: path, runID: os.Getenv(RunIDEnvVar)}
+}
+
+// Env returns the environment variables that continue this log in a child process
+func (l *Log) Env() []string {
+	if l == nil {
+		return nil
+	}
+	return []string{PathEnvVar + "=" + l.path, RunIDEnvVar + "=" + l.runID}
+}
+
+// RunID returns the ID shared by the run's records
+func (l *Log) RunID() string {
+	if l == nil {
+		return ""
+	}
+	return l.runID
+}
+
+// Write appends a record, filling in its time and run ID. Each record is a
+// single append so the parent and MCP server processes can share the file.
+func (l *Log) Write(r Record) error {
+	if l == nil {
+		return nil
+	}
+	r.Time = time.Now().UTC()
+	r.RunID = l.runID
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Start writes the run record with the user, host, and skill content hash
+func (l *Log) Start(run Run) error {
+	if l == nil {
+		return nil
+	}
+
+	r := Record{
+		Type:    TypeRun,
+		Skill:   run.Skill,
+		Path:    run.Path,
+		Backend: run.Backend,
+		Argv:    run.Argv,
+	}
+	if u, err := user.Current(); err == nil {
+		r.User = u.Username
+	}
+	r.Host, _ = os.Hostname()
+	r.WorkDir, _ = os.Getwd()
+	if run.Path != "" {
+		hash, err := hashFile(run.Path)
+		if err != nil {
+			return err
+		}
+		r.SHA256 = hash
+	}
+
+	return l.Write(r)
+}
+
+// Stream reads a run's stream-json output, writing a record for each tool
+// call as it completes, and returns the run's summary
+func (l *Log) Stream(stream io.Reader, skillName, skillPath string) (*formatter.Summary, error) {
+	parser := formatter.NewStreamParser(skillName, skillPath, false)
+	events, errs := parser.Parse(stream)
+
+	summary := formatter.NewSummary()
+	var writeErr error
+	for event := range events {
+		summary.Add(event)
+		if data, ok := event.Data.(formatter.ToolCompleteData); ok && writeErr == nil {
+			writeErr = l.Write(toolRecord(data.Operation))
+		}
+	}
+	if err := <-errs; err != nil {
+		return summary, fmt.Errorf("failed to read session for audit log: %w", err)
+	}
+	return summary, writeErr
+}
+
+// Finish writes the result record for a run. The summary may be nil if the
+// run produced no output.
+func (l *Log) Finish(summary *formatter.Summary, runErr error) error {
+	r := Record{Type: TypeResult}
+	if summary != nil {
+		r.Result = summary.Result
+		r.Subtype = summary.Subtype
+		r.IsError = summary.IsError
+		r.Usage = summary.Usage
+		r.SessionID = summary.SessionID
+		r.ElapsedMS = summary.ElapsedMS
+	}
+	if runErr != nil {
+		r.Error = runErr.Error()
+	}
+	return l.Write(r)
+}
+
+// toolRecord records a completed tool call
+func toolRecord(op formatter.ToolOperation) Record {
+	return Record{
+		Type:       TypeTool,
+		Tool:       op.Name,
+		Input:      op.Input,
+		Status:     op.Status,
+		ResultSize: resultSize(op.Result),
+		Error:      op.Error,
+	}
+}
+
+// resultSize returns the size in bytes of a tool's output
+func resultSize(result any) int {
+	switch r := result.(type) {
+	case nil:
+		return 0
+	case string:
+		return len(r)
+	default:
+		data, err := json.Marshal(r)
+		if err != nil {
+			return 0
+		}
+		return len(data)
+	}
+}
+
+// hashFile returns the hex SHA-256 of a file's content
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readRecords reads every record in an audit log
+func readRecords(t *testing.T, path string) []Record {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestLog(t *testing.T) {
+	dir := t.TempDir()
+	skillPath := filepath.Join(dir, "SKILL.md")
+	if err := os.WriteFile(skillPath, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "logs", "audit.jsonl")
+
+	log, err := New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := log.Start(Run{Skill: "greet", Path: skillPath, Backend: "claude", Argv: []string{"claude", "-p"}}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	stream := strings.Join([]string{
+		`{"type":"system","subtype":"init","session_id":"s1","cwd":"/tmp"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"echo hi"}}]}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"hi\n"}]}}`,
+		`{"type":"result","subtype":"success","is_error":false,"result":"Done","session_id":"s1"}`,
+	}, "\n")
+	summary, err := log.Stream(strings.NewReader(stream), "greet", skillPath)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if err := log.Finish(summary, errors.New("exit status 1")); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	records := readRecords(t, path)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3: %+v", len(records), records)
+	}
+	for _, r := range records {
+		if r.RunID != log.RunID() || r.Time.IsZero() {
+			t.Errorf("record missing run ID or time: %+v", r)
+		}
+	}
+
+	run := records[0]
+	// sha256("hello")
+	if run.Type != TypeRun || run.Skill != "greet" || run.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("unexpected run record: %+v", run)
+	}
+	if len(run.Argv) != 2 || run.Host == "" || run.WorkDir == "" {
+		t.Errorf("run record missing argv, host, or workdir: %+v", run)
+	}
+
+	tool := records[1]
+	if tool.Type != TypeTool || tool.Tool != "Bash" || tool.Input["command"] != "echo hi" || tool.ResultSize != 3 {
+		t.Errorf("unexpected tool record: %+v", tool)
+	}
+
+	result := records[2]
+	if result.Type != TypeResult || result.Result != "Done" || result.SessionID != "s1" || result.Error != "exit status 1" {
+		t.Errorf("unexpected result record: %+v", result)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("audit log mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(PathEnvVar, "")
+	if FromEnv() != nil {
+		t.Error("FromEnv() should be nil without " + PathEnvVar)
+	}
+
+	log, err := New(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range log.Env() {
+		name, value, _ := strings.Cut(env, "=")
+		t.Setenv(name, value)
+	}
+
+	child := FromEnv()
+	if err := child.Write(Record{Type: TypePermission, Tool: "Bash", Decision: "deny"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	records := readRecords(t, log.path)
+	if len(records) != 1 || records[0].RunID != log.RunID() {
+		t.Errorf("child record should continue the parent's run: %+v", records)
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var log *Log
+	if err := log.Start(Run{Path: "missing"}); err != nil {
+		t.Errorf("Start() error = %v", err)
+	}
+	if err := log.Finish(nil, nil); err != nil {
+		t.Errorf("Finish() error = %v", err)
+	}
+	if log.Env() != nil {
+		t.Error("Env() should be nil")
+	}
+	if next, err := log.NewRun(); next != nil || err != nil {
+		t.Errorf("NewRun() = %v, %v; want nil", next, err)
+	}
+}
+
+func TestNewRun(t *testing.T) {
+	log, err := New(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := log.NewRun()
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+	if next.path != log.path || next.RunID() == log.RunID() {
+		t.Errorf("NewRun() = %+v, want the same file and a run ID other than %s", next, log.RunID())
+	}
+}
@@ -0,0 +1,221 @@
+// Package batch runs one skill over many inputs with a bounded worker pool.
+// Each run's stream-json output is saved to its own log and parsed
+// separately, so concurrent runs never interleave.
+package batch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+// Input is one run of the skill
+type Input struct {
+	Name string          // Shown in progress and the summary
+	Args arguments.Input // Arguments passed to the skill
+}
+
+// LoadInputs reads inputs from spec. A pattern with glob characters gives one
+// input per matching file, passed as the skill's argument. Otherwise spec is
+// a file ("-" for stdin) with one input per line: a JSON object of named
+// arguments, or the arguments as you'd type them after the skill name.
+// Blank lines and lines starting with # are skipped.
+func LoadInputs(spec string, stdin io.Reader) ([]Input, error) {
+	if strings.ContainsAny(spec, "*?[") {
+		matches, err := filepath.Glob(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --inputs pattern: %w", err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", spec)
+		}
+		sort.Strings(matches)
+		inputs := make([]Input, len(matches))
+		for i, m := range matches {
+			inputs[i] = Input{Name: m, Args: arguments.Input{Positional: []string{m}}}
+		}
+		return inputs, nil
+	}
+
+	r := stdin
+	if spec != "-" {
+		f, err := os.Open(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inputs: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	var inputs []Input
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, "{") {
+			inputs = append(inputs, Input{Name: line, Args: arguments.FromString(line)})
+			continue
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid JSON: %w", spec, lineNum, err)
+		}
+		named := make(map[string]string, len(fields))
+		for name, value := range fields {
+			if s, ok := value.(string); ok {
+				named[name] = s
+				continue
+			}
+			data, _ := json.Marshal(value)
+			named[name] = string(data)
+		}
+		inputs = append(inputs, Input{Name: line, Args: arguments.Input{Named: named}})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read inputs: %w", err)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no inputs in %s", spec)
+	}
+	return inputs, nil
+}
+
+// ExecuteFunc runs the skill for one input, writing stream-json to stdout
+type ExecuteFunc func(ctx context.Context, input Input, stdout, stderr io.Writer) error
+
+// Options configures a batch run
+type Options struct {
+	Jobs      int         // Inputs run at once; less than 1 means 1
+	LogDir    string      // Directory for each input's stream-json log
+	SkillName string      // Reported in parsed events
+	SkillPath string      // Reported in parsed events
+	Execute   ExecuteFunc // Runs the skill for one input
+	Progress  *Progress   // Optional live progress view
+}
+
+// Result is the outcome of one input
+type Result struct {
+	Input   Input
+	Summary *formatter.Summary // Parsed session; nil if the run failed to start
+	Err     error              // Set when the run or its stream failed
+	Elapsed time.Duration
+	LogPath string // stream-json log, readable with skillet --parse
+}
+
+// Status values for results
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed" // Claude finished with an error result
+	StatusError  = "error"  // The run itself failed
+)
+
+// Status summarizes how the input's run ended
+func (r *Result) Status() string {
+	switch {
+	case r.Err != nil:
+		return StatusError
+	case r.Summary == nil || !r.Summary.Completed || r.Summary.IsError:
+		return StatusFailed
+	default:
+		return StatusOK
+	}
+}
+
+// Run runs every input, at most opts.Jobs at a time, and returns the results
+// in input order
+func Run(ctx context.Context, inputs []Input, opts Options) ([]*Result, error) {
+	if err := os.MkdirAll(opts.LogDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	results := make([]*Result, len(inputs))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(inputs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = runOne(ctx, i, inputs[i], opts)
+			}
+		}()
+	}
+	for i := range inputs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results, nil
+}
+
+// runOne runs a single input, saving and parsing its stream as it arrives
+func runOne(ctx context.Context, i int, input Input, opts Options) *Result {
+	start := time.Now()
+	result := &Result{Input: input, LogPath: filepath.Join(opts.LogDir, logName(i, input.Name))}
+	opts.Progress.Start(i, input.Name)
+	defer func() {
+		result.Elapsed = time.Since(start)
+		opts.Progress.Finish(i, result)
+	}()
+
+	if ctx.Err() != nil {
+		result.Err = ctx.Err()
+		return result
+	}
+
+	log, err := os.Create(result.LogPath)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create log: %w", err)
+		return result
+	}
+	defer func() { _ = log.Close() }()
+
+	parser := formatter.NewStreamParser(opts.SkillName, opts.SkillPath, false)
+	execute := func(stdout, stderr io.Writer) error {
+		return opts.Execute(ctx, input, io.MultiWriter(log, stdout), stderr)
+	}
+	result.Summary, result.Err = formatter.RunStream(parser, execute, func(event formatter.StreamEvent) {
+		if tool, ok := event.Data.(formatter.ToolCompleteData); ok {
+			opts.Progress.Tool(i, tool.Operation)
+		}
+	})
+	return result
+}
+
+// unsafeLogChars are replaced in log file names
+var unsafeLogChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// logName returns the log file name for the i'th input: its position, for
+// ordering, and a readable slug of its name
+func logName(i int, name string) string {
+	slug := strings.Trim(unsafeLogChars.ReplaceAllString(name, "-"), "-.")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-.")
+	}
+	if slug == "" {
+		return fmt.Sprintf("%03d.jsonl", i+1)
+	}
+	return fmt.Sprintf("%03d-%s.jsonl", i+1, slug)
+}
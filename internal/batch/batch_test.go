@@ -0,0 +1,220 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+const recording = "../../testdata/parse/tool-operations.jsonl"
+
+func TestLoadInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.txt", "a.txt", "c.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inputsFile := filepath.Join(dir, "inputs")
+	content := "# files to review\nsrc/api.go --mode=fast\n\n{\"file\": \"src/db.go\", \"depth\": 2}\n"
+	if err := os.WriteFile(inputsFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		spec  string
+		stdin string
+		want  []Input
+	}{
+		{
+			name: "glob",
+			spec: filepath.Join(dir, "*.txt"),
+			want: []Input{
+				{Name: filepath.Join(dir, "a.txt"), Args: arguments.Input{Positional: []string{filepath.Join(dir, "a.txt")}}},
+				{Name: filepath.Join(dir, "b.txt"), Args: arguments.Input{Positional: []string{filepath.Join(dir, "b.txt")}}},
+			},
+		},
+		{
+			name: "file",
+			spec: inputsFile,
+			want: []Input{
+				{Name: "src/api.go --mode=fast", Args: arguments.FromString("src/api.go --mode=fast")},
+				{Name: `{"file": "src/db.go", "depth": 2}`, Args: arguments.Input{Named: map[string]string{"file": "src/db.go", "depth": "2"}}},
+			},
+		},
+		{
+			name:  "stdin",
+			spec:  "-",
+			stdin: "one\ntwo\n",
+			want: []Input{
+				{Name: "one", Args: arguments.FromString("one")},
+				{Name: "two", Args: arguments.FromString("two")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadInputs(tt.spec, strings.NewReader(tt.stdin))
+			if err != nil {
+				t.Fatalf("LoadInputs error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadInputs = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadInputs_Errors(t *testing.T) {
+	dir := t.TempDir()
+	badJSON := filepath.Join(dir, "bad")
+	if err := os.WriteFile(badJSON, []byte("ok\n{not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, []byte("# nothing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{filepath.Join(dir, "*.none"), "no files match"},
+		{filepath.Join(dir, "missing"), "failed to read inputs"},
+		{badJSON, "bad:2: invalid JSON"},
+		{empty, "no inputs in"},
+	}
+	for _, tt := range tests {
+		if _, err := LoadInputs(tt.spec, nil); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("LoadInputs(%s) error = %v, want %q", tt.spec, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	data, err := os.ReadFile(recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := []Input{{Name: "src/a.go"}, {Name: "src/b.go"}, {Name: "src/c.go"}}
+
+	var running, maxRunning atomic.Int32
+	execute := func(ctx context.Context, input Input, stdout, stderr io.Writer) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if input.Name == "src/b.go" {
+			_, _ = io.WriteString(stderr, "boom\n")
+			return errors.New("exit status 1")
+		}
+		_, err := stdout.Write(data)
+		return err
+	}
+
+	var progress bytes.Buffer
+	logDir := filepath.Join(t.TempDir(), "logs")
+	results, err := Run(context.Background(), inputs, Options{
+		Jobs:     2,
+		LogDir:   logDir,
+		Execute:  execute,
+		Progress: NewProgress(&progress, len(inputs), false, false),
+	})
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if got := maxRunning.Load(); got != 2 {
+		t.Errorf("max concurrent runs = %d, want 2", got)
+	}
+	var statuses []string
+	for _, r := range results {
+		statuses = append(statuses, r.Status())
+	}
+	if want := []string{StatusOK, StatusError, StatusOK}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+	if err := results[1].Err; err == nil || err.Error() != "exit status 1: boom" {
+		t.Errorf("error = %v, want stderr included", err)
+	}
+	if len(results[0].Summary.Tools) != 3 || !results[0].Summary.Completed {
+		t.Errorf("Expected a parsed summary with tools, got %+v", results[0].Summary)
+	}
+
+	if want := filepath.Join(logDir, "001-src-a.go.jsonl"); results[0].LogPath != want {
+		t.Errorf("LogPath = %s, want %s", results[0].LogPath, want)
+	}
+	if log, err := os.ReadFile(results[2].LogPath); err != nil || !bytes.Equal(log, data) {
+		t.Errorf("Log should hold the raw stream, got %d bytes, %v", len(log), err)
+	}
+
+	if got := progress.String(); !strings.Contains(got, "✓ src/a.go") || !strings.Contains(got, "✗ src/b.go") || !strings.Contains(got, "exit status 1: boom") {
+		t.Errorf("Unexpected progress output:\n%s", got)
+	}
+
+	results[0].Summary.Usage = &formatter.Usage{InputTokens: 100, CacheReadInputTokens: 20, OutputTokens: 30, TotalCostUSD: 0.0125}
+	var summary bytes.Buffer
+	if err := WriteSummary(&summary, results); err != nil {
+		t.Fatalf("WriteSummary error: %v", err)
+	}
+	if got := summary.String(); !strings.Contains(got, "INPUT") || !strings.Contains(got, "120 in / 30 out") || !strings.Contains(got, "$0.0125") || !strings.Contains(got, "2 succeeded, 1 failed") {
+		t.Errorf("Unexpected summary:\n%s", got)
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := Run(ctx, []Input{{Name: "a"}}, Options{
+		LogDir: t.TempDir(),
+		Execute: func(context.Context, Input, io.Writer, io.Writer) error {
+			t.Error("Execute should not run after cancellation")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if results[0].Status() != StatusError {
+		t.Errorf("status = %s, want %s", results[0].Status(), StatusError)
+	}
+}
+
+func TestLogName(t *testing.T) {
+	tests := []struct {
+		i    int
+		name string
+		want string
+	}{
+		{0, "src/api.go", "001-src-api.go.jsonl"},
+		{9, `{"file": "x"}`, "010-file-x.jsonl"},
+		{99, "///", "100.jsonl"},
+		{1, strings.Repeat("a", 60), "002-" + strings.Repeat("a", 40) + ".jsonl"},
+	}
+	for _, tt := range tests {
+		if got := logName(tt.i, tt.name); got != tt.want {
+			t.Errorf("logName(%d, %q) = %q, want %q", tt.i, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package batch
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+// maxNameWidth truncates input names in progress rows
+const maxNameWidth = 40
+
+// Progress shows a row for each running input, redrawn in place on a
+// terminal, and prints a line as each input finishes. A nil Progress shows
+// nothing.
+type Progress struct {
+	w     io.Writer
+	live  bool // Redraw running rows in place
+	total int
+
+	mu      sync.Mutex
+	running map[int]*row
+	done    int
+	failed  int
+	drawn   int // Lines in the live area to clear before redrawing
+	stop    chan struct{}
+
+	okStyle, failStyle, dimStyle lipgloss.Style
+}
+
+// row is a running input
+type row struct {
+	name     string
+	start    time.Time
+	activity string // Most recent tool call
+}
+
+// NewProgress creates a progress view for total inputs. live redraws rows in
+// place and should only be set when w is a terminal.
+func NewProgress(w io.Writer, total int, live, useColors bool) *Progress {
+	p := &Progress{
+		w:         w,
+		live:      live,
+		total:     total,
+		running:   make(map[int]*row),
+		stop:      make(chan struct{}),
+		okStyle:   lipgloss.NewStyle(),
+		failStyle: lipgloss.NewStyle(),
+		dimStyle:  lipgloss.NewStyle(),
+	}
+	if useColors {
+		p.okStyle = p.okStyle.Foreground(lipgloss.Color("2"))     // Green
+		p.failStyle = p.failStyle.Foreground(lipgloss.Color("1")) // Red
+		p.dimStyle = p.dimStyle.Foreground(lipgloss.Color("8"))   // Dim gray
+	}
+	if live {
+		// Keep elapsed times ticking between events
+		go func() {
+			ticker := time.NewTicker(500 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					p.mu.Lock()
+					p.redraw()
+					p.mu.Unlock()
+				case <-p.stop:
+					return
+				}
+			}
+		}()
+	}
+	return p
+}
+
+// Start shows input i as running
+func (p *Progress) Start(i int, name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running[i] = &row{name: truncate(name), start: time.Now()}
+	p.redraw()
+}
+
+// Tool shows input i's latest tool call
+func (p *Progress) Tool(i int, op formatter.ToolOperation) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if r, ok := p.running[i]; ok {
+		r.activity = strings.TrimSpace(op.Name + " " + op.Target)
+		p.redraw()
+	}
+}
+
+// Finish prints input i's outcome above the running rows
+func (p *Progress) Finish(i int, result *Result) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.running, i)
+	p.done++
+
+	p.clear()
+	elapsed := p.dimStyle.Render(formatElapsed(result.Elapsed))
+	name := truncate(result.Input.Name)
+	switch result.Status() {
+	case StatusOK:
+		_, _ = fmt.Fprintf(p.w, "%s %s %s\n", p.okStyle.Render("✓"), name, elapsed)
+	default:
+		p.failed++
+		_, _ = fmt.Fprintf(p.w, "%s %s %s %s\n", p.failStyle.Render("✗"), name, elapsed, p.failStyle.Render(failure(result)))
+	}
+	p.redraw()
+}
+
+// Close stops redrawing and removes the running rows
+func (p *Progress) Close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.live {
+		close(p.stop)
+		p.live = false
+	}
+	p.clear()
+}
+
+// clear erases the live area; the caller holds mu
+func (p *Progress) clear() {
+	for ; p.drawn > 0; p.drawn-- {
+		_, _ = fmt.Fprint(p.w, "\x1b[1A\x1b[2K")
+	}
+}
+
+// redraw replaces the live area with the running rows and a count; the
+// caller holds mu
+func (p *Progress) redraw() {
+	if !p.live {
+		return
+	}
+	p.clear()
+
+	indexes := make([]int, 0, len(p.running))
+	for i := range p.running {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		r := p.running[i]
+		line := fmt.Sprintf("  %s %s %s", p.dimStyle.Render("…"), r.name, p.dimStyle.Render(formatElapsed(time.Since(r.start))))
+		if r.activity != "" {
+			line += " " + p.dimStyle.Render(truncate(r.activity))
+		}
+		_, _ = fmt.Fprintln(p.w, line)
+		p.drawn++
+	}
+
+	status := fmt.Sprintf("%d/%d done", p.done, p.total)
+	if p.failed > 0 {
+		status += ", " + p.failStyle.Render(fmt.Sprintf("%d failed", p.failed))
+	}
+	_, _ = fmt.Fprintln(p.w, status)
+	p.drawn++
+}
+
+// failure returns a one-line reason a result didn't succeed
+func failure(r *Result) string {
+	var msg string
+	switch {
+	case r.Err != nil:
+		msg = r.Err.Error()
+	case r.Summary != nil && r.Summary.IsError:
+		msg = r.Summary.Result
+	default:
+		msg = "no result"
+	}
+	msg, _, _ = strings.Cut(strings.TrimSpace(msg), "\n")
+	return truncate(msg)
+}
+
+// truncate shortens s to maxNameWidth runes
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxNameWidth {
+		return s
+	}
+	return string(runes[:maxNameWidth-1]) + "…"
+}
+
+// formatElapsed formats a duration for progress rows and the summary table
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
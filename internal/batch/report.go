@@ -0,0 +1,43 @@
+package batch
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteSummary writes a table of each input's status, elapsed time, tokens,
+// cost, and log, followed by the totals
+func WriteSummary(w io.Writer, results []*Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "INPUT\tSTATUS\tELAPSED\tTOKENS\tCOST\tLOG")
+
+	ok := 0
+	var input, output int
+	var cost float64
+	for _, r := range results {
+		status := r.Status()
+		if status == StatusOK {
+			ok++
+		}
+		tokens, spent := "-", "-"
+		if r.Summary != nil && r.Summary.Usage != nil {
+			u := r.Summary.Usage
+			in := u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens
+			tokens = fmt.Sprintf("%d in / %d out", in, u.OutputTokens)
+			input += in
+			output += u.OutputTokens
+			if u.TotalCostUSD > 0 {
+				spent = fmt.Sprintf("$%.4f", u.TotalCostUSD)
+				cost += u.TotalCostUSD
+			}
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", truncate(r.Input.Name), status, formatElapsed(r.Elapsed), tokens, spent, r.LogPath)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d succeeded, %d failed (%d in / %d out tokens, $%.4f)\n", ok, len(results)-ok, input, output, cost)
+	return err
+}
@@ -0,0 +1,165 @@
+// Package budget watches a run's stream-json output and stops the run when
+// it goes over its cost or turn limit.
+package budget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+// Limits caps a single run. Zero means no limit.
+type Limits struct {
+	MaxCost  float64 // USD, estimated from token usage as the run streams
+	MaxTurns int     // Assistant turns
+}
+
+// Enabled reports whether any limit is set
+func (l Limits) Enabled() bool {
+	return l.MaxCost > 0 || l.MaxTurns > 0
+}
+
+// Limit names, as in flags and frontmatter
+const (
+	LimitCost  = "max-cost"
+	LimitTurns = "max-turns"
+)
+
+// ExceededError is returned when a run is stopped for going over a limit
+type ExceededError struct {
+	Limit string  // LimitCost or LimitTurns
+	Value float64 // Spent when the run was stopped
+	Max   float64
+}
+
+func (e *ExceededError) Error() string {
+	if e.Limit == LimitCost {
+		return fmt.Sprintf("run stopped: estimated cost $%.4f exceeded %s $%.2f", e.Value, e.Limit, e.Max)
+	}
+	return fmt.Sprintf("run stopped: %d turns exceeded %s %d", int(e.Value), e.Limit, int(e.Max))
+}
+
+// Watcher is an io.Writer that reads the agent's stream-json output as it's
+// written and calls cancel the first time a limit is exceeded. A nil
+// Watcher has no limits.
+type Watcher struct {
+	limits Limits
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	buf   []byte
+	model string
+	costs map[string]float64 // Estimated cost of each assistant message, by ID
+	turns int
+	err   error
+}
+
+// NewWatcher creates a watcher that calls cancel when limits are exceeded
+func NewWatcher(limits Limits, cancel context.CancelFunc) *Watcher {
+	return &Watcher{
+		limits: limits,
+		cancel: cancel,
+		costs:  make(map[string]float64),
+	}
+}
+
+// streamLine is the part of a stream-json line the watcher reads
+type streamLine struct {
+	Type    string `json:"type"`
+	Model   string `json:"model"`
+	Message *struct {
+		ID    string           `json:"id"`
+		Model string           `json:"model"`
+		Usage *formatter.Usage `json:"usage"`
+	} `json:"message"`
+}
+
+// Write reads complete lines from p and never fails, so the agent's output
+// isn't interrupted
+func (w *Watcher) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.readLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// readLine updates the running totals from one line of the stream
+func (w *Watcher) readLine(line []byte) {
+	var msg streamLine
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+	switch msg.Type {
+	case "system":
+		if msg.Model != "" {
+			w.model = msg.Model
+		}
+	case "assistant":
+		if msg.Message == nil {
+			return
+		}
+		// Each content block of a message arrives on its own line with the
+		// message's usage so far, so count the message once and keep its
+		// latest usage
+		id := msg.Message.ID
+		if id == "" {
+			id = fmt.Sprintf("turn-%d", w.turns+1)
+		}
+		if _, seen := w.costs[id]; !seen {
+			w.turns++
+		}
+		model := msg.Message.Model
+		if model == "" {
+			model = w.model
+		}
+		w.costs[id] = EstimateCost(model, msg.Message.Usage)
+		w.check()
+	}
+}
+
+// check records and acts on the first exceeded limit
+func (w *Watcher) check() {
+	if w.err != nil {
+		return
+	}
+	if w.limits.MaxTurns > 0 && w.turns > w.limits.MaxTurns {
+		w.err = &ExceededError{Limit: LimitTurns, Value: float64(w.turns), Max: float64(w.limits.MaxTurns)}
+	} else if cost := w.cost(); w.limits.MaxCost > 0 && cost > w.limits.MaxCost {
+		w.err = &ExceededError{Limit: LimitCost, Value: cost, Max: w.limits.MaxCost}
+	}
+	if w.err != nil && w.cancel != nil {
+		w.cancel()
+	}
+}
+
+// cost is the estimated cost of the messages so far
+func (w *Watcher) cost() float64 {
+	var total float64
+	for _, c := range w.costs {
+		total += c
+	}
+	return total
+}
+
+// Err returns the *ExceededError for the limit that stopped the run, or nil
+func (w *Watcher) Err() error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
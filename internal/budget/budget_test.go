@@ -0,0 +1,122 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+// assistantLine is a stream-json assistant message with usage
+func assistantLine(id string, input, output int) string {
+	return fmt.Sprintf(`{"type":"assistant","message":{"id":%q,"role":"assistant","content":[],"usage":{"input_tokens":%d,"output_tokens":%d}}}`, id, input, output) + "\n"
+}
+
+func TestWatcher(t *testing.T) {
+	start := `{"type":"system","subtype":"init","model":"claude-sonnet-4-5-20250929"}` + "\n"
+
+	tests := []struct {
+		name      string
+		limits    Limits
+		stream    string
+		wantLimit string // Empty means the run isn't stopped
+	}{
+		{
+			name:   "under limits",
+			limits: Limits{MaxCost: 1, MaxTurns: 2},
+			stream: start + assistantLine("msg_1", 1000, 100) + assistantLine("msg_2", 1000, 100),
+		},
+		{
+			name:      "too many turns",
+			limits:    Limits{MaxTurns: 2},
+			stream:    start + assistantLine("msg_1", 10, 10) + assistantLine("msg_2", 10, 10) + assistantLine("msg_3", 10, 10),
+			wantLimit: LimitTurns,
+		},
+		{
+			name:   "content blocks of one message are one turn",
+			limits: Limits{MaxTurns: 1},
+			stream: start + assistantLine("msg_1", 10, 5) + assistantLine("msg_1", 10, 10),
+		},
+		{
+			// 100k output tokens at $15/MTok
+			name:      "over cost",
+			limits:    Limits{MaxCost: 1},
+			stream:    start + assistantLine("msg_1", 1000, 50_000) + assistantLine("msg_2", 1000, 50_000),
+			wantLimit: LimitCost,
+		},
+		{
+			name:   "repeated usage isn't counted twice",
+			limits: Limits{MaxCost: 1},
+			stream: start + assistantLine("msg_1", 1000, 60_000) + assistantLine("msg_1", 1000, 60_000),
+		},
+		{
+			name:   "no limits",
+			stream: start + assistantLine("msg_1", 1000, 1_000_000),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancelled := 0
+			w := NewWatcher(tt.limits, func() { cancelled++ })
+
+			// Split writes mid-line, as a pipe might
+			stream := tt.stream
+			for len(stream) > 0 {
+				n := min(7, len(stream))
+				if _, err := w.Write([]byte(stream[:n])); err != nil {
+					t.Fatalf("Write error: %v", err)
+				}
+				stream = stream[n:]
+			}
+
+			var exceeded *ExceededError
+			if tt.wantLimit == "" {
+				if w.Err() != nil || cancelled != 0 {
+					t.Errorf("Err() = %v, cancelled %d times; want no limit exceeded", w.Err(), cancelled)
+				}
+				return
+			}
+			if !errors.As(w.Err(), &exceeded) || exceeded.Limit != tt.wantLimit {
+				t.Fatalf("Err() = %v, want %s exceeded", w.Err(), tt.wantLimit)
+			}
+			if cancelled != 1 {
+				t.Errorf("cancel called %d times, want 1", cancelled)
+			}
+			if !strings.Contains(exceeded.Error(), tt.wantLimit) {
+				t.Errorf("Error() = %q, want it to name %s", exceeded.Error(), tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestWatcher_Nil(t *testing.T) {
+	var w *Watcher
+	if err := w.Err(); err != nil {
+		t.Errorf("nil Watcher Err() = %v, want nil", err)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		model string
+		usage *formatter.Usage
+		want  float64
+	}{
+		{"claude-sonnet-4-5-20250929", &formatter.Usage{InputTokens: 1_000_000, OutputTokens: 1_000_000}, 18},
+		{"claude-opus-4-5-20251101", &formatter.Usage{InputTokens: 1_000_000, OutputTokens: 1_000_000}, 30},
+		{"claude-opus-4-1-20250805", &formatter.Usage{OutputTokens: 1_000_000}, 75},
+		{"claude-haiku-4-5", &formatter.Usage{InputTokens: 1_000_000}, 1},
+		{"claude-3-5-haiku-20241022", &formatter.Usage{InputTokens: 1_000_000}, 0.8},
+		{"claude-sonnet-4-5", &formatter.Usage{CacheCreationInputTokens: 1_000_000, CacheReadInputTokens: 1_000_000}, 3.75 + 0.3},
+		{"unknown-model", &formatter.Usage{InputTokens: 1_000_000}, 3},
+		{"claude-sonnet-4-5", nil, 0},
+	}
+	for _, tt := range tests {
+		if got := EstimateCost(tt.model, tt.usage); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateCost(%q, %+v) = %v, want %v", tt.model, tt.usage, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package budget
+
+import (
+	"strings"
+
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+// price is a model's cost in USD per million tokens
+type price struct {
+	match  string // Substring of the model ID
+	input  float64
+	output float64
+}
+
+// prices are checked in order, so older models come before the newer ones
+// they'd otherwise match
+var prices = []price{
+	{"claude-3-opus", 15, 75},
+	{"claude-opus-4-2025", 15, 75},
+	{"claude-opus-4-1", 15, 75},
+	{"opus", 5, 25},
+	{"claude-3-haiku", 0.25, 1.25},
+	{"claude-3-5-haiku", 0.8, 4},
+	{"haiku", 1, 5},
+	{"sonnet", 3, 15},
+}
+
+// defaultPrice is used for models not in the table
+var defaultPrice = price{"", 3, 15}
+
+// Cache writes and reads are priced relative to input tokens
+const (
+	cacheWriteMultiplier = 1.25
+	cacheReadMultiplier  = 0.1
+)
+
+// EstimateCost estimates the cost in USD of usage on model from list prices.
+// The result message's total_cost_usd is authoritative once the run ends.
+func EstimateCost(model string, usage *formatter.Usage) float64 {
+	if usage == nil {
+		return 0
+	}
+	p := priceFor(model)
+	input := float64(usage.InputTokens) +
+		float64(usage.CacheCreationInputTokens)*cacheWriteMultiplier +
+		float64(usage.CacheReadInputTokens)*cacheReadMultiplier
+	return (input*p.input + float64(usage.OutputTokens)*p.output) / 1_000_000
+}
+
+// priceFor returns the first price matching model
+func priceFor(model string) price {
+	for _, p := range prices {
+		if strings.Contains(model, p.match) {
+			return p
+		}
+	}
+	return defaultPrice
+}
@@ -5,19 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
+	"github.com/martinemde/skillet/internal/arguments"
 	"github.com/martinemde/skillet/internal/frontmatter"
 	"github.com/martinemde/skillet/internal/validation"
 	"gopkg.in/yaml.v3"
 )
 
-var (
-	// argumentsRegex matches $ARGUMENTS variable references
-	argumentsRegex = regexp.MustCompile(`\$ARGUMENTS`)
-)
-
 // Command represents a parsed command .md file
 type Command struct {
 	// Frontmatter fields
@@ -29,6 +24,11 @@ type Command struct {
 	Model                  string `yaml:"model,omitempty"`
 	DisableModelInvocation bool   `yaml:"disable-model-invocation,omitempty"`
 
+	// Skillet extension fields
+	Arguments []arguments.Declaration `yaml:"arguments,omitempty"` // Typed, named arguments
+	MaxCost   float64                 `yaml:"max-cost,omitempty"`  // Stop the run past this estimated cost in USD
+	MaxTurns  int                     `yaml:"max-turns,omitempty"` // Stop the run past this many turns
+
 	// Derived fields
 	Name    string // Derived from filename (without .md)
 	Content string // Markdown content after frontmatter
@@ -36,14 +36,20 @@ type Command struct {
 }
 
 // Parse reads and parses a command .md file
-func Parse(commandPath string, arguments string) (*Command, error) {
-	return ParseWithBaseDir(commandPath, "", arguments)
+func Parse(commandPath string, args string) (*Command, error) {
+	return ParseWithBaseDir(commandPath, "", args)
 }
 
 // ParseWithBaseDir reads and parses a command .md file with an optional custom base directory
 // If baseDir is empty, it defaults to the directory containing the command file
-// The arguments string replaces $ARGUMENTS in the command content
-func ParseWithBaseDir(commandPath, baseDir, arguments string) (*Command, error) {
+// The args string replaces $ARGUMENTS in the command content
+func ParseWithBaseDir(commandPath, baseDir, args string) (*Command, error) {
+	return ParseWithInput(commandPath, baseDir, arguments.FromString(args))
+}
+
+// ParseWithInput reads and parses a command .md file, binding input to the declared arguments.
+// Invalid arguments are reported as an *arguments.Error that includes the command's usage.
+func ParseWithInput(commandPath, baseDir string, input arguments.Input) (*Command, error) {
 	// Resolve absolute path
 	absPath, err := filepath.Abs(commandPath)
 	if err != nil {
@@ -74,8 +80,25 @@ func ParseWithBaseDir(commandPath, baseDir, arguments string) (*Command, error)
 	cmd.Name = name
 	cmd.BaseDir = baseDir
 
+	if err := arguments.Validate(cmd.Arguments); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if cmd.MaxCost < 0 {
+		return nil, fmt.Errorf("validation failed: max-cost must not be negative, got %g", cmd.MaxCost)
+	}
+	if cmd.MaxTurns < 0 {
+		return nil, fmt.Errorf("validation failed: max-turns must not be negative, got %d", cmd.MaxTurns)
+	}
+
+	// Bind and validate arguments
+	values, err := arguments.Resolve(cmd.Arguments, input)
+	if err != nil {
+		return nil, &arguments.Error{Err: err, Usage: cmd.Usage()}
+	}
+
 	// Interpolate variables
-	cmd.Content = interpolateVariables(cmd.Content, baseDir, arguments)
+	cmd.Content = validation.InterpolateBaseDir(cmd.Content, baseDir)
+	cmd.Content = arguments.Interpolate(cmd.Content, cmd.Arguments, values)
 
 	// If description is not set, use the first non-empty line of content
 	if cmd.Description == "" {
@@ -109,21 +132,9 @@ func parseFrontmatter(data string) (*Command, error) {
 	return cmd, nil
 }
 
-// interpolateVariables replaces variables like {baseDir} and $ARGUMENTS with actual values
-// If $ARGUMENTS is not present in the content and arguments are provided,
-// appends "ARGUMENTS: <value>" to the content per the agentskills.io spec.
-func interpolateVariables(content, baseDir, arguments string) string {
-	content = validation.InterpolateBaseDir(content, baseDir)
-
-	if argumentsRegex.MatchString(content) {
-		// $ARGUMENTS is present, replace it with arguments
-		content = argumentsRegex.ReplaceAllString(content, arguments)
-	} else if arguments != "" {
-		// $ARGUMENTS not present but arguments provided, append them
-		content = content + "\n\nARGUMENTS: " + arguments
-	}
-
-	return content
+// Usage returns a usage message describing the command's arguments
+func (c *Command) Usage() string {
+	return arguments.Usage(c.Name, c.ArgumentHint, c.Arguments)
 }
 
 // extractFirstLine gets the first non-empty, non-heading line as a description
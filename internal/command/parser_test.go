@@ -4,6 +4,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/validation"
 )
 
 func TestParse_SimpleCommand(t *testing.T) {
@@ -211,7 +214,7 @@ func TestInterpolateVariables(t *testing.T) {
 	baseDir := "/path/to/command"
 	content := "Base directory is {baseDir} and config is at {baseDir}/config.json"
 
-	result := interpolateVariables(content, baseDir, "")
+	result := validation.InterpolateBaseDir(content, baseDir)
 
 	expected := "Base directory is /path/to/command and config is at /path/to/command/config.json"
 	if result != expected {
@@ -221,7 +224,7 @@ func TestInterpolateVariables(t *testing.T) {
 
 func TestInterpolateVariables_Arguments(t *testing.T) {
 	content := "Process file $ARGUMENTS with options"
-	result := interpolateVariables(content, "/base", "myfile.txt --verbose")
+	result := arguments.Interpolate(content, nil, arguments.Values{Raw: "myfile.txt --verbose"})
 
 	expected := "Process file myfile.txt --verbose with options"
 	if result != expected {
@@ -231,7 +234,7 @@ func TestInterpolateVariables_Arguments(t *testing.T) {
 
 func TestInterpolateVariables_MultipleArguments(t *testing.T) {
 	content := "First: $ARGUMENTS, Second: $ARGUMENTS"
-	result := interpolateVariables(content, "/base", "arg1 arg2")
+	result := arguments.Interpolate(content, nil, arguments.Values{Raw: "arg1 arg2"})
 
 	expected := "First: arg1 arg2, Second: arg1 arg2"
 	if result != expected {
@@ -241,7 +244,7 @@ func TestInterpolateVariables_MultipleArguments(t *testing.T) {
 
 func TestInterpolateVariables_EmptyArguments(t *testing.T) {
 	content := "Process $ARGUMENTS here"
-	result := interpolateVariables(content, "/base", "")
+	result := arguments.Interpolate(content, nil, arguments.Values{})
 
 	expected := "Process  here"
 	if result != expected {
@@ -347,7 +350,7 @@ func TestParse_WithArguments(t *testing.T) {
 
 func TestInterpolateVariables_AppendArgumentsWhenNotPresent(t *testing.T) {
 	content := "No arguments placeholder in content"
-	result := interpolateVariables(content, "/base", "myarg --flag")
+	result := arguments.Interpolate(content, nil, arguments.Values{Raw: "myarg --flag"})
 
 	expected := "No arguments placeholder in content\n\nARGUMENTS: myarg --flag"
 	if result != expected {
@@ -357,7 +360,7 @@ func TestInterpolateVariables_AppendArgumentsWhenNotPresent(t *testing.T) {
 
 func TestInterpolateVariables_NoAppendWhenArgumentsEmpty(t *testing.T) {
 	content := "No arguments placeholder in content"
-	result := interpolateVariables(content, "/base", "")
+	result := arguments.Interpolate(content, nil, arguments.Values{})
 
 	// Content should remain unchanged when arguments are empty
 	if result != content {
@@ -367,7 +370,7 @@ func TestInterpolateVariables_NoAppendWhenArgumentsEmpty(t *testing.T) {
 
 func TestInterpolateVariables_NoAppendWhenPlaceholderPresent(t *testing.T) {
 	content := "Use $ARGUMENTS here"
-	result := interpolateVariables(content, "/base", "myarg")
+	result := arguments.Interpolate(content, nil, arguments.Values{Raw: "myarg"})
 
 	// $ARGUMENTS should be replaced, not appended
 	expected := "Use myarg here"
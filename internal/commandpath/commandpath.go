@@ -42,10 +42,10 @@ func NewWithWorkDir(workDir string) (*Path, error) {
 		return nil, err
 	}
 
-	// Load plugin sources (priority 2+, after project and user)
+	// Load plugin sources (lowest priority, after project, user, and search path)
 	plugins, err := pluginpath.Load()
 	if err == nil && len(plugins) > 0 {
-		pluginSources := pluginpath.CommandSources(plugins, 2)
+		pluginSources := pluginpath.CommandSources(plugins, len(p.Sources()))
 		p.AppendSources(pluginSources)
 	}
 
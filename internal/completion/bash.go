@@ -14,8 +14,8 @@ _skillet_completions() {
     local cur prev words cword
     _init_completion || return
 
-    local flags="--version --help --list --verbose --debug --usage --dry-run -q --quiet --parse --prompt --model --allowed-tools --permission-mode --output-format --color"
-    local bool_flags="--version --help --list --verbose --debug --usage --dry-run -q --quiet"
+    local flags="--version --help --list --verbose --debug --usage --dry-run -q --quiet --parse --prompt --model --allowed-tools --permission-mode --output-format --format --json-schema --backend --audit-log --max-cost --max-turns --offline --update --color --profile"
+    local bool_flags="--version --help --list --verbose --debug --usage --dry-run -q --quiet --offline --update"
 
     case "${prev}" in
         --color)
@@ -38,11 +38,19 @@ _skillet_completions() {
             COMPREPLY=($(compgen -W "{{.OutputFormatValues}}" -- "${cur}"))
             return 0
             ;;
-        --parse)
+        --format)
+            COMPREPLY=($(compgen -W "{{.FormatValues}}" -- "${cur}"))
+            return 0
+            ;;
+        --parse|--json-schema|--audit-log)
             _filedir
             return 0
             ;;
-        --prompt)
+        --backend)
+            COMPREPLY=($(compgen -W "claude replay:" -- "${cur}"))
+            return 0
+            ;;
+        --prompt|--profile|--max-cost|--max-turns)
             # Free text, no completion
             return 0
             ;;
@@ -96,12 +104,14 @@ func GenerateBash(w io.Writer) error {
 		ToolValues           string
 		PermissionModeValues string
 		OutputFormatValues   string
+		FormatValues         string
 	}{
 		ColorValues:          strings.Join(ColorValues, " "),
 		ModelValues:          strings.Join(ModelValues, " "),
 		ToolValues:           strings.Join(ToolValues, " "),
 		PermissionModeValues: strings.Join(PermissionModeValues, " "),
 		OutputFormatValues:   strings.Join(OutputFormatValues, " "),
+		FormatValues:         strings.Join(FormatValues, " "),
 	}
 
 	return tmpl.Execute(w, data)
@@ -10,6 +10,7 @@ import (
 	"github.com/martinemde/skillet/internal/command"
 	"github.com/martinemde/skillet/internal/commandpath"
 	"github.com/martinemde/skillet/internal/discovery"
+	"github.com/martinemde/skillet/internal/index"
 	"github.com/martinemde/skillet/internal/skillpath"
 )
 
@@ -47,6 +48,9 @@ var (
 	OutputFormatValues = []string{
 		"stream-json", "json", "text",
 	}
+
+	// FormatValues are valid values for --format flag
+	FormatValues = []string{"json", "ndjson"}
 )
 
 // Generate writes the completion script for the given shell to the writer.
@@ -70,13 +74,22 @@ func SupportedShells() []string {
 
 // CompleteNames returns skill and command names matching the given prefix.
 // Names are returned as qualified names (namespace:name or just name).
+// Discovery goes through the index so completion stays fast with many plugins.
 func CompleteNames(prefix string) []string {
 	var names []string
 
+	ix, err := index.Default()
+	if err != nil {
+		ix = nil
+	}
+
 	// Discover skills
 	skillPath, err := skillpath.New()
 	if err == nil {
 		skillDisc := discovery.New(skillPath)
+		if ix != nil {
+			skillDisc = discovery.NewWithFinder(skillPath, ix.SkillFinder())
+		}
 		skills, err := skillDisc.Discover()
 		if err == nil {
 			for _, s := range skills {
@@ -94,6 +107,9 @@ func CompleteNames(prefix string) []string {
 	cmdPath, err := commandpath.New()
 	if err == nil {
 		cmdDisc := command.NewDiscoverer(cmdPath)
+		if ix != nil {
+			cmdDisc = command.NewDiscovererWithFinder(cmdPath, ix.CommandFinder())
+		}
 		commands, err := cmdDisc.Discover()
 		if err == nil {
 			for _, c := range commands {
@@ -107,6 +123,10 @@ func CompleteNames(prefix string) []string {
 		}
 	}
 
+	if ix != nil {
+		_ = ix.Save()
+	}
+
 	sort.Strings(names)
 	return names
 }
@@ -23,7 +23,7 @@ function __skillet_needs_command
             continue
         end
         switch $token
-            case '--parse' '--prompt' '--model' '--allowed-tools' '--permission-mode' '--output-format' '--color'
+            case '--parse' '--prompt' '--model' '--allowed-tools' '--permission-mode' '--output-format' '--format' '--json-schema' '--backend' '--audit-log' '--max-cost' '--max-turns' '--color' '--profile'
                 set skip_next 1
             case '-*'
                 # Boolean flag, continue
@@ -50,6 +50,8 @@ complete -c skillet -l debug -d 'Print raw JSON stream to stderr'
 complete -c skillet -l usage -d 'Show token usage statistics'
 complete -c skillet -l dry-run -d 'Show the command that would be executed without running it'
 complete -c skillet -s q -l quiet -d 'Quiet mode - suppress all output except errors'
+complete -c skillet -l offline -d 'Resolve URLs and git sources only from the cache'
+complete -c skillet -l update -d 'Accept remote skills that changed since skillet.lock'
 
 # Flags with values
 complete -c skillet -l parse -r -F -d 'Parse and format stream-json input'
@@ -58,7 +60,14 @@ complete -c skillet -l model -r -f -a '{{.ModelValues}}' -d 'Override model to u
 complete -c skillet -l allowed-tools -r -f -a '{{.ToolValues}}' -d 'Override allowed tools'
 complete -c skillet -l permission-mode -r -f -a '{{.PermissionModeValues}}' -d 'Override permission mode'
 complete -c skillet -l output-format -r -f -a '{{.OutputFormatValues}}' -d 'Override output format'
+complete -c skillet -l format -r -f -a '{{.FormatValues}}' -d 'Normalized output format'
+complete -c skillet -l json-schema -r -F -d 'JSON Schema for structured output'
+complete -c skillet -l backend -r -f -a 'claude replay:' -d 'Agent backend'
+complete -c skillet -l audit-log -r -F -d 'Append a JSONL audit log of the run'
+complete -c skillet -l max-cost -r -f -d 'Stop the run past this estimated cost in USD'
+complete -c skillet -l max-turns -r -f -d 'Stop the run after this many turns'
 complete -c skillet -l color -r -f -a '{{.ColorValues}}' -d 'Control color output'
+complete -c skillet -l profile -r -f -d 'Apply a named profile from the config files'
 
 # Skill and command names (only when no positional arg yet)
 complete -c skillet -n '__skillet_needs_command' -a '(__skillet_complete_names)' -d 'Skill or command'
@@ -77,12 +86,14 @@ func GenerateFish(w io.Writer) error {
 		ToolValues           string
 		PermissionModeValues string
 		OutputFormatValues   string
+		FormatValues         string
 	}{
 		ColorValues:          strings.Join(ColorValues, " "),
 		ModelValues:          strings.Join(ModelValues, " "),
 		ToolValues:           strings.Join(ToolValues, " "),
 		PermissionModeValues: strings.Join(PermissionModeValues, " "),
 		OutputFormatValues:   strings.Join(OutputFormatValues, " "),
+		FormatValues:         strings.Join(FormatValues, " "),
 	}
 
 	return tmpl.Execute(w, data)
@@ -28,7 +28,16 @@ _skillet() {
         '--allowed-tools[Override allowed tools]:tools:({{.ToolValues}})' \
         '--permission-mode[Override permission mode]:mode:({{.PermissionModeValues}})' \
         '--output-format[Override output format]:format:({{.OutputFormatValues}})' \
+        '--format[Normalized output format]:format:({{.FormatValues}})' \
+        '--json-schema[JSON Schema for structured output]:schema:_files' \
+        '--backend[Agent backend]:backend:(claude replay\:)' \
+        '--audit-log[Append a JSONL audit log of the run]:file:_files' \
+        '--max-cost[Stop the run past this estimated cost in USD]:usd:' \
+        '--max-turns[Stop the run after this many turns]:turns:' \
+        '--offline[Resolve URLs and git sources only from the cache]' \
+        '--update[Accept remote skills that changed since skillet.lock]' \
         '--color[Control color output]:color:({{.ColorValues}})' \
+        '--profile[Apply a named profile from the config files]:profile:' \
         '1:skill or command:_skillet_names' \
         '*::arguments:_files'
 }
@@ -60,12 +69,14 @@ func GenerateZsh(w io.Writer) error {
 		ToolValues           string
 		PermissionModeValues string
 		OutputFormatValues   string
+		FormatValues         string
 	}{
 		ColorValues:          strings.Join(ColorValues, " "),
 		ModelValues:          strings.Join(ModelValues, " "),
 		ToolValues:           strings.Join(ToolValues, " "),
 		PermissionModeValues: strings.Join(PermissionModeValues, " "),
 		OutputFormatValues:   strings.Join(OutputFormatValues, " "),
+		FormatValues:         strings.Join(FormatValues, " "),
 	}
 
 	return tmpl.Execute(w, data)
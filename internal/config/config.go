@@ -0,0 +1,348 @@
+// Package config loads skillet's configuration. Settings are layered:
+// $XDG_CONFIG_HOME/skillet/config.yaml (~/.config/skillet/config.yaml), then
+// the project's .skillet.yaml, then the selected profile, then SKILLET_*
+// environment variables. Command line flags override all of them.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/executor"
+	"github.com/martinemde/skillet/internal/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FileName is the name of the config file in skillet's config directory
+	FileName = "config.yaml"
+	// ProjectFileName is the project config file, found in the working
+	// directory or a parent up to the git root
+	ProjectFileName = ".skillet.yaml"
+	// ProfileEnvVar selects a profile when --profile isn't given
+	ProfileEnvVar = "SKILLET_PROFILE"
+)
+
+// Settings are the values a project file, profile, or environment variable
+// can set. Flags of the same name override them.
+type Settings struct {
+	// Backend is the default agent backend name
+	Backend string `yaml:"backend,omitempty"`
+	// AuditLog is the default --audit-log path
+	AuditLog string `yaml:"audit-log,omitempty"`
+	// SearchPath adds directories to search for skills and commands
+	SearchPath []SearchDir `yaml:"search-path,omitempty"`
+	// Color is the default --color mode
+	Color string `yaml:"color,omitempty"`
+	// Verbose is the default for --verbose
+	Verbose *bool `yaml:"verbose,omitempty"`
+	// Usage is the default for --usage
+	Usage *bool `yaml:"usage,omitempty"`
+	// Model is the default --model
+	Model string `yaml:"model,omitempty"`
+	// PermissionMode is the default --permission-mode
+	PermissionMode string `yaml:"permission-mode,omitempty"`
+}
+
+// Config holds settings from the config files
+type Config struct {
+	Settings `yaml:",inline"`
+	// Backends are named backends selectable with --backend
+	Backends map[string]executor.Backend `yaml:"backends,omitempty"`
+	// Profiles are named settings selectable with --profile
+	Profiles map[string]Settings `yaml:"profiles,omitempty"`
+}
+
+// Search path positions
+const (
+	PositionPrepend = "prepend" // Searched before project skills
+	PositionAppend  = "append"  // Searched after user skills, before plugins
+)
+
+// SearchDir is an extra directory with skills/ and commands/ subdirectories
+type SearchDir struct {
+	Path      string `yaml:"path"`
+	Name      string `yaml:"name,omitempty"`      // Source name shown by --list; defaults to the directory name
+	Namespace string `yaml:"namespace,omitempty"` // Prefix for everything found in the directory
+	Position  string `yaml:"position,omitempty"`  // PositionPrepend or PositionAppend (default)
+}
+
+// bypassPermissions is the permission mode a project file can't choose
+const bypassPermissions = "bypassPermissions"
+
+// profile is the profile selected with SetProfile
+var profile string
+
+// SetProfile selects the profile Load applies. An empty name falls back to
+// $SKILLET_PROFILE.
+func SetProfile(name string) {
+	profile = name
+}
+
+// Path returns the path of the user config file
+func Path() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads the layered config for the current working directory
+func Load() (*Config, error) {
+	return LoadWithWorkDir("")
+}
+
+// LoadWithWorkDir reads the user config file, the project config file for
+// workDir, the selected profile, and the environment, later layers winning.
+// If workDir is empty, the current working directory is used.
+func LoadWithWorkDir(workDir string) (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if workDir == "" {
+		if workDir, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	if projectPath := FindProjectFile(workDir); projectPath != "" {
+		project, err := LoadFile(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(project.Backends) > 0 {
+			// Backends run arbitrary commands, so a cloned repo can't define them
+			return nil, fmt.Errorf("%s: backends can only be defined in %s", projectPath, path)
+		}
+		if err := project.Settings.checkProject(path); err != nil {
+			return nil, fmt.Errorf("%s: %w", projectPath, err)
+		}
+		for name, p := range project.Profiles {
+			if err := p.checkProject(path); err != nil {
+				return nil, fmt.Errorf("%s: profile %q: %w", projectPath, name, err)
+			}
+		}
+		cfg.Settings.merge(project.Settings)
+		for name, p := range project.Profiles {
+			if base, ok := cfg.Profiles[name]; ok {
+				base.merge(p)
+				p = base
+			}
+			if cfg.Profiles == nil {
+				cfg.Profiles = make(map[string]Settings)
+			}
+			cfg.Profiles[name] = p
+		}
+	}
+
+	name := profile
+	if name == "" {
+		name = os.Getenv(ProfileEnvVar)
+	}
+	if name != "" {
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q", name)
+		}
+		cfg.Settings.merge(p)
+	}
+
+	if err := cfg.Settings.applyEnv(workDir); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// FindProjectFile returns the nearest .skillet.yaml in workDir or a parent
+// up to the git root, or "" if there isn't one
+func FindProjectFile(workDir string) string {
+	dir, err := filepath.Abs(workDir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadFile reads a config file. A missing file is an empty config.
+// Relative replay, audit log, and search paths are resolved from the file's directory.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for name, backend := range cfg.Backends {
+		if backend.Replay != "" && len(backend.Command) > 0 {
+			return nil, fmt.Errorf("%s: backend %q cannot set both command and replay", path, name)
+		}
+		backend.Replay = resolvePath(backend.Replay, dir)
+		cfg.Backends[name] = backend
+	}
+
+	if err := cfg.Settings.resolve(dir); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for name, p := range cfg.Profiles {
+		if err := p.resolve(dir); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+		cfg.Profiles[name] = p
+	}
+
+	return cfg, nil
+}
+
+// checkProject rejects settings a project file can't choose, since a cloned
+// repo could use them to skip permission prompts or hide the audit trail.
+// userPath names the file where they can be set.
+func (s *Settings) checkProject(userPath string) error {
+	if s.PermissionMode == bypassPermissions {
+		return fmt.Errorf("permission-mode %s can only be set in %s", bypassPermissions, userPath)
+	}
+	if s.AuditLog != "" {
+		return fmt.Errorf("audit-log can only be set in %s", userPath)
+	}
+	return nil
+}
+
+// resolve validates s and resolves its paths relative to dir
+func (s *Settings) resolve(dir string) error {
+	if err := validateColor(s.Color); err != nil {
+		return err
+	}
+	s.AuditLog = resolvePath(s.AuditLog, dir)
+
+	for i, sd := range s.SearchPath {
+		if sd.Path == "" {
+			return fmt.Errorf("search-path entry %d needs a path", i+1)
+		}
+		if sd.Position != "" && sd.Position != PositionPrepend && sd.Position != PositionAppend {
+			return fmt.Errorf("invalid search-path position %q (must be %s or %s)", sd.Position, PositionPrepend, PositionAppend)
+		}
+		s.SearchPath[i].Path = resolvePath(sd.Path, dir)
+	}
+	return nil
+}
+
+// merge overrides s with the values set in other. Search paths accumulate.
+func (s *Settings) merge(other Settings) {
+	if other.Backend != "" {
+		s.Backend = other.Backend
+	}
+	if other.AuditLog != "" {
+		s.AuditLog = other.AuditLog
+	}
+	s.SearchPath = append(s.SearchPath, other.SearchPath...)
+	if other.Color != "" {
+		s.Color = other.Color
+	}
+	if other.Verbose != nil {
+		s.Verbose = other.Verbose
+	}
+	if other.Usage != nil {
+		s.Usage = other.Usage
+	}
+	if other.Model != "" {
+		s.Model = other.Model
+	}
+	if other.PermissionMode != "" {
+		s.PermissionMode = other.PermissionMode
+	}
+}
+
+// applyEnv overrides s with SKILLET_* environment variables. A relative
+// SKILLET_AUDIT_LOG is relative to workDir.
+func (s *Settings) applyEnv(workDir string) error {
+	stringVars := map[string]*string{
+		"SKILLET_BACKEND":         &s.Backend,
+		"SKILLET_COLOR":           &s.Color,
+		"SKILLET_MODEL":           &s.Model,
+		"SKILLET_PERMISSION_MODE": &s.PermissionMode,
+	}
+	for name, field := range stringVars {
+		if v := os.Getenv(name); v != "" {
+			*field = v
+		}
+	}
+	if err := validateColor(s.Color); err != nil {
+		return fmt.Errorf("SKILLET_COLOR: %w", err)
+	}
+	if v := os.Getenv("SKILLET_AUDIT_LOG"); v != "" {
+		s.AuditLog = resolvePath(v, workDir)
+	}
+
+	boolVars := map[string]**bool{
+		"SKILLET_VERBOSE": &s.Verbose,
+		"SKILLET_USAGE":   &s.Usage,
+	}
+	for name, field := range boolVars {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q (must be true or false)", name, v)
+		}
+		*field = &b
+	}
+	return nil
+}
+
+// validateColor checks a color mode from a config file or the environment
+func validateColor(mode string) error {
+	switch mode {
+	case "", "auto", "always", "never":
+		return nil
+	}
+	return fmt.Errorf("invalid color %q (must be auto, always, or never)", mode)
+}
+
+// resolvePath expands ~/ and makes a relative path relative to dir
+func resolvePath(p, dir string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return filepath.Join(dir, p)
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/martinemde/skillet/internal/executor"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeConfig(t, `backend: pinned
+backends:
+  pinned:
+    command: [/opt/claude/bin/claude, "{args}"]
+  demo:
+    replay: demos/review.jsonl
+audit-log: logs/audit.jsonl
+`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile error: %v", err)
+	}
+
+	if cfg.Backend != "pinned" {
+		t.Errorf("Backend = %q, want pinned", cfg.Backend)
+	}
+	if got := cfg.Backends["pinned"]; got.Type() != executor.BackendCommand || got.Command[0] != "/opt/claude/bin/claude" {
+		t.Errorf("Unexpected pinned backend: %+v", got)
+	}
+	wantReplay := filepath.Join(filepath.Dir(path), "demos", "review.jsonl")
+	if got := cfg.Backends["demo"].Replay; got != wantReplay {
+		t.Errorf("Replay = %q, want %q", got, wantReplay)
+	}
+	wantAuditLog := filepath.Join(filepath.Dir(path), "logs", "audit.jsonl")
+	if cfg.AuditLog != wantAuditLog {
+		t.Errorf("AuditLog = %q, want %q", cfg.AuditLog, wantAuditLog)
+	}
+}
+
+func TestLoadFile_SearchPath(t *testing.T) {
+	path := writeConfig(t, `search-path:
+  - path: tools/skills
+    name: monorepo
+    namespace: tools
+  - path: /opt/skills
+    position: prepend
+`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile error: %v", err)
+	}
+	if len(cfg.SearchPath) != 2 {
+		t.Fatalf("SearchPath = %+v, want 2 entries", cfg.SearchPath)
+	}
+	want := SearchDir{Path: filepath.Join(filepath.Dir(path), "tools", "skills"), Name: "monorepo", Namespace: "tools"}
+	if cfg.SearchPath[0] != want {
+		t.Errorf("SearchPath[0] = %+v, want %+v", cfg.SearchPath[0], want)
+	}
+	if got := cfg.SearchPath[1]; got.Path != "/opt/skills" || got.Position != PositionPrepend {
+		t.Errorf("SearchPath[1] = %+v, want /opt/skills prepended", got)
+	}
+}
+
+func TestLoadWithWorkDir(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv(ProfileEnvVar, "")
+	t.Setenv("SKILLET_VERBOSE", "")
+	t.Setenv("SKILLET_COLOR", "")
+	if err := os.MkdirAll(filepath.Join(configHome, "skillet"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	userConfig := `color: never
+verbose: true
+search-path:
+  - path: /opt/skills
+profiles:
+  ci:
+    usage: true
+    audit-log: /var/log/skillet.jsonl
+`
+	if err := os.WriteFile(filepath.Join(configHome, "skillet", FileName), []byte(userConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	project := t.TempDir()
+	workDir := filepath.Join(project, "services", "api")
+	if err := os.MkdirAll(filepath.Join(project, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	projectConfig := `model: opus
+search-path:
+  - path: tools
+profiles:
+  ci:
+    model: haiku
+`
+	if err := os.WriteFile(filepath.Join(project, ProjectFileName), []byte(projectConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadWithWorkDir(workDir)
+	if err != nil {
+		t.Fatalf("LoadWithWorkDir error: %v", err)
+	}
+	if cfg.Color != "never" || cfg.Verbose == nil || !*cfg.Verbose || cfg.Model != "opus" || cfg.Usage != nil {
+		t.Errorf("Unexpected settings without a profile: %+v", cfg.Settings)
+	}
+	wantSearch := []string{"/opt/skills", filepath.Join(project, "tools")}
+	if len(cfg.SearchPath) != 2 || cfg.SearchPath[0].Path != wantSearch[0] || cfg.SearchPath[1].Path != wantSearch[1] {
+		t.Errorf("SearchPath = %+v, want %v", cfg.SearchPath, wantSearch)
+	}
+
+	SetProfile("ci")
+	defer SetProfile("")
+	t.Setenv("SKILLET_VERBOSE", "false")
+	t.Setenv("SKILLET_COLOR", "always")
+	cfg, err = LoadWithWorkDir(workDir)
+	if err != nil {
+		t.Fatalf("LoadWithWorkDir error: %v", err)
+	}
+	if cfg.Model != "haiku" || cfg.Usage == nil || !*cfg.Usage {
+		t.Errorf("The ci profile should apply, got %+v", cfg.Settings)
+	}
+	if want := "/var/log/skillet.jsonl"; cfg.AuditLog != want {
+		t.Errorf("AuditLog = %q, want the user profile's %q", cfg.AuditLog, want)
+	}
+	if cfg.Verbose == nil || *cfg.Verbose || cfg.Color != "always" {
+		t.Errorf("Environment variables should override the files, got %+v", cfg.Settings)
+	}
+}
+
+func TestLoadWithWorkDir_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		profile string
+		env     string
+		wantErr string
+	}{
+		{"project backends", "backends:\n  x:\n    command: [sh]\n", "", "", "backends can only be defined in"},
+		{"project bypasses permissions", "permission-mode: bypassPermissions\n", "", "", "permission-mode bypassPermissions can only be set in"},
+		{"project profile bypasses permissions", "profiles:\n  ci:\n    permission-mode: bypassPermissions\n", "", "", `profile "ci": permission-mode bypassPermissions can only be set in`},
+		{"project audit log", "audit-log: /tmp/elsewhere.jsonl\n", "", "", "audit-log can only be set in"},
+		{"project profile audit log", "profiles:\n  ci:\n    audit-log: /tmp/elsewhere.jsonl\n", "", "", `profile "ci": audit-log can only be set in`},
+		{"unknown profile", "", "missing", "", `unknown profile "missing"`},
+		{"invalid env bool", "", "", "maybe", `invalid SKILLET_VERBOSE "maybe"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv(ProfileEnvVar, tt.profile)
+			t.Setenv("SKILLET_VERBOSE", tt.env)
+			workDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(workDir, ".git"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(workDir, ProjectFileName), []byte(tt.project), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadWithWorkDir(workDir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadWithWorkDir error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), FileName))
+	if err != nil || cfg.Backend != "" || len(cfg.Backends) != 0 {
+		t.Errorf("A missing config should be empty, got %+v, %v", cfg, err)
+	}
+}
+
+func TestLoadFile_Empty(t *testing.T) {
+	if _, err := LoadFile(writeConfig(t, "")); err != nil {
+		t.Errorf("An empty config should load, got %v", err)
+	}
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "backnd: claude\n", "field backnd not found"},
+		{"both command and replay", "backends:\n  x:\n    command: [a]\n    replay: b.jsonl\n", `backend "x" cannot set both`},
+		{"search path without path", "search-path:\n  - name: shared\n", "search-path entry 1 needs a path"},
+		{"bad color", "color: sometimes\n", `invalid color "sometimes"`},
+		{"bad profile", "profiles:\n  ci:\n    search-path:\n      - name: x\n", `profile "ci": search-path entry 1 needs a path`},
+		{"bad search path position", "search-path:\n  - path: shared\n    position: middle\n", `invalid search-path position "middle"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFile error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	path, err := Path()
+	if err != nil || path != filepath.Join("/xdg", "skillet", FileName) {
+		t.Errorf("Path() = %q, %v", path, err)
+	}
+}
@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Claude runs the Claude CLI
+type Claude struct {
+	config Config
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewClaude creates an executor for the Claude CLI
+func NewClaude(config Config, stdout, stderr io.Writer) *Claude {
+	return &Claude{
+		config: config,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// Execute runs the Claude CLI
+func (e *Claude) Execute(ctx context.Context) error {
+	return run(ctx, "claude", e.buildArgs(), e.config, e.stdout, e.stderr)
+}
+
+// buildArgs constructs the command-line arguments for the Claude CLI
+func (e *Claude) buildArgs() []string {
+	return e.config.claudeArgs()
+}
+
+// claudeArgs constructs the Claude CLI arguments for the config
+func (c Config) claudeArgs() []string {
+	args := []string{
+		"-p",
+		"--verbose",
+		"--output-format", c.outputFormat(),
+		"--permission-mode", c.permissionMode(),
+	}
+
+	// Add MCP server config for permission prompt handling
+	if c.SkilletPath != "" {
+		mcpConfig := fmt.Sprintf(
+			`{"mcpServers":{"skillet":{"command":"%s","args":["--mcp"]}}}`,
+			c.SkilletPath,
+		)
+		args = append(args, "--mcp-config", mcpConfig)
+		args = append(args, "--permission-prompt-tool", "mcp__skillet__prompt")
+	}
+
+	if c.Model != "" {
+		args = append(args, "--model", c.Model)
+	}
+
+	if c.AllowedTools != "" {
+		args = append(args, "--allowed-tools", c.AllowedTools)
+	}
+
+	if c.SystemPrompt != "" {
+		args = append(args, "--append-system-prompt", c.SystemPrompt)
+	}
+
+	if c.JSONSchema != "" {
+		args = append(args, "--json-schema", c.JSONSchema)
+	}
+
+	if c.MaxTurns > 0 {
+		args = append(args, "--max-turns", strconv.Itoa(c.MaxTurns))
+	}
+
+	// Resume takes precedence over continue since it names a specific session
+	if c.Resume != "" {
+		args = append(args, "--resume", c.Resume)
+	} else if c.Continue {
+		args = append(args, "--continue")
+	}
+
+	if c.Prompt != "" {
+		args = append(args, c.Prompt)
+	}
+
+	return args
+}
+
+func (c Config) outputFormat() string {
+	if c.OutputFormat != "" {
+		return c.OutputFormat
+	}
+	return "stream-json"
+}
+
+func (c Config) permissionMode() string {
+	if c.PermissionMode != "" {
+		return c.PermissionMode
+	}
+	return "acceptEdits"
+}
+
+// GetCommand returns the command string that would be executed (for dry-run)
+func (e *Claude) GetCommand() string {
+	return formatCommand("claude", e.buildArgs())
+}
+
+// Argv returns the claude command line
+func (e *Claude) Argv() []string {
+	return append([]string{"claude"}, e.buildArgs()...)
+}
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// argsPlaceholder expands to the Claude CLI arguments as separate arguments
+const argsPlaceholder = "{args}"
+
+// Command runs a configured command template in place of the Claude CLI.
+// The command must write Claude's stream-json format to stdout.
+//
+// In the template, an argument of exactly "{args}" expands to the arguments
+// skillet would pass to claude, and {prompt}, {system_prompt}, {model},
+// {allowed_tools}, {permission_mode}, {json_schema}, and {workdir} are
+// replaced within arguments. A leading "~/" in the program is expanded.
+type Command struct {
+	template []string
+	config   Config
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+// NewCommand creates an executor for a command template
+func NewCommand(template []string, config Config, stdout, stderr io.Writer) *Command {
+	return &Command{
+		template: template,
+		config:   config,
+		stdout:   stdout,
+		stderr:   stderr,
+	}
+}
+
+// Execute runs the command
+func (e *Command) Execute(ctx context.Context) error {
+	argv := e.buildArgv()
+	if len(argv) == 0 {
+		return fmt.Errorf("backend command is empty")
+	}
+	return run(ctx, argv[0], argv[1:], e.config, e.stdout, e.stderr)
+}
+
+// GetCommand returns the command string that would be executed (for dry-run)
+func (e *Command) GetCommand() string {
+	argv := e.buildArgv()
+	if len(argv) == 0 {
+		return ""
+	}
+	return formatCommand(argv[0], argv[1:])
+}
+
+// Argv returns the expanded command template
+func (e *Command) Argv() []string {
+	return e.buildArgv()
+}
+
+// buildArgv expands the template into the program and its arguments
+func (e *Command) buildArgv() []string {
+	replacer := strings.NewReplacer(
+		"{prompt}", e.config.Prompt,
+		"{system_prompt}", e.config.SystemPrompt,
+		"{model}", e.config.Model,
+		"{allowed_tools}", e.config.AllowedTools,
+		"{permission_mode}", e.config.permissionMode(),
+		"{json_schema}", e.config.JSONSchema,
+		"{workdir}", e.config.WorkDir,
+	)
+
+	var argv []string
+	for i, part := range e.template {
+		if part == argsPlaceholder {
+			argv = append(argv, e.config.claudeArgs()...)
+			continue
+		}
+		if rest, ok := strings.CutPrefix(part, "~/"); ok && i == 0 {
+			if home, err := os.UserHomeDir(); err == nil {
+				part = filepath.Join(home, rest)
+			}
+		}
+		argv = append(argv, replacer.Replace(part))
+	}
+	return argv
+}
@@ -1,126 +1,161 @@
+// Package executor runs the agent backend that produces a skill's stream-json output.
 package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 
 	"github.com/martinemde/skillet/internal/promptserver"
 )
 
+// Executor runs an agent, writing its stream-json output to stdout
+type Executor interface {
+	// Execute runs the agent until it finishes or ctx is cancelled
+	Execute(ctx context.Context) error
+	// GetCommand returns a description of what would be executed (for dry-run)
+	GetCommand() string
+	// Argv returns the program and arguments that will run; nil if no process runs
+	Argv() []string
+}
+
 // Config holds the final resolved configuration for executing Claude CLI.
 // All values should be resolved before creating the executor.
 type Config struct {
-	SystemPrompt     string // appended to system prompt; empty means none
-	Prompt           string // user prompt to send
-	Model            string // empty means use default
-	AllowedTools     string // empty means no restriction
-	PermissionMode   string // empty defaults to "acceptEdits"
-	OutputFormat     string // empty defaults to "stream-json"
-	SkilletPath      string // path to skillet binary for MCP permission prompts
-	PromptSocketPath string // Unix socket path for prompt server IPC
-	TaskListID       string // Claude Code task list ID
+	SystemPrompt     string   // appended to system prompt; empty means none
+	Prompt           string   // user prompt to send
+	Model            string   // empty means use default
+	AllowedTools     string   // empty means no restriction
+	PermissionMode   string   // empty defaults to "acceptEdits"
+	OutputFormat     string   // empty defaults to "stream-json"
+	SkilletPath      string   // path to skillet binary for MCP permission prompts
+	PromptSocketPath string   // Unix socket path for prompt server IPC
+	TaskListID       string   // Claude Code task list ID
+	Resume           string   // session ID to resume; empty means new session
+	Continue         bool     // continue the most recent session in the working directory
+	JSONSchema       string   // JSON Schema for structured output; empty means free-form
+	MaxTurns         int      // stop after this many turns; 0 means no limit
+	WorkDir          string   // directory to run Claude in; empty means the current directory
+	Env              []string // extra KEY=value environment variables for the agent process
 }
 
-// Executor executes the Claude CLI
-type Executor struct {
-	config Config
-	stdout io.Writer
-	stderr io.Writer
+// Backend names
+const (
+	BackendClaude  = "claude"
+	BackendReplay  = "replay"
+	BackendCommand = "command"
+)
+
+// Backend selects how the agent is run. A backend with neither a command
+// nor a replay file runs the Claude CLI.
+type Backend struct {
+	Name    string   `yaml:"-"`
+	Command []string `yaml:"command,omitempty"` // Command template run instead of claude
+	Replay  string   `yaml:"replay,omitempty"`  // Recorded stream-json file to replay
 }
 
-// New creates a new Executor
-func New(config Config, stdout, stderr io.Writer) *Executor {
-	return &Executor{
-		config: config,
-		stdout: stdout,
-		stderr: stderr,
+// Type returns BackendClaude, BackendReplay, or BackendCommand
+func (b Backend) Type() string {
+	switch {
+	case b.Replay != "":
+		return BackendReplay
+	case len(b.Command) > 0:
+		return BackendCommand
+	default:
+		return BackendClaude
 	}
 }
 
-// Execute runs the Claude CLI
-func (e *Executor) Execute(ctx context.Context) error {
-	cmd := exec.CommandContext(ctx, "claude", e.buildArgs()...)
-	cmd.Stdout = e.stdout
-	cmd.Stderr = e.stderr
-
-	// Build environment variables to pass to child process
-	var envVars []string
-
-	if e.config.PromptSocketPath != "" {
-		envVars = append(envVars, promptserver.SocketEnvVar+"="+e.config.PromptSocketPath)
+// New creates an executor for the backend
+func New(backend Backend, config Config, stdout, stderr io.Writer) (Executor, error) {
+	switch backend.Type() {
+	case BackendReplay:
+		if len(backend.Command) > 0 {
+			return nil, fmt.Errorf("backend %q cannot set both command and replay", backend.Name)
+		}
+		return NewReplay(backend.Replay, stdout), nil
+	case BackendCommand:
+		return NewCommand(backend.Command, config, stdout, stderr), nil
+	default:
+		return NewClaude(config, stdout, stderr), nil
 	}
+}
 
-	if e.config.TaskListID != "" {
-		envVars = append(envVars, "CLAUDE_CODE_TASK_LIST_ID="+e.config.TaskListID)
+// SelectBackend resolves a backend name: "claude", "replay:<file>", or the
+// name of a configured backend. Configured backends may override "claude".
+// An empty name selects claude.
+func SelectBackend(name string, configured map[string]Backend) (Backend, error) {
+	if name == "" {
+		name = BackendClaude
 	}
 
-	// Only set cmd.Env if we have custom variables
-	if len(envVars) > 0 {
-		cmd.Env = append(os.Environ(), envVars...)
+	if backend, ok := configured[name]; ok {
+		backend.Name = name
+		return backend, nil
 	}
 
-	return cmd.Run()
-}
-
-// buildArgs constructs the command-line arguments for the Claude CLI
-func (e *Executor) buildArgs() []string {
-	args := []string{
-		"-p",
-		"--verbose",
-		"--output-format", e.outputFormat(),
-		"--permission-mode", e.permissionMode(),
+	if name == BackendClaude {
+		return Backend{Name: name}, nil
 	}
 
-	// Add MCP server config for permission prompt handling
-	if e.config.SkilletPath != "" {
-		mcpConfig := fmt.Sprintf(
-			`{"mcpServers":{"skillet":{"command":"%s","args":["--mcp"]}}}`,
-			e.config.SkilletPath,
-		)
-		args = append(args, "--mcp-config", mcpConfig)
-		args = append(args, "--permission-prompt-tool", "mcp__skillet__prompt")
+	if path, ok := strings.CutPrefix(name, BackendReplay+":"); ok {
+		if path == "" {
+			return Backend{}, fmt.Errorf("replay backend requires a file (replay:<file>)")
+		}
+		return Backend{Name: BackendReplay, Replay: path}, nil
 	}
 
-	if e.config.Model != "" {
-		args = append(args, "--model", e.config.Model)
+	names := []string{BackendClaude, BackendReplay + ":<file>"}
+	for configuredName := range configured {
+		if configuredName != BackendClaude {
+			names = append(names, configuredName)
+		}
 	}
+	sort.Strings(names[2:])
+	return Backend{}, fmt.Errorf("unknown backend %q (available: %s)", name, strings.Join(names, ", "))
+}
 
-	if e.config.AllowedTools != "" {
-		args = append(args, "--allowed-tools", e.config.AllowedTools)
-	}
+// run executes a process with skillet's environment for agent child processes
+func run(ctx context.Context, name string, args []string, config Config, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Dir = config.WorkDir
+
+	// Build environment variables to pass to child process
+	var envVars []string
 
-	if e.config.SystemPrompt != "" {
-		args = append(args, "--append-system-prompt", e.config.SystemPrompt)
+	if config.PromptSocketPath != "" {
+		envVars = append(envVars, promptserver.SocketEnvVar+"="+config.PromptSocketPath)
 	}
 
-	if e.config.Prompt != "" {
-		args = append(args, e.config.Prompt)
+	if config.TaskListID != "" {
+		envVars = append(envVars, "CLAUDE_CODE_TASK_LIST_ID="+config.TaskListID)
 	}
 
-	return args
-}
+	envVars = append(envVars, config.Env...)
 
-func (e *Executor) outputFormat() string {
-	if e.config.OutputFormat != "" {
-		return e.config.OutputFormat
+	// Only set cmd.Env if we have custom variables
+	if len(envVars) > 0 {
+		cmd.Env = append(os.Environ(), envVars...)
 	}
-	return "stream-json"
-}
 
-func (e *Executor) permissionMode() string {
-	if e.config.PermissionMode != "" {
-		return e.config.PermissionMode
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("%s is not installed or not on PATH: %w", name, err)
+		}
+		return err
 	}
-	return "acceptEdits"
+	return nil
 }
 
-// GetCommand returns the command string that would be executed (for dry-run)
-func (e *Executor) GetCommand() string {
-	args := e.buildArgs()
+// formatCommand renders a command line, quoting arguments with spaces or newlines
+func formatCommand(name string, args []string) string {
 	quoted := make([]string, len(args))
 	for i, arg := range args {
 		if strings.Contains(arg, " ") || strings.Contains(arg, "\n") {
@@ -129,5 +164,5 @@ func (e *Executor) GetCommand() string {
 			quoted[i] = arg
 		}
 	}
-	return "claude " + strings.Join(quoted, " ")
+	return strings.TrimSpace(name + " " + strings.Join(quoted, " "))
 }
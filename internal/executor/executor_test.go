@@ -1,18 +1,20 @@
 package executor
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"strings"
 	"testing"
 )
 
-func TestNew(t *testing.T) {
+func TestNewClaude(t *testing.T) {
 	config := Config{
 		Prompt:       "Test prompt",
 		SystemPrompt: "System prompt content",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 
 	if exec.config.Prompt != config.Prompt {
 		t.Error("Executor should store the config prompt")
@@ -28,7 +30,7 @@ func TestBuildArgs_Basic(t *testing.T) {
 		SystemPrompt: "System prompt content",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	args := exec.buildArgs()
 
 	// Check required args
@@ -65,7 +67,7 @@ func TestBuildArgs_WithModel(t *testing.T) {
 		Model:  "claude-opus-4-5-20251101",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	args := exec.buildArgs()
 
 	hasModel := false
@@ -86,7 +88,7 @@ func TestBuildArgs_NoModel(t *testing.T) {
 		Model:  "", // empty means no model flag
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	args := exec.buildArgs()
 
 	for _, arg := range args {
@@ -102,7 +104,7 @@ func TestBuildArgs_WithAllowedTools(t *testing.T) {
 		AllowedTools: "Read Write Bash(git:*)",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	args := exec.buildArgs()
 
 	hasTools := false
@@ -122,7 +124,7 @@ func TestBuildArgs_PromptOnly(t *testing.T) {
 		Prompt: "Just a prompt",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	args := exec.buildArgs()
 
 	// Should NOT have --append-system-prompt when empty
@@ -144,7 +146,7 @@ func TestBuildArgs_CustomOutputFormat(t *testing.T) {
 		OutputFormat: "text",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	args := exec.buildArgs()
 
 	hasFormat := false
@@ -165,7 +167,7 @@ func TestBuildArgs_CustomPermissionMode(t *testing.T) {
 		PermissionMode: "plan",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	args := exec.buildArgs()
 
 	hasMode := false
@@ -188,7 +190,7 @@ func TestGetCommand(t *testing.T) {
 		AllowedTools: "Read Write",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	cmd := exec.GetCommand()
 
 	if !strings.HasPrefix(cmd, "claude ") {
@@ -208,7 +210,7 @@ func TestGetCommand_QuotesArgs(t *testing.T) {
 		Prompt: "prompt with spaces",
 	}
 
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	cmd := exec.GetCommand()
 
 	if !strings.Contains(cmd, `"prompt with spaces"`) {
@@ -221,8 +223,236 @@ func TestBuildArgs_WithTaskListID(t *testing.T) {
 		Prompt:     "Test",
 		TaskListID: "my-task-list-123",
 	}
-	exec := New(config, io.Discard, io.Discard)
+	exec := NewClaude(config, io.Discard, io.Discard)
 	if exec.config.TaskListID != "my-task-list-123" {
 		t.Error("Executor should store the TaskListID")
 	}
 }
+
+func TestBuildArgs_WithResume(t *testing.T) {
+	config := Config{
+		Prompt: "Test",
+		Resume: "abc-123",
+	}
+
+	exec := NewClaude(config, io.Discard, io.Discard)
+	args := exec.buildArgs()
+
+	hasResume := false
+	for i, arg := range args {
+		if arg == "--resume" && i+1 < len(args) && args[i+1] == "abc-123" {
+			hasResume = true
+		}
+		if arg == "--continue" {
+			t.Error("Args should not contain '--continue' when resuming")
+		}
+	}
+	if !hasResume {
+		t.Error("Args should contain '--resume abc-123'")
+	}
+
+	// Prompt must remain the final argument
+	if args[len(args)-1] != "Test" {
+		t.Errorf("Last arg should be the prompt, got '%s'", args[len(args)-1])
+	}
+}
+
+func TestBuildArgs_WithContinue(t *testing.T) {
+	config := Config{
+		Prompt:   "Test",
+		Continue: true,
+	}
+
+	exec := NewClaude(config, io.Discard, io.Discard)
+	args := exec.buildArgs()
+
+	hasContinue := false
+	for _, arg := range args {
+		if arg == "--continue" {
+			hasContinue = true
+		}
+		if arg == "--resume" {
+			t.Error("Args should not contain '--resume' when only continuing")
+		}
+	}
+	if !hasContinue {
+		t.Error("Args should contain '--continue'")
+	}
+}
+
+func TestBuildArgs_ResumeTakesPrecedenceOverContinue(t *testing.T) {
+	config := Config{
+		Prompt:   "Test",
+		Resume:   "abc-123",
+		Continue: true,
+	}
+
+	exec := NewClaude(config, io.Discard, io.Discard)
+	args := exec.buildArgs()
+
+	for _, arg := range args {
+		if arg == "--continue" {
+			t.Error("Args should not contain '--continue' when a resume session is given")
+		}
+	}
+}
+
+func TestBuildArgs_WithJSONSchema(t *testing.T) {
+	config := Config{
+		Prompt:     "Test",
+		JSONSchema: `{"type":"object"}`,
+	}
+
+	exec := NewClaude(config, io.Discard, io.Discard)
+	args := exec.buildArgs()
+
+	hasSchema := false
+	for i, arg := range args {
+		if arg == "--json-schema" && i+1 < len(args) && args[i+1] == `{"type":"object"}` {
+			hasSchema = true
+			break
+		}
+	}
+	if !hasSchema {
+		t.Error(`Args should contain '--json-schema {"type":"object"}'`)
+	}
+}
+
+func TestBuildArgs_WithMaxTurns(t *testing.T) {
+	exec := NewClaude(Config{Prompt: "Test", MaxTurns: 10}, io.Discard, io.Discard)
+	args := exec.buildArgs()
+
+	hasMaxTurns := false
+	for i, arg := range args {
+		if arg == "--max-turns" && i+1 < len(args) && args[i+1] == "10" {
+			hasMaxTurns = true
+			break
+		}
+	}
+	if !hasMaxTurns {
+		t.Error("Args should contain '--max-turns 10'")
+	}
+
+	for _, arg := range NewClaude(Config{Prompt: "Test"}, io.Discard, io.Discard).buildArgs() {
+		if arg == "--max-turns" {
+			t.Error("Args should not contain --max-turns without a limit")
+		}
+	}
+}
+
+func TestSelectBackend(t *testing.T) {
+	configured := map[string]Backend{
+		"pinned": {Command: []string{"/opt/claude/bin/claude", "{args}"}},
+		"demo":   {Replay: "demo.jsonl"},
+	}
+
+	tests := []struct {
+		name     string
+		wantType string
+		wantErr  string
+	}{
+		{"", BackendClaude, ""},
+		{"claude", BackendClaude, ""},
+		{"pinned", BackendCommand, ""},
+		{"demo", BackendReplay, ""},
+		{"replay:session.jsonl", BackendReplay, ""},
+		{"replay:", "", "requires a file"},
+		{"other", "", `unknown backend "other" (available: claude, replay:<file>, demo, pinned)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, err := SelectBackend(tt.name, configured)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("SelectBackend(%q) error = %v, want %q", tt.name, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectBackend(%q) error = %v", tt.name, err)
+			}
+			if backend.Type() != tt.wantType {
+				t.Errorf("SelectBackend(%q).Type() = %q, want %q", tt.name, backend.Type(), tt.wantType)
+			}
+		})
+	}
+}
+
+func TestSelectBackend_OverrideClaude(t *testing.T) {
+	backend, err := SelectBackend("", map[string]Backend{
+		"claude": {Command: []string{"claude-1.0", "{args}"}},
+	})
+	if err != nil || backend.Type() != BackendCommand {
+		t.Errorf("A configured claude backend should replace the default, got %+v, %v", backend, err)
+	}
+}
+
+func TestNew_Backends(t *testing.T) {
+	config := Config{Prompt: "Hi"}
+
+	tests := []struct {
+		backend Backend
+		want    string
+	}{
+		{Backend{}, "claude -p"},
+		{Backend{Replay: "session.jsonl"}, "replay session.jsonl"},
+		{Backend{Command: []string{"wrapper", "--", "{args}"}}, "wrapper -- -p --verbose"},
+	}
+
+	for _, tt := range tests {
+		exec, err := New(tt.backend, config, io.Discard, io.Discard)
+		if err != nil {
+			t.Fatalf("New(%+v) error = %v", tt.backend, err)
+		}
+		if got := exec.GetCommand(); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("GetCommand() = %q, want prefix %q", got, tt.want)
+		}
+	}
+
+	if _, err := New(Backend{Name: "both", Command: []string{"x"}, Replay: "y"}, config, io.Discard, io.Discard); err == nil {
+		t.Error("A backend with both command and replay should fail")
+	}
+}
+
+func TestCommand_BuildArgv(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	exec := NewCommand(
+		[]string{"~/bin/agent", "--model={model}", "--cwd", "{workdir}", "{prompt}"},
+		Config{Prompt: "Review $HOME", Model: "opus", WorkDir: "/work"},
+		io.Discard, io.Discard,
+	)
+
+	want := []string{"/home/me/bin/agent", "--model=opus", "--cwd", "/work", "Review $HOME"}
+	if got := exec.buildArgv(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("buildArgv() = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_Execute(t *testing.T) {
+	var stdout bytes.Buffer
+	exec := NewCommand([]string{"sh", "-c", `printf '%s' "$1"`, "sh", "{prompt}"}, Config{Prompt: "hello"}, &stdout, io.Discard)
+
+	if err := exec.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if stdout.String() != "hello" {
+		t.Errorf("stdout = %q, want %q", stdout.String(), "hello")
+	}
+}
+
+func TestReplay_Execute(t *testing.T) {
+	var stdout bytes.Buffer
+	exec := NewReplay("../../testdata/parse/tool-operations.jsonl", &stdout)
+
+	if err := exec.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), `"result":"Completed successfully"`) {
+		t.Errorf("Replay should write the recording, got: %s", stdout.String())
+	}
+
+	if err := NewReplay("missing.jsonl", &stdout).Execute(context.Background()); err == nil {
+		t.Error("Replaying a missing file should fail")
+	}
+}
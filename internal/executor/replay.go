@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Replay writes a recorded stream-json session instead of running an agent
+type Replay struct {
+	path   string
+	stdout io.Writer
+}
+
+// NewReplay creates an executor that replays the recording at path
+func NewReplay(path string, stdout io.Writer) *Replay {
+	return &Replay{path: path, stdout: stdout}
+}
+
+// Execute copies the recording to stdout
+func (e *Replay) Execute(ctx context.Context) error {
+	f, err := os.Open(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	_, err = io.Copy(e.stdout, &contextReader{ctx: ctx, r: f})
+	return err
+}
+
+// GetCommand returns a description of the replay (for dry-run)
+func (e *Replay) GetCommand() string {
+	return "replay " + e.path
+}
+
+// Argv returns nil; a replay runs no process
+func (e *Replay) Argv() []string {
+	return nil
+}
+
+// contextReader stops reading once its context is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,100 @@
+// Package exitcode defines skillet's exit statuses, so scripts can tell why
+// a run failed, and classifies errors into them.
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/budget"
+	"github.com/martinemde/skillet/internal/formatter"
+	"github.com/martinemde/skillet/internal/resolver"
+)
+
+// Exit statuses
+const (
+	OK              = 0
+	Failure         = 1   // Any failure not listed below
+	Usage           = 2   // Invalid flags or skill arguments
+	NotFound        = 3   // The skill or command wasn't found
+	InvalidResource = 4   // The skill or command file is invalid, e.g. bad frontmatter
+	ResultError     = 5   // The run finished with an error result
+	MaxTurns        = 6   // The run stopped at its turn limit
+	MaxBudget       = 7   // The run stopped at its budget
+	SchemaMismatch  = 8   // The result didn't match the output schema
+	AgentNotFound   = 127 // The agent CLI (claude) isn't installed
+	Interrupted     = 130 // Stopped by Ctrl-C or SIGTERM
+)
+
+// Error gives an error an exit status
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns err with the exit status code, or nil if err is nil
+func Wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// ErrInterrupted is returned when a run is stopped by a signal
+var ErrInterrupted = Wrap(Interrupted, errors.New("interrupted"))
+
+// FromError returns the exit status for err
+func FromError(err error) int {
+	if err == nil {
+		return OK
+	}
+
+	var codeErr *Error
+	var argErr *arguments.Error
+	var resultErr *formatter.ResultError
+	var schemaErr *formatter.SchemaMismatchError
+	var budgetErr *budget.ExceededError
+	switch {
+	case errors.As(err, &codeErr):
+		return codeErr.Code
+	case errors.As(err, &budgetErr):
+		if budgetErr.Limit == budget.LimitTurns {
+			return MaxTurns
+		}
+		return MaxBudget
+	case errors.As(err, &resultErr):
+		return FromSubtype(resultErr.Subtype)
+	case errors.As(err, &schemaErr):
+		return SchemaMismatch
+	case errors.As(err, &argErr):
+		return Usage
+	case errors.Is(err, resolver.ErrNotFound):
+		return NotFound
+	case errors.Is(err, exec.ErrNotFound):
+		return AgentNotFound
+	case errors.Is(err, context.Canceled):
+		return Interrupted
+	}
+	return Failure
+}
+
+// FromSubtype returns the exit status for a failed result's subtype
+func FromSubtype(subtype string) int {
+	switch subtype {
+	case formatter.SubtypeMaxTurns:
+		return MaxTurns
+	case formatter.SubtypeMaxBudget:
+		return MaxBudget
+	}
+	return ResultError
+}
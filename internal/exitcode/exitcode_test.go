@@ -0,0 +1,42 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/budget"
+	"github.com/martinemde/skillet/internal/formatter"
+	"github.com/martinemde/skillet/internal/resolver"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, OK},
+		{"plain", errors.New("boom"), Failure},
+		{"wrapped code", fmt.Errorf("outer: %w", Wrap(InvalidResource, errors.New("bad frontmatter"))), InvalidResource},
+		{"arguments", &arguments.Error{Err: errors.New("missing file")}, Usage},
+		{"not found", fmt.Errorf("failed to resolve: %w", resolver.ErrNotFound), NotFound},
+		{"error result", &formatter.ResultError{Subtype: formatter.SubtypeDuringExecution}, ResultError},
+		{"max turns", &formatter.ResultError{Subtype: formatter.SubtypeMaxTurns}, MaxTurns},
+		{"max budget", fmt.Errorf("run: %w", &formatter.ResultError{Subtype: formatter.SubtypeMaxBudget}), MaxBudget},
+		{"turn limit", &budget.ExceededError{Limit: budget.LimitTurns, Value: 11, Max: 10}, MaxTurns},
+		{"cost limit", fmt.Errorf("execution failed: %w", &budget.ExceededError{Limit: budget.LimitCost, Value: 0.6, Max: 0.5}), MaxBudget},
+		{"schema", fmt.Errorf("formatting failed: %w", &formatter.SchemaMismatchError{Err: errors.New("missing field")}), SchemaMismatch},
+		{"agent missing", &exec.Error{Name: "claude", Err: exec.ErrNotFound}, AgentNotFound},
+		{"cancelled", fmt.Errorf("execution failed: %w", context.Canceled), Interrupted},
+		{"interrupted", ErrInterrupted, Interrupted},
+	}
+	for _, tt := range tests {
+		if got := FromError(tt.err); got != tt.want {
+			t.Errorf("%s: FromError(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package formatter
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // EventType identifies different stream events
 type EventType int
@@ -14,6 +17,25 @@ const (
 	EventUsage
 )
 
+// String returns the stable name used for the event type in JSON output
+func (t EventType) String() string {
+	switch t {
+	case EventSystemInit:
+		return "init"
+	case EventThinking:
+		return "thinking"
+	case EventText:
+		return "text"
+	case EventToolComplete:
+		return "tool"
+	case EventFinalResult:
+		return "result"
+	case EventUsage:
+		return "usage"
+	}
+	return "unknown"
+}
+
 // StreamEvent represents a parsed event from the Claude stream
 type StreamEvent struct {
 	Type EventType
@@ -24,6 +46,7 @@ type StreamEvent struct {
 type SystemInitData struct {
 	SkillName string
 	SkillPath string
+	SessionID string
 }
 
 // ThinkingData represents a thinking block event
@@ -43,9 +66,13 @@ type ToolCompleteData struct {
 
 // FinalResultData represents the final result event
 type FinalResultData struct {
-	Result  string
-	IsError bool
-	Elapsed time.Duration
+	Result    string
+	Subtype   string // e.g. SubtypeSuccess or SubtypeMaxTurns
+	IsError   bool   // Set for error results and error subtypes
+	Elapsed   time.Duration
+	SessionID string // Claude session ID, used to resume the run
+
+	StructuredOutput json.RawMessage // JSON result when an output schema was requested
 }
 
 // UsageData represents token usage information
@@ -2,10 +2,13 @@ package formatter
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+
+	"github.com/martinemde/skillet/internal/jsonschema"
 )
 
 // FormatterConfig holds configuration options for formatters
@@ -31,6 +34,12 @@ type Config struct {
 	SkillName       string // Name of the skill being executed
 	SkillPath       string // Path to the skill/command file being executed
 	Color           string // Color mode: "auto", "always", or "never"
+	Format          string // Output format: "" for terminal, FormatJSON, or FormatNDJSON
+
+	// Schema validates the final result; with terminal output only the JSON
+	// result is written to Output and progress is written to Progress instead.
+	Schema   *jsonschema.Schema
+	Progress io.Writer
 }
 
 // Formatter struct for backward compatibility
@@ -44,6 +53,9 @@ type legacyFormatter struct {
 	skillName       string
 	skillPath       string
 	color           string
+	format          string
+	schema          *jsonschema.Schema
+	progress        io.Writer
 }
 
 // New creates a formatter with the legacy API
@@ -58,6 +70,9 @@ func New(cfg Config) *legacyFormatter {
 		skillName:       cfg.SkillName,
 		skillPath:       cfg.SkillPath,
 		color:           cfg.Color,
+		format:          cfg.Format,
+		schema:          cfg.Schema,
+		progress:        cfg.Progress,
 	}
 }
 
@@ -107,24 +122,58 @@ func (f *legacyFormatter) Format(input io.Reader) error {
 	parser := NewStreamParser(f.skillName, f.skillPath, f.verbose)
 	events, parserErr := parser.Parse(pr)
 
-	// Create appropriate formatter based on verbose flag
+	// With an output schema, the terminal view moves to the progress writer
+	// so that only the validated JSON result is written to output
+	output := f.output
+	structuredOutput := f.schema != nil && !IsJSONFormat(f.format)
+	if structuredOutput {
+		output = f.progress
+		if output == nil {
+			output = io.Discard
+		}
+	}
+
+	// Create appropriate formatter based on format and verbose flag
 	var formatter Formatter
-	if f.verbose {
+	if IsJSONFormat(f.format) {
+		formatter = NewJSONFormatter(output, f.format)
+	} else if f.verbose {
 		formatter = NewVerboseTerminalFormatter(FormatterConfig{
-			Output:    f.output,
+			Output:    output,
 			ShowUsage: f.showUsage,
 			Color:     f.color,
 		})
 	} else {
 		formatter = NewTerminalFormatter(FormatterConfig{
-			Output:    f.output,
+			Output:    output,
 			ShowUsage: f.showUsage,
 			Color:     f.color,
 		})
 	}
 
+	if f.schema != nil {
+		var resultOutput io.Writer
+		if structuredOutput {
+			resultOutput = f.output
+		}
+		formatter = NewStructuredFormatter(formatter, resultOutput, f.schema)
+	}
+
+	// Note the final result on the way through, to report a failed run
+	var final *FinalResultData
+	forward := make(chan StreamEvent)
+	go func() {
+		defer close(forward)
+		for event := range events {
+			if data, ok := event.Data.(FinalResultData); ok {
+				final = &data
+			}
+			forward <- event
+		}
+	}()
+
 	// Format events (blocks until all events are processed)
-	formatErr := formatter.Format(events)
+	formatErr := formatter.Format(forward)
 
 	// Wait for parser error channel to close
 	parseErr := <-parserErr
@@ -139,9 +188,16 @@ func (f *legacyFormatter) Format(input io.Reader) error {
 	if parseErr != nil {
 		return fmt.Errorf("parsing failed: %w", parseErr)
 	}
+	var resultErr *ResultError
+	if errors.As(formatErr, &resultErr) {
+		return resultErr
+	}
 	if formatErr != nil {
 		return fmt.Errorf("formatting failed: %w", formatErr)
 	}
+	if final != nil && final.IsError {
+		return &ResultError{Subtype: final.Subtype, Result: final.Result}
+	}
 
 	return nil
 }
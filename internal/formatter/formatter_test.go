@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -121,6 +122,33 @@ func TestFormat_WithUsage(t *testing.T) {
 	}
 }
 
+func TestFormat_UsageCost(t *testing.T) {
+	input := `{"type":"result","result":"Task complete","total_cost_usd":0.0421,"num_turns":4,"duration_api_ms":2300,"usage":{"input_tokens":100,"output_tokens":50}}`
+
+	var output bytes.Buffer
+	f := New(Config{Output: &output, ShowUsage: true})
+	if err := f.Format(strings.NewReader(input)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	result := output.String()
+	for _, expected := range []string{"Turns", "4", "API time", "2.3s", "Cost", "$0.0421"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Output should contain '%s', got: %s", expected, result)
+		}
+	}
+
+	// Cost is reported even when the result has no token usage
+	output.Reset()
+	f = New(Config{Output: &output, ShowUsage: true})
+	if err := f.Format(strings.NewReader(`{"type":"result","result":"Done","total_cost_usd":0.5}`)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if !strings.Contains(output.String(), "$0.5000") {
+		t.Errorf("Output should contain the cost, got: %s", output.String())
+	}
+}
+
 func TestFormat_WithoutUsage(t *testing.T) {
 	input := `{"type":"result","result":"Task complete","is_error":false,"usage":{"input_tokens":100,"output_tokens":50}}`
 
@@ -154,8 +182,10 @@ func TestFormat_ErrorResult(t *testing.T) {
 	f := New(Config{Output: &output})
 	err := f.Format(strings.NewReader(input))
 
-	if err != nil {
-		t.Fatalf("Format failed: %v", err)
+	// The failed result is reported so the exit status can reflect it
+	var resultErr *ResultError
+	if !errors.As(err, &resultErr) || resultErr.Subtype != "permission_denied" {
+		t.Fatalf("Format error = %v, want a ResultError with the subtype", err)
 	}
 
 	result := output.String()
@@ -166,6 +196,54 @@ func TestFormat_ErrorResult(t *testing.T) {
 	}
 }
 
+func TestFormat_ResultSubtypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantErr    string
+		wantOutput string
+	}{
+		{
+			name:       "max turns without is_error",
+			input:      `{"type":"result","subtype":"error_max_turns","result":""}`,
+			wantErr:    "run failed: reached the maximum number of turns",
+			wantOutput: "Failed: reached the maximum number of turns",
+		},
+		{
+			name:       "budget",
+			input:      `{"type":"result","subtype":"error_max_budget_usd","is_error":true}`,
+			wantErr:    "run failed: reached the maximum budget",
+			wantOutput: "Failed: reached the maximum budget",
+		},
+		{
+			name:       "error text",
+			input:      `{"type":"result","subtype":"success","is_error":true,"result":"Invalid API key\nPlease run /login"}`,
+			wantErr:    "run failed: Invalid API key",
+			wantOutput: "Failed",
+		},
+		{
+			name:       "success",
+			input:      `{"type":"result","subtype":"success","result":"Done"}`,
+			wantOutput: "Completed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := New(Config{Output: &output, Color: "never"}).Format(strings.NewReader(tt.input))
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Format error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("Format error = %v, want %q", err, tt.wantErr)
+			}
+			if !strings.Contains(output.String(), tt.wantOutput) {
+				t.Errorf("Output should contain %q, got: %s", tt.wantOutput, output.String())
+			}
+		})
+	}
+}
+
 func TestFormat_VerboseMode(t *testing.T) {
 	// In verbose mode, text is streamed as it comes, not printed at the end
 	input := `{"type":"system","subtype":"init"}
@@ -887,3 +965,60 @@ func TestParser_ExtractTarget_TaskList(t *testing.T) {
 		t.Errorf("Expected empty string, got '%s'", target)
 	}
 }
+
+func TestFormat_SessionIDResumeHint(t *testing.T) {
+	input := `{"type":"system","subtype":"init","session_id":"sess-42"}
+{"type":"result","subtype":"success","result":"Done","is_error":false}`
+
+	for _, verbose := range []bool{false, true} {
+		var output bytes.Buffer
+		f := New(Config{Output: &output, Verbose: verbose, Color: "never"})
+		if err := f.Format(strings.NewReader(input)); err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+
+		result := output.String()
+		if !strings.Contains(result, "skillet --resume sess-42") {
+			t.Errorf("Output (verbose=%v) should contain resume hint, got: %s", verbose, result)
+		}
+	}
+}
+
+func TestFormat_NoSessionIDNoResumeHint(t *testing.T) {
+	input := `{"type":"result","subtype":"success","result":"Done","is_error":false}`
+
+	var output bytes.Buffer
+	f := New(Config{Output: &output, Color: "never"})
+	if err := f.Format(strings.NewReader(input)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	if strings.Contains(output.String(), "--resume") {
+		t.Errorf("Output should not contain resume hint without a session ID, got: %s", output.String())
+	}
+}
+
+func TestParser_SessionIDFromResult(t *testing.T) {
+	input := `{"type":"system","subtype":"init","session_id":"from-init"}
+{"type":"result","subtype":"success","result":"Done","session_id":"from-result"}`
+
+	parser := NewStreamParser("", "", false)
+	events, _ := parser.Parse(strings.NewReader(input))
+
+	var initID, resultID string
+	for event := range events {
+		switch data := event.Data.(type) {
+		case SystemInitData:
+			initID = data.SessionID
+		case FinalResultData:
+			resultID = data.SessionID
+		}
+	}
+
+	if initID != "from-init" {
+		t.Errorf("SystemInitData.SessionID = %q, want %q", initID, "from-init")
+	}
+	if resultID != "from-result" {
+		t.Errorf("FinalResultData.SessionID = %q, want %q", resultID, "from-result")
+	}
+}
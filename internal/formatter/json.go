@@ -0,0 +1,121 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Machine-readable output formats
+const (
+	FormatJSON   = "json"   // one JSON document summarizing the run
+	FormatNDJSON = "ndjson" // one JSON event per line as the run progresses
+)
+
+// JSONFormatter emits skillet's normalized events as stable JSON.
+// Unlike Claude's raw stream-json, tool calls are already paired with their
+// results and carry the same target, status and error shown in the terminal.
+type JSONFormatter struct {
+	output io.Writer
+	format string
+}
+
+// jsonEvent is the wire format for a single event in ndjson mode
+type jsonEvent struct {
+	Type      string         `json:"type"`
+	Skill     string         `json:"skill,omitempty"`
+	SkillPath string         `json:"skill_path,omitempty"`
+	SessionID string         `json:"session_id,omitempty"`
+	Text      string         `json:"text,omitempty"`
+	Tool      *ToolOperation `json:"tool,omitempty"`
+	Result    *string        `json:"result,omitempty"`
+	Subtype   string         `json:"subtype,omitempty"`
+	IsError   *bool          `json:"is_error,omitempty"`
+	ElapsedMS *int64         `json:"elapsed_ms,omitempty"`
+	Usage     *Usage         `json:"usage,omitempty"`
+
+	StructuredOutput json.RawMessage `json:"structured_output,omitempty"`
+}
+
+// NewJSONFormatter creates a formatter for FormatJSON or FormatNDJSON
+func NewJSONFormatter(output io.Writer, format string) *JSONFormatter {
+	return &JSONFormatter{
+		output: output,
+		format: format,
+	}
+}
+
+// IsJSONFormat reports whether format selects the JSON formatter
+func IsJSONFormat(format string) bool {
+	return format == FormatJSON || format == FormatNDJSON
+}
+
+// Format writes events as JSON
+func (f *JSONFormatter) Format(events <-chan StreamEvent) error {
+	if f.format == FormatNDJSON {
+		return f.formatLines(events)
+	}
+	return f.formatDocument(events)
+}
+
+// formatLines writes each event on its own line as it arrives
+func (f *JSONFormatter) formatLines(events <-chan StreamEvent) error {
+	encoder := json.NewEncoder(f.output)
+	var encodeErr error
+	for event := range events {
+		if encodeErr != nil {
+			// Keep draining so the parser doesn't block
+			continue
+		}
+		if err := encoder.Encode(toJSONEvent(event)); err != nil {
+			encodeErr = fmt.Errorf("failed to encode event: %w", err)
+		}
+	}
+	return encodeErr
+}
+
+// formatDocument collects the run into a summary and writes it once at the end
+func (f *JSONFormatter) formatDocument(events <-chan StreamEvent) error {
+	summary := NewSummary()
+	for event := range events {
+		summary.Add(event)
+	}
+
+	encoder := json.NewEncoder(f.output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(summary); err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	return nil
+}
+
+// toJSONEvent converts a stream event to its wire format
+func toJSONEvent(event StreamEvent) jsonEvent {
+	out := jsonEvent{Type: event.Type.String()}
+
+	switch data := event.Data.(type) {
+	case SystemInitData:
+		out.Skill = data.SkillName
+		out.SkillPath = data.SkillPath
+		out.SessionID = data.SessionID
+	case ThinkingData:
+		out.Text = data.Text
+	case TextData:
+		out.Text = data.Text
+	case ToolCompleteData:
+		op := data.Operation
+		out.Tool = &op
+	case FinalResultData:
+		elapsed := data.Elapsed.Milliseconds()
+		out.Result = &data.Result
+		out.Subtype = data.Subtype
+		out.IsError = &data.IsError
+		out.ElapsedMS = &elapsed
+		out.SessionID = data.SessionID
+		out.StructuredOutput = data.StructuredOutput
+	case UsageData:
+		out.Usage = data.Usage
+	}
+
+	return out
+}
@@ -0,0 +1,97 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonTestStream = `{"type":"system","subtype":"init","session_id":"sess-1"}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Looking"},{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/tmp/a.txt"}}]}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"<tool_use_error>File does not exist.</tool_use_error>"}]}}
+{"type":"result","subtype":"success","result":"All done","is_error":false,"usage":{"input_tokens":10,"output_tokens":5}}`
+
+func TestJSONFormatter_NDJSON(t *testing.T) {
+	var output bytes.Buffer
+	f := New(Config{Output: &output, Format: FormatNDJSON, SkillName: "demo"})
+	if err := f.Format(strings.NewReader(jsonTestStream)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	var types []string
+	for _, line := range lines {
+		var event map[string]any
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Line is not valid JSON: %q: %v", line, err)
+		}
+		types = append(types, event["type"].(string))
+	}
+
+	want := []string{"init", "text", "tool", "result", "usage"}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Errorf("Event types = %v, want %v", types, want)
+	}
+
+	var tool struct {
+		Tool ToolOperation `json:"tool"`
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &tool); err != nil {
+		t.Fatalf("Failed to decode tool event: %v", err)
+	}
+	if tool.Tool.Name != "Read" || tool.Tool.Target != "a.txt" {
+		t.Errorf("Tool = %+v, want Read a.txt", tool.Tool)
+	}
+	if tool.Tool.Status != "error" || tool.Tool.Error != "File does not exist." {
+		t.Errorf("Tool status/error = %q/%q, want error/File does not exist.", tool.Tool.Status, tool.Tool.Error)
+	}
+}
+
+func TestJSONFormatter_Document(t *testing.T) {
+	var output bytes.Buffer
+	f := New(Config{Output: &output, Format: FormatJSON, SkillName: "demo", SkillPath: "/skills/demo/SKILL.md"})
+	if err := f.Format(strings.NewReader(jsonTestStream)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	var summary Summary
+	if err := json.Unmarshal(output.Bytes(), &summary); err != nil {
+		t.Fatalf("Output is not a single JSON document: %v\n%s", err, output.String())
+	}
+
+	if summary.SkillName != "demo" {
+		t.Errorf("skill = %q, want demo", summary.SkillName)
+	}
+	if summary.SessionID != "sess-1" {
+		t.Errorf("session_id = %q, want sess-1", summary.SessionID)
+	}
+	if summary.Result != "All done" || summary.IsError || !summary.Completed {
+		t.Errorf("result = %q is_error=%v completed=%v", summary.Result, summary.IsError, summary.Completed)
+	}
+	if len(summary.Tools) != 1 || summary.Tools[0].Name != "Read" {
+		t.Errorf("tools = %+v, want one Read", summary.Tools)
+	}
+	if summary.Usage == nil || summary.Usage.InputTokens != 10 {
+		t.Errorf("usage = %+v, want input_tokens 10", summary.Usage)
+	}
+}
+
+func TestJSONFormatter_EmptyStream(t *testing.T) {
+	var output bytes.Buffer
+	f := New(Config{Output: &output, Format: FormatJSON})
+	if err := f.Format(strings.NewReader("")); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	var summary map[string]any
+	if err := json.Unmarshal(output.Bytes(), &summary); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if tools, ok := summary["tools"].([]any); !ok || len(tools) != 0 {
+		t.Errorf("tools should be an empty array, got %v", summary["tools"])
+	}
+	if summary["completed"] != false {
+		t.Errorf("completed should be false without a result, got %v", summary["completed"])
+	}
+}
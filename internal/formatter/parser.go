@@ -12,12 +12,21 @@ import (
 
 // Message represents different types of messages in the stream
 type Message struct {
-	Type    string          `json:"type"`
-	Message *MessageContent `json:"message,omitempty"`
-	Result  string          `json:"result,omitempty"`
-	Subtype string          `json:"subtype,omitempty"`
-	IsError bool            `json:"is_error,omitempty"`
-	Usage   *Usage          `json:"usage,omitempty"`
+	Type      string          `json:"type"`
+	Message   *MessageContent `json:"message,omitempty"`
+	Result    string          `json:"result,omitempty"`
+	Subtype   string          `json:"subtype,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+	Usage     *Usage          `json:"usage,omitempty"`
+	SessionID string          `json:"session_id,omitempty"`
+
+	// Run totals reported on the result message
+	TotalCostUSD  float64 `json:"total_cost_usd,omitempty"`
+	NumTurns      int     `json:"num_turns,omitempty"`
+	DurationAPIMS int64   `json:"duration_api_ms,omitempty"`
+
+	// StructuredOutput is the validated JSON result when running with --json-schema
+	StructuredOutput json.RawMessage `json:"structured_output,omitempty"`
 }
 
 // MessageContent represents the content of an assistant message
@@ -71,17 +80,22 @@ type Usage struct {
 	CacheCreationInputTokens int            `json:"cache_creation_input_tokens"`
 	CacheCreation            map[string]int `json:"cache_creation,omitempty"`
 	ServerToolUse            map[string]int `json:"server_tool_use,omitempty"`
+
+	// Run totals, copied from the result message
+	TotalCostUSD  float64 `json:"total_cost_usd,omitempty"`
+	NumTurns      int     `json:"num_turns,omitempty"`
+	DurationAPIMS int64   `json:"duration_api_ms,omitempty"`
 }
 
 // ToolOperation represents a tool call and its result
 type ToolOperation struct {
-	ID     string
-	Name   string
-	Target string // filename, command, or key parameter
-	Status string // "pending", "success", "error", "empty"
-	Error  string
-	Input  map[string]any
-	Result any
+	ID     string         `json:"id"`
+	Name   string         `json:"name"`
+	Target string         `json:"target,omitempty"` // filename, command, or key parameter
+	Status string         `json:"status"`           // "pending", "success", "error", "empty"
+	Error  string         `json:"error,omitempty"`
+	Input  map[string]any `json:"input,omitempty"`
+	Result any            `json:"-"`
 }
 
 // Output truncation limits
@@ -95,6 +109,7 @@ type ClaudeStreamParser struct {
 	tools       []ToolOperation
 	toolCallMap map[string]int
 	startTime   time.Time
+	sessionID   string
 	skillName   string
 	skillPath   string
 	verbose     bool
@@ -176,11 +191,13 @@ func (p *ClaudeStreamParser) handleMessage(msg Message, events chan<- StreamEven
 // handleSystemMessage processes system-level messages
 func (p *ClaudeStreamParser) handleSystemMessage(msg Message, events chan<- StreamEvent) {
 	if msg.Subtype == "init" {
+		p.sessionID = msg.SessionID
 		events <- StreamEvent{
 			Type: EventSystemInit,
 			Data: SystemInitData{
 				SkillName: p.skillName,
 				SkillPath: p.skillPath,
+				SessionID: msg.SessionID,
 			},
 		}
 	}
@@ -251,22 +268,39 @@ func (p *ClaudeStreamParser) handleUserMessage(msg Message, events chan<- Stream
 
 // handleResultMessage processes final result messages
 func (p *ClaudeStreamParser) handleResultMessage(msg Message, events chan<- StreamEvent) {
+	// Prefer the session ID on the result, falling back to the one from init
+	sessionID := msg.SessionID
+	if sessionID == "" {
+		sessionID = p.sessionID
+	}
+
 	// Emit final result event
 	elapsed := time.Since(p.startTime)
 	events <- StreamEvent{
 		Type: EventFinalResult,
 		Data: FinalResultData{
-			Result:  msg.Result,
-			IsError: msg.IsError,
-			Elapsed: elapsed,
+			Result:    msg.Result,
+			Subtype:   msg.Subtype,
+			IsError:   msg.IsError || isErrorSubtype(msg.Subtype),
+			Elapsed:   elapsed,
+			SessionID: sessionID,
+
+			StructuredOutput: msg.StructuredOutput,
 		},
 	}
 
-	// Emit usage event if available
-	if msg.Usage != nil {
+	// Emit usage event if available, with the run's cost and turns
+	usage := msg.Usage
+	if usage == nil && (msg.TotalCostUSD > 0 || msg.NumTurns > 0) {
+		usage = &Usage{}
+	}
+	if usage != nil {
+		usage.TotalCostUSD = msg.TotalCostUSD
+		usage.NumTurns = msg.NumTurns
+		usage.DurationAPIMS = msg.DurationAPIMS
 		events <- StreamEvent{
 			Type: EventUsage,
-			Data: UsageData{Usage: msg.Usage},
+			Data: UsageData{Usage: usage},
 		}
 	}
 }
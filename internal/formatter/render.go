@@ -58,6 +58,11 @@ func renderMarkdown(mdRenderer *glamour.TermRenderer, text string) string {
 	return strings.TrimSpace(rendered)
 }
 
+// resumeHint returns a dimmed line telling the user how to resume a session
+func resumeHint(sessionID string) string {
+	return dimStyle.Render("  Resume with: skillet --resume " + sessionID)
+}
+
 // stripSystemReminders removes <system-reminder>...</system-reminder> tags and their content
 func stripSystemReminders(text string) string {
 	for {
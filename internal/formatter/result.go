@@ -0,0 +1,55 @@
+package formatter
+
+import "strings"
+
+// Result subtypes reported by the Claude CLI
+const (
+	SubtypeSuccess         = "success"
+	SubtypeMaxTurns        = "error_max_turns"
+	SubtypeMaxBudget       = "error_max_budget_usd"
+	SubtypeDuringExecution = "error_during_execution"
+)
+
+// ResultError is returned when the run's final result is an error
+type ResultError struct {
+	Subtype string // Result subtype, e.g. SubtypeMaxTurns
+	Result  string // Final result text
+}
+
+func (e *ResultError) Error() string {
+	if reason := subtypeReason(e.Subtype); reason != "" {
+		return "run failed: " + reason
+	}
+	if msg, _, _ := strings.Cut(strings.TrimSpace(e.Result), "\n"); msg != "" {
+		return "run failed: " + msg
+	}
+	if e.Subtype != "" && e.Subtype != SubtypeSuccess {
+		return "run failed: " + e.Subtype
+	}
+	return "run failed"
+}
+
+// isErrorSubtype reports whether a result subtype means the run failed
+func isErrorSubtype(subtype string) bool {
+	return strings.HasPrefix(subtype, "error")
+}
+
+// subtypeReason describes a subtype for the "Failed" line, or returns "" when
+// the result text says more
+func subtypeReason(subtype string) string {
+	switch subtype {
+	case SubtypeMaxTurns:
+		return "reached the maximum number of turns"
+	case SubtypeMaxBudget:
+		return "reached the maximum budget"
+	}
+	return ""
+}
+
+// failedLine is the completion status printed for a failed result
+func failedLine(data FinalResultData) string {
+	if reason := subtypeReason(data.Subtype); reason != "" {
+		return errorIcon.String() + " Failed: " + reason
+	}
+	return errorIcon.String() + " Failed"
+}
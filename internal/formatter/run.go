@@ -0,0 +1,43 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RunStream runs execute with its stdout parsed by parser as it arrives,
+// calling onEvent for each event. The run's error wins over a parse error, and
+// carries whatever the run wrote to stderr.
+func RunStream(parser *ClaudeStreamParser, execute func(stdout, stderr io.Writer) error, onEvent func(StreamEvent)) (*Summary, error) {
+	pr, pw := io.Pipe()
+	var stderr strings.Builder
+	execErr := make(chan error, 1)
+	go func() {
+		err := execute(pw, &stderr)
+		_ = pw.Close()
+		execErr <- err
+	}()
+
+	events, errs := parser.Parse(pr)
+	summary := NewSummary()
+	for event := range events {
+		summary.Add(event)
+		if onEvent != nil {
+			onEvent(event)
+		}
+	}
+	parseErr := <-errs
+	_, _ = io.Copy(io.Discard, pr) // Keep the agent unblocked after a stream error
+
+	if err := <-execErr; err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return summary, err
+	}
+	if parseErr != nil {
+		return summary, fmt.Errorf("failed to read session: %w", parseErr)
+	}
+	return summary, nil
+}
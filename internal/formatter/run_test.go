@@ -0,0 +1,73 @@
+package formatter
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRunStream(t *testing.T) {
+	stream := `{"type":"system","subtype":"init","model":"claude-sonnet-4-5"}` + "\n" +
+		`{"type":"result","subtype":"success","result":"done","is_error":false}` + "\n"
+
+	tests := []struct {
+		name    string
+		execute func(stdout, stderr io.Writer) error
+		wantErr string
+		wantRes string
+	}{
+		{
+			name: "success",
+			execute: func(stdout, stderr io.Writer) error {
+				_, err := io.WriteString(stdout, stream)
+				return err
+			},
+			wantRes: "done",
+		},
+		{
+			name: "run error carries stderr",
+			execute: func(stdout, stderr io.Writer) error {
+				_, _ = io.WriteString(stdout, stream)
+				_, _ = io.WriteString(stderr, "agent crashed\n")
+				return errors.New("exit status 1")
+			},
+			wantErr: "exit status 1: agent crashed",
+			wantRes: "done",
+		},
+		{
+			name: "run error wins over parse error",
+			execute: func(stdout, stderr io.Writer) error {
+				_, _ = io.WriteString(stdout, strings.Repeat("x", 2*1024*1024)+"\n")
+				return errors.New("exit status 2")
+			},
+			wantErr: "exit status 2",
+		},
+		{
+			name: "parse error",
+			execute: func(stdout, stderr io.Writer) error {
+				_, _ = io.WriteString(stdout, strings.Repeat("x", 2*1024*1024)+"\n")
+				return nil
+			},
+			wantErr: "failed to read session",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events int
+			summary, err := RunStream(NewStreamParser("", "", false), tt.execute, func(StreamEvent) { events++ })
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("RunStream error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("RunStream error = %v, want %q", err, tt.wantErr)
+			}
+			if summary.Result != tt.wantRes {
+				t.Errorf("summary.Result = %q, want %q", summary.Result, tt.wantRes)
+			}
+			if tt.wantRes != "" && events == 0 {
+				t.Error("onEvent was never called")
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/jsonschema"
+)
+
+// SchemaMismatchError is returned when the final result doesn't match the output schema
+type SchemaMismatchError struct {
+	Err error
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf("result does not match output schema: %v", e.Err)
+}
+
+func (e *SchemaMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// StructuredFormatter validates the final result against a JSON Schema.
+// Events are forwarded to an inner formatter for progress output. When output
+// is set, only the validated JSON is written to it so it can be piped to jq.
+type StructuredFormatter struct {
+	inner  Formatter
+	output io.Writer
+	schema *jsonschema.Schema
+}
+
+// NewStructuredFormatter wraps inner with schema validation of the final result.
+// Pass a nil output to validate without printing the JSON result.
+func NewStructuredFormatter(inner Formatter, output io.Writer, schema *jsonschema.Schema) *StructuredFormatter {
+	return &StructuredFormatter{
+		inner:  inner,
+		output: output,
+		schema: schema,
+	}
+}
+
+// Format forwards events to the inner formatter, then validates and prints the result
+func (f *StructuredFormatter) Format(events <-chan StreamEvent) error {
+	forward := make(chan StreamEvent, 10)
+	innerErr := make(chan error, 1)
+	go func() {
+		innerErr <- f.inner.Format(forward)
+	}()
+
+	var final *FinalResultData
+	for event := range events {
+		if data, ok := event.Data.(FinalResultData); ok {
+			final = &data
+		}
+		forward <- event
+	}
+	close(forward)
+
+	if err := <-innerErr; err != nil {
+		return err
+	}
+
+	if final == nil {
+		return fmt.Errorf("no structured output: run ended without a result")
+	}
+	if final.IsError {
+		return &ResultError{Subtype: final.Subtype, Result: final.Result}
+	}
+
+	data := structuredOutput(*final)
+	if err := f.schema.ValidateJSON(data); err != nil {
+		return &SchemaMismatchError{Err: err}
+	}
+
+	if f.output != nil {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, data); err != nil {
+			return fmt.Errorf("failed to write structured output: %w", err)
+		}
+		compact.WriteByte('\n')
+		if _, err := f.output.Write(compact.Bytes()); err != nil {
+			return fmt.Errorf("failed to write structured output: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// structuredOutput returns the JSON result of a run. Claude reports it in
+// structured_output; older CLIs only put it in the result text, sometimes fenced.
+func structuredOutput(data FinalResultData) []byte {
+	if len(data.StructuredOutput) > 0 {
+		return data.StructuredOutput
+	}
+
+	text := strings.TrimSpace(data.Result)
+	if strings.HasPrefix(text, "```") {
+		// Drop the opening fence line (``` or ```json) and the closing fence
+		if idx := strings.Index(text, "\n"); idx != -1 {
+			text = text[idx+1:]
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+	}
+	return []byte(text)
+}
@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/martinemde/skillet/internal/jsonschema"
+)
+
+const severitySchema = `{"type":"object","required":["severity"],"properties":{"severity":{"enum":["low","medium","high"]}}}`
+
+func TestStructuredFormatter_PrintsValidatedJSON(t *testing.T) {
+	schema, err := jsonschema.Compile([]byte(severitySchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := `{"type":"system","subtype":"init","session_id":"sess-1"}
+{"type":"result","subtype":"success","result":"done","structured_output":{"severity": "high"},"is_error":false}`
+
+	var output, progress bytes.Buffer
+	f := New(Config{Output: &output, Progress: &progress, Schema: schema, Color: "never"})
+	if err := f.Format(strings.NewReader(stream)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	if output.String() != `{"severity":"high"}`+"\n" {
+		t.Errorf("Output = %q, want only the compact JSON result", output.String())
+	}
+	if !strings.Contains(progress.String(), "Completed") {
+		t.Errorf("Progress should contain the terminal view, got %q", progress.String())
+	}
+}
+
+func TestStructuredFormatter_FencedResultText(t *testing.T) {
+	schema, err := jsonschema.Compile([]byte(severitySchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := `{"type":"result","subtype":"success","result":"` + "```json\\n{\\\"severity\\\": \\\"low\\\"}\\n```" + `","is_error":false}`
+
+	var output bytes.Buffer
+	f := New(Config{Output: &output, Schema: schema})
+	if err := f.Format(strings.NewReader(stream)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if output.String() != `{"severity":"low"}`+"\n" {
+		t.Errorf("Output = %q, want fenced JSON extracted from result", output.String())
+	}
+}
+
+func TestStructuredFormatter_SchemaMismatch(t *testing.T) {
+	schema, err := jsonschema.Compile([]byte(severitySchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := `{"type":"result","subtype":"success","result":"done","structured_output":{"severity":"urgent"},"is_error":false}`
+
+	var output bytes.Buffer
+	f := New(Config{Output: &output, Schema: schema})
+	err = f.Format(strings.NewReader(stream))
+
+	var mismatch *SchemaMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected SchemaMismatchError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "/severity") {
+		t.Errorf("Error should name the offending path, got %v", err)
+	}
+	if output.Len() != 0 {
+		t.Errorf("Invalid output should not be printed, got %q", output.String())
+	}
+}
@@ -0,0 +1,54 @@
+package formatter
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Summary accumulates the normalized events of a single run.
+// It is the machine-readable counterpart to what the terminal formatters print.
+type Summary struct {
+	SkillName string          `json:"skill,omitempty"`
+	SkillPath string          `json:"skill_path,omitempty"`
+	SessionID string          `json:"session_id,omitempty"`
+	Tools     []ToolOperation `json:"tools"`
+	Result    string          `json:"result"`
+	Subtype   string          `json:"subtype,omitempty"`
+	IsError   bool            `json:"is_error"`
+	Completed bool            `json:"completed"` // true once a final result was received
+	Elapsed   time.Duration   `json:"-"`
+	ElapsedMS int64           `json:"elapsed_ms"`
+	Usage     *Usage          `json:"usage,omitempty"`
+
+	StructuredOutput json.RawMessage `json:"structured_output,omitempty"`
+}
+
+// NewSummary creates an empty summary
+func NewSummary() *Summary {
+	return &Summary{Tools: []ToolOperation{}}
+}
+
+// Add records an event in the summary
+func (s *Summary) Add(event StreamEvent) {
+	switch data := event.Data.(type) {
+	case SystemInitData:
+		s.SkillName = data.SkillName
+		s.SkillPath = data.SkillPath
+		s.SessionID = data.SessionID
+	case ToolCompleteData:
+		s.Tools = append(s.Tools, data.Operation)
+	case FinalResultData:
+		s.Result = data.Result
+		s.Subtype = data.Subtype
+		s.IsError = data.IsError
+		s.Completed = true
+		s.Elapsed = data.Elapsed
+		s.ElapsedMS = data.Elapsed.Milliseconds()
+		if data.SessionID != "" {
+			s.SessionID = data.SessionID
+		}
+		s.StructuredOutput = data.StructuredOutput
+	case UsageData:
+		s.Usage = data.Usage
+	}
+}
@@ -201,10 +201,14 @@ func (f *TerminalFormatter) printFinalResult(data FinalResultData) {
 	// Print completion status
 	_, _ = fmt.Fprintln(f.output)
 	if data.IsError {
-		_, _ = fmt.Fprintln(f.output, errorIcon.String()+" Failed")
+		_, _ = fmt.Fprintln(f.output, failedLine(data))
 	} else {
 		_, _ = fmt.Fprintf(f.output, "%s Completed in %.1fs\n", successIcon.String(), data.Elapsed.Seconds())
 	}
+
+	if data.SessionID != "" {
+		_, _ = fmt.Fprintln(f.output, resumeHint(data.SessionID))
+	}
 }
 
 // printUsage prints token usage information in a styled table
@@ -239,6 +243,18 @@ func (f *TerminalFormatter) printUsage(usage *Usage) {
 		}
 	}
 
+	if usage.NumTurns > 0 {
+		rows = append(rows, []string{"Turns", fmt.Sprintf("%d", usage.NumTurns)})
+	}
+
+	if usage.DurationAPIMS > 0 {
+		rows = append(rows, []string{"API time", fmt.Sprintf("%.1fs", float64(usage.DurationAPIMS)/1000)})
+	}
+
+	if usage.TotalCostUSD > 0 {
+		rows = append(rows, []string{"Cost", fmt.Sprintf("$%.4f", usage.TotalCostUSD)})
+	}
+
 	// Create styled table - colors are handled by global lipgloss profile
 	t := table.New().
 		Border(lipgloss.NormalBorder()).
@@ -0,0 +1,403 @@
+// Package gitsource fetches skills from git repositories referenced as
+// github:owner/repo/path@ref or git+<url>@ref#path into a cache keyed by commit.
+package gitsource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reference prefixes
+const (
+	githubPrefix = "github:"
+	gitPrefix    = "git+"
+)
+
+// refsDir holds the last commit seen for each repository ref, so offline
+// runs can find a ref's checkout
+const refsDir = "refs"
+
+// commitRegex matches a full commit SHA
+var commitRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Source is a skill in a git repository
+type Source struct {
+	Repo   string // URL git fetches from
+	Ref    string // Branch, tag, or full commit SHA; empty for the default branch
+	Subdir string // Skill directory or file inside the repository; empty to find the only SKILL.md
+}
+
+// IsSource reports whether s is a git source reference
+func IsSource(s string) bool {
+	return strings.HasPrefix(s, githubPrefix) || strings.HasPrefix(s, gitPrefix)
+}
+
+// Parse parses a github: or git+ reference:
+//
+//	github:owner/repo/path/to/skill@ref
+//	git+https://host/repo.git@ref#path/to/skill
+//	git+file:///srv/skills.git#path/to/skill
+func Parse(s string) (Source, error) {
+	switch {
+	case strings.HasPrefix(s, githubPrefix):
+		return parseGitHub(s)
+	case strings.HasPrefix(s, gitPrefix):
+		return parseGitURL(s)
+	default:
+		return Source{}, fmt.Errorf("not a git source: %s", s)
+	}
+}
+
+// parseGitHub parses github:owner/repo[/path][@ref]
+func parseGitHub(s string) (Source, error) {
+	rest := strings.TrimPrefix(s, githubPrefix)
+
+	var src Source
+	if i := strings.LastIndex(rest, "@"); i != -1 {
+		rest, src.Ref = rest[:i], rest[i+1:]
+		if src.Ref == "" {
+			return Source{}, fmt.Errorf("invalid git source %q: empty ref after @", s)
+		}
+	}
+
+	parts := strings.SplitN(strings.Trim(rest, "/"), "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return Source{}, fmt.Errorf("invalid git source %q: expected github:owner/repo[/path][@ref]", s)
+	}
+	src.Repo = "https://github.com/" + parts[0] + "/" + strings.TrimSuffix(parts[1], ".git") + ".git"
+	if len(parts) == 3 {
+		src.Subdir = parts[2]
+	}
+	return src.validate(s)
+}
+
+// parseGitURL parses git+<url>[@ref][#path]
+func parseGitURL(s string) (Source, error) {
+	rest := strings.TrimPrefix(s, gitPrefix)
+
+	var src Source
+	rest, src.Subdir, _ = strings.Cut(rest, "#")
+
+	// A ref follows the last path segment, so user@host in the URL isn't one
+	if slash := strings.LastIndex(rest, "/"); slash != -1 {
+		if at := strings.LastIndex(rest[slash:], "@"); at != -1 {
+			rest, src.Ref = rest[:slash+at], rest[slash+at+1:]
+			if src.Ref == "" {
+				return Source{}, fmt.Errorf("invalid git source %q: empty ref after @", s)
+			}
+		}
+	}
+
+	u, err := url.Parse(rest)
+	if err != nil {
+		return Source{}, fmt.Errorf("invalid git source %q: %w", s, err)
+	}
+	switch u.Scheme {
+	case "https", "http", "ssh", "file":
+	default:
+		return Source{}, fmt.Errorf("invalid git source %q: unsupported scheme %q", s, u.Scheme)
+	}
+	src.Repo = rest
+	return src.validate(s)
+}
+
+// validate checks the ref and subdirectory
+func (src Source) validate(s string) (Source, error) {
+	if strings.HasPrefix(src.Ref, "-") {
+		return Source{}, fmt.Errorf("invalid git source %q: invalid ref %q", s, src.Ref)
+	}
+	if src.Subdir != "" {
+		clean := path.Clean(src.Subdir)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return Source{}, fmt.Errorf("invalid git source %q: path must stay inside the repository", s)
+		}
+		src.Subdir = clean
+	}
+	return src, nil
+}
+
+// String returns the reference in git+ form
+func (src Source) String() string {
+	s := gitPrefix + src.Repo
+	if src.Ref != "" {
+		s += "@" + src.Ref
+	}
+	if src.Subdir != "" {
+		s += "#" + src.Subdir
+	}
+	return s
+}
+
+// Fetch checks out the source's commit into cacheDir/<commit> and returns
+// the checkout directory and commit. A commit already in the cache is
+// reused; refs other than a full SHA are looked up on each fetch unless
+// offline, which uses the commit the ref last resolved to.
+func (src Source) Fetch(cacheDir string, offline bool) (dir, commit string, err error) {
+	if offline {
+		return src.fetchCached(cacheDir)
+	}
+
+	commit, remoteRef, err := src.resolveCommit()
+	if err != nil {
+		return "", "", err
+	}
+
+	dir = filepath.Join(cacheDir, commit)
+	if _, err := os.Stat(dir); err == nil {
+		touch(dir)
+		src.saveRef(cacheDir, commit)
+		return dir, commit, nil
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create git cache: %w", err)
+	}
+	tmp, err := os.MkdirTemp(cacheDir, ".fetch-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create git cache: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if _, err := git(tmp, "init", "-q"); err != nil {
+		return "", "", err
+	}
+	if _, err := git(tmp, "fetch", "-q", "--depth", "1", src.Repo, remoteRef); err != nil {
+		return "", "", err
+	}
+	if _, err := git(tmp, "-c", "advice.detachedHead=false", "checkout", "-q", "--detach", "FETCH_HEAD"); err != nil {
+		return "", "", err
+	}
+	head, err := git(tmp, "rev-parse", "HEAD")
+	if err != nil {
+		return "", "", err
+	}
+	if head != commit {
+		return "", "", fmt.Errorf("fetched %s but expected %s from %s", head, commit, src.Repo)
+	}
+	if err := os.RemoveAll(filepath.Join(tmp, ".git")); err != nil {
+		return "", "", fmt.Errorf("failed to clean up checkout: %w", err)
+	}
+
+	if err := os.Rename(tmp, dir); err != nil {
+		// Another skillet may have cached the same commit meanwhile
+		if _, statErr := os.Stat(dir); statErr != nil {
+			return "", "", fmt.Errorf("failed to cache checkout: %w", err)
+		}
+	}
+	src.saveRef(cacheDir, commit)
+	return dir, commit, nil
+}
+
+// fetchCached returns a cached checkout without using the network
+func (src Source) fetchCached(cacheDir string) (dir, commit string, err error) {
+	commit = src.Ref
+	if !commitRegex.MatchString(commit) {
+		entry, err := readRef(filepath.Join(cacheDir, refsDir, refKey(src.Repo, src.Ref)))
+		if err != nil {
+			return "", "", fmt.Errorf("%s is not cached (offline)", src)
+		}
+		commit = entry.Commit
+	}
+
+	dir = filepath.Join(cacheDir, commit)
+	if _, err := os.Stat(dir); err != nil {
+		return "", "", fmt.Errorf("%s is not cached (offline)", src)
+	}
+	touch(dir)
+	return dir, commit, nil
+}
+
+// CachedRef records the commit a repository ref resolved to
+type CachedRef struct {
+	Repo   string    `json:"repo"`
+	Ref    string    `json:"ref,omitempty"`
+	Commit string    `json:"commit"`
+	UsedAt time.Time `json:"used_at"`
+
+	dir string // Checkout directory, set by List
+}
+
+// Dir returns the ref's checkout directory
+func (r CachedRef) Dir() string {
+	return r.dir
+}
+
+// refKey returns the file name for a repository ref
+func refKey(repo, ref string) string {
+	sum := sha256.Sum256([]byte(repo + "@" + ref))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// saveRef records the commit for the source's ref. The cache still works
+// without it, so failures are ignored.
+func (src Source) saveRef(cacheDir, commit string) {
+	data, err := json.MarshalIndent(CachedRef{Repo: src.Repo, Ref: src.Ref, Commit: commit, UsedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(cacheDir, refsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, refKey(src.Repo, src.Ref)), data, 0o644)
+}
+
+// readRef reads a ref record
+func readRef(path string) (*CachedRef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entry := &CachedRef{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// touch marks a checkout as used so Prune keeps it
+func touch(dir string) {
+	now := time.Now()
+	_ = os.Chtimes(dir, now, now)
+}
+
+// List returns the cached refs whose checkouts still exist, most recently used first
+func List(cacheDir string) ([]CachedRef, error) {
+	files, err := os.ReadDir(filepath.Join(cacheDir, refsDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read git cache: %w", err)
+	}
+
+	var refs []CachedRef
+	for _, f := range files {
+		entry, err := readRef(filepath.Join(cacheDir, refsDir, f.Name()))
+		if err != nil || !commitRegex.MatchString(entry.Commit) {
+			continue
+		}
+		entry.dir = filepath.Join(cacheDir, entry.Commit)
+		info, err := os.Stat(entry.dir)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(entry.UsedAt) {
+			entry.UsedAt = info.ModTime()
+		}
+		refs = append(refs, *entry)
+	}
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].UsedAt.After(refs[j].UsedAt) })
+	return refs, nil
+}
+
+// Prune removes checkouts not used since before, along with refs that no
+// longer have a checkout, and returns how many checkouts were removed
+func Prune(cacheDir string, before time.Time) (int, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read git cache: %w", err)
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if !e.IsDir() || !commitRegex.MatchString(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(cacheDir, e.Name())); err != nil {
+			return removed, fmt.Errorf("failed to remove cached checkout: %w", err)
+		}
+		removed++
+	}
+
+	refs, _ := os.ReadDir(filepath.Join(cacheDir, refsDir))
+	for _, f := range refs {
+		path := filepath.Join(cacheDir, refsDir, f.Name())
+		entry, err := readRef(path)
+		if err == nil && commitRegex.MatchString(entry.Commit) {
+			if _, err := os.Stat(filepath.Join(cacheDir, entry.Commit)); err == nil {
+				continue
+			}
+		}
+		_ = os.Remove(path)
+	}
+	return removed, nil
+}
+
+// resolveCommit returns the commit for the source's ref and the remote ref to fetch
+func (src Source) resolveCommit() (commit, remoteRef string, err error) {
+	if commitRegex.MatchString(src.Ref) {
+		return src.Ref, src.Ref, nil
+	}
+
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	out, err := git("", "ls-remote", src.Repo, ref, ref+"^{}")
+	if err != nil {
+		return "", "", err
+	}
+
+	// Prefer the exact ref, then branches, then tags. Annotated tags list
+	// the tagged commit as <tag>^{}.
+	found := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		sha, name, ok := strings.Cut(line, "\t")
+		if ok {
+			found[name] = sha
+		}
+	}
+	for _, name := range []string{ref, "refs/heads/" + ref, "refs/tags/" + ref} {
+		if sha, ok := found[name+"^{}"]; ok {
+			return sha, name, nil
+		}
+		if sha, ok := found[name]; ok {
+			return sha, name, nil
+		}
+	}
+	return "", "", fmt.Errorf("ref %q not found in %s (use a branch, tag, or full commit SHA)", ref, src.Repo)
+}
+
+// git runs a git command and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("git is required for git sources: %w", err)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
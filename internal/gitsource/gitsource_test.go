@@ -0,0 +1,214 @@
+package gitsource
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Source
+	}{
+		{"github:acme/skills", Source{Repo: "https://github.com/acme/skills.git"}},
+		{"github:acme/skills/review@v1.2.0", Source{Repo: "https://github.com/acme/skills.git", Ref: "v1.2.0", Subdir: "review"}},
+		{"github:acme/skills.git/tools/fmt/@main", Source{Repo: "https://github.com/acme/skills.git", Ref: "main", Subdir: "tools/fmt"}},
+		{"git+https://example.com/team/skills.git@main#review", Source{Repo: "https://example.com/team/skills.git", Ref: "main", Subdir: "review"}},
+		{"git+ssh://git@example.com/team/skills.git#review", Source{Repo: "ssh://git@example.com/team/skills.git", Subdir: "review"}},
+		{"git+file:///srv/skills.git", Source{Repo: "file:///srv/skills.git"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"github:acme", "expected github:owner/repo"},
+		{"github:acme/skills@", "empty ref"},
+		{"github:acme/skills/../../etc", "inside the repository"},
+		{"git+ftp://example.com/skills.git", "unsupported scheme"},
+		{"git+https://example.com/skills.git@--upload-pack=x", "invalid ref"},
+		{"skills", "not a git source"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// runGit runs git in dir, failing the test on error
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// makeBareRepo creates a bare repository with a skill at skills/review,
+// tagged v1, and a later commit on main. It returns the repo path and the v1 commit.
+func makeBareRepo(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	work := t.TempDir()
+	runGit(t, work, "init", "-q", "-b", "main")
+	skillDir := filepath.Join(work, "skills", "review")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: review\n---\nv1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-q", "-m", "v1")
+	runGit(t, work, "tag", "-a", "v1", "-m", "v1")
+	v1 := runGit(t, work, "rev-parse", "HEAD")
+
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: review\n---\nv2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "commit", "-q", "-am", "v2")
+
+	bare := filepath.Join(t.TempDir(), "skills.git")
+	runGit(t, "", "clone", "-q", "--bare", work, bare)
+	return bare, v1
+}
+
+func TestFetch(t *testing.T) {
+	bare, v1 := makeBareRepo(t)
+	cache := t.TempDir()
+
+	readSkill := func(dir string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, "skills", "review", "SKILL.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	src, err := Parse("git+file://" + bare + "@v1#skills/review")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, commit, err := src.Fetch(cache, false)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if commit != v1 || dir != filepath.Join(cache, v1) {
+		t.Errorf("Fetch() = %s, %s, want the v1 commit %s", dir, commit, v1)
+	}
+	if !strings.Contains(readSkill(dir), "v1") {
+		t.Error("checkout should contain the tagged version")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Error("cached checkout should not keep .git")
+	}
+
+	main, err := Parse("git+file://" + bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, commit, err = main.Fetch(cache, false)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if commit == v1 || !strings.Contains(readSkill(dir), "v2") {
+		t.Error("the default branch should fetch the latest commit")
+	}
+
+	// A pinned commit in the cache needs no repository
+	if err := os.RemoveAll(bare); err != nil {
+		t.Fatal(err)
+	}
+	pinned := Source{Repo: "file://" + bare, Ref: v1}
+	if dir, _, err := pinned.Fetch(cache, false); err != nil || dir != filepath.Join(cache, v1) {
+		t.Errorf("Fetch() of a cached commit = %s, %v", dir, err)
+	}
+}
+
+func TestFetch_UnknownRef(t *testing.T) {
+	bare, _ := makeBareRepo(t)
+	src := Source{Repo: "file://" + bare, Ref: "nope"}
+	if _, _, err := src.Fetch(t.TempDir(), false); err == nil || !strings.Contains(err.Error(), `ref "nope" not found`) {
+		t.Errorf("Fetch() error = %v, want ref not found", err)
+	}
+}
+
+func TestFetch_Commit(t *testing.T) {
+	bare, v1 := makeBareRepo(t)
+	src := Source{Repo: "file://" + bare, Ref: v1}
+	if _, commit, err := src.Fetch(t.TempDir(), false); err != nil || commit != v1 {
+		t.Errorf("Fetch() = %s, %v, want %s", commit, err, v1)
+	}
+}
+
+func TestFetch_Offline(t *testing.T) {
+	bare, v1 := makeBareRepo(t)
+	cache := t.TempDir()
+	src := Source{Repo: "file://" + bare, Ref: "v1", Subdir: "skills/review"}
+
+	if _, _, err := src.Fetch(cache, true); err == nil || !strings.Contains(err.Error(), "not cached") {
+		t.Errorf("offline Fetch() before caching error = %v, want not cached", err)
+	}
+	if _, _, err := src.Fetch(cache, false); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	// The ref resolves from the cache once the repository is gone
+	if err := os.RemoveAll(bare); err != nil {
+		t.Fatal(err)
+	}
+	dir, commit, err := src.Fetch(cache, true)
+	if err != nil || commit != v1 || dir != filepath.Join(cache, v1) {
+		t.Errorf("offline Fetch() = %s, %s, %v, want the v1 commit", dir, commit, err)
+	}
+
+	refs, err := List(cache)
+	if err != nil || len(refs) != 1 || refs[0].Ref != "v1" || refs[0].Commit != v1 {
+		t.Fatalf("List() = %+v, %v", refs, err)
+	}
+
+	if n, err := Prune(cache, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("Prune() of recent checkouts = %d, %v, want 0", n, err)
+	}
+	if n, err := Prune(cache, time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("Prune() = %d, %v, want 1", n, err)
+	}
+	if refs, _ := List(cache); len(refs) != 0 {
+		t.Errorf("List() after Prune() = %+v, want none", refs)
+	}
+}
@@ -0,0 +1,149 @@
+package harness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/formatter"
+	"github.com/martinemde/skillet/internal/toolrule"
+)
+
+// check evaluates a case's expectations and returns the failures.
+// Files are checked in workspace; tool paths are made relative to sessionDir.
+func check(expect Expect, summary *formatter.Summary, exitCode int, workspace, sessionDir string) []string {
+	var failures []string
+
+	wantExit := 0
+	if expect.ExitCode != nil {
+		wantExit = *expect.ExitCode
+	}
+	if exitCode != wantExit {
+		failures = append(failures, fmt.Sprintf("exit code %d, want %d", exitCode, wantExit))
+	}
+
+	if !summary.Completed {
+		if wantExit == 0 {
+			failures = append(failures, "session ended without a final result")
+		}
+	} else {
+		wantError := false
+		if expect.IsError != nil {
+			wantError = *expect.IsError
+		}
+		if summary.IsError != wantError {
+			failures = append(failures, fmt.Sprintf("is_error %t, want %t", summary.IsError, wantError))
+		}
+	}
+
+	failures = append(failures, checkText("result", summary.Result, expect.Result)...)
+
+	for _, file := range expect.Files {
+		failures = append(failures, checkFile(file, workspace)...)
+	}
+
+	failures = append(failures, checkTools(expect.Tools, summary.Tools, sessionDir)...)
+
+	return failures
+}
+
+// checkText applies text assertions to a value described by label
+func checkText(label, text string, expect TextExpect) []string {
+	var failures []string
+
+	for _, s := range expect.Contains {
+		if !strings.Contains(text, s) {
+			failures = append(failures, fmt.Sprintf("%s does not contain %q", label, s))
+		}
+	}
+	for _, s := range expect.NotContains {
+		if strings.Contains(text, s) {
+			failures = append(failures, fmt.Sprintf("%s contains %q", label, s))
+		}
+	}
+	if expect.Matches != "" {
+		re, err := regexp.Compile(expect.Matches)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("invalid pattern for %s: %v", label, err))
+		} else if !re.MatchString(text) {
+			failures = append(failures, fmt.Sprintf("%s does not match /%s/", label, expect.Matches))
+		}
+	}
+
+	return failures
+}
+
+// checkFile applies a file assertion to the workspace
+func checkFile(expect FileExpect, workspace string) []string {
+	label := "file " + expect.Path
+	wantExists := expect.Exists == nil || *expect.Exists
+
+	content, err := os.ReadFile(filepath.Join(workspace, filepath.FromSlash(expect.Path)))
+	exists := err == nil
+	switch {
+	case !wantExists && exists:
+		return []string{label + " should not exist"}
+	case !wantExists:
+		return nil
+	case !exists:
+		return []string{label + " does not exist"}
+	}
+
+	return checkText(label, string(content), expect.TextExpect)
+}
+
+// checkTools applies tool call assertions to the tools used in the session
+func checkTools(expect ToolsExpect, tools []formatter.ToolOperation, sessionDir string) []string {
+	var failures []string
+
+	for _, s := range expect.Called {
+		rule, err := toolrule.Parse(s)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		if _, ok := findCall(rule, tools, sessionDir); !ok {
+			failures = append(failures, fmt.Sprintf("expected a call matching %s", rule))
+		}
+	}
+	for _, s := range expect.NotCalled {
+		rule, err := toolrule.Parse(s)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		if target, ok := findCall(rule, tools, sessionDir); ok {
+			failures = append(failures, fmt.Sprintf("unexpected call matching %s: %s", rule, strings.TrimSpace(rule.Tool+" "+target)))
+		}
+	}
+
+	return failures
+}
+
+// findCall returns the target of the first tool call matching rule
+func findCall(rule toolrule.Rule, tools []formatter.ToolOperation, sessionDir string) (string, bool) {
+	for _, op := range tools {
+		target := callTarget(op, sessionDir)
+		if rule.Matches(op.Name, target) {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+// callTarget returns the full input a rule specifier matches against:
+// the command for Bash, a session-relative path for file tools, and so on.
+func callTarget(op formatter.ToolOperation, sessionDir string) string {
+	key, value := toolrule.InputTarget(op.Input)
+	if key == "" {
+		return op.Target
+	}
+	if strings.HasSuffix(key, "path") && filepath.IsAbs(value) && sessionDir != "" {
+		if rel, err := filepath.Rel(sessionDir, value); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return value
+}
@@ -0,0 +1,151 @@
+package harness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestsDir is the directory inside a skill that holds test cases
+const TestsDir = "tests"
+
+// Case is a single test case loaded from tests/<name>.yaml
+type Case struct {
+	Name      string            `yaml:"name,omitempty"`      // Defaults to the file name
+	Args      []string          `yaml:"args,omitempty"`      // Positional skill arguments
+	Named     map[string]string `yaml:"named,omitempty"`     // Named skill arguments (--name=value)
+	Prompt    string            `yaml:"prompt,omitempty"`    // User prompt; defaults to the skill description
+	Workspace string            `yaml:"workspace,omitempty"` // Fixture directory copied into the temp workspace
+	Replay    string            `yaml:"replay,omitempty"`    // Recorded stream-json JSONL to replay instead of running Claude
+	Timeout   string            `yaml:"timeout,omitempty"`   // Maximum duration of a live run, e.g. "5m"
+	Expect    Expect            `yaml:"expect,omitempty"`
+
+	// Path is the case file; relative paths in the case are resolved from its directory
+	Path string `yaml:"-"`
+}
+
+// Expect holds the assertions checked after a case runs
+type Expect struct {
+	ExitCode *int         `yaml:"exit_code,omitempty"` // Claude's exit code (default 0)
+	IsError  *bool        `yaml:"is_error,omitempty"`  // Whether the final result is an error (default false)
+	Result   TextExpect   `yaml:"result,omitempty"`
+	Files    []FileExpect `yaml:"files,omitempty"`
+	Tools    ToolsExpect  `yaml:"tools,omitempty"`
+}
+
+// TextExpect asserts on text such as the final result
+type TextExpect struct {
+	Contains    Strings `yaml:"contains,omitempty"`
+	NotContains Strings `yaml:"not_contains,omitempty"`
+	Matches     string  `yaml:"matches,omitempty"` // Regular expression
+}
+
+// FileExpect asserts on a file in the workspace after the run
+type FileExpect struct {
+	Path       string `yaml:"path"`
+	Exists     *bool  `yaml:"exists,omitempty"` // Default true
+	TextExpect `yaml:",inline"`
+}
+
+// ToolsExpect asserts on the tools called during the run.
+// Entries are permission rules like "Write" or "Bash(go test:*)".
+type ToolsExpect struct {
+	Called    Strings `yaml:"called,omitempty"`
+	NotCalled Strings `yaml:"not_called,omitempty"`
+}
+
+// Strings is a list of strings that may be written as a single string in YAML
+type Strings []string
+
+// UnmarshalYAML accepts either a scalar or a sequence
+func (s *Strings) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = Strings{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// LoadCases reads all test cases from a skill's tests directory, sorted by name
+func LoadCases(skillDir string) ([]*Case, error) {
+	dir := filepath.Join(skillDir, TestsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no tests found: %s does not exist", dir)
+		}
+		return nil, fmt.Errorf("failed to read tests: %w", err)
+	}
+
+	var cases []*Case
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		c, err := LoadCase(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no tests found in %s", dir)
+	}
+
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, nil
+}
+
+// LoadCase reads a single test case file
+func LoadCase(path string) (*Case, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test case: %w", err)
+	}
+
+	c := &Case{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	c.Path = path
+	if c.Name == "" {
+		c.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if _, err := c.timeout(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	return c, nil
+}
+
+// resolve returns a path from the case file relative to the case's directory
+func (c *Case) resolve(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(filepath.Dir(c.Path), p)
+}
+
+// timeout returns the parsed timeout, or zero for none
+func (c *Case) timeout() (time.Duration, error) {
+	if c.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q", c.Timeout)
+	}
+	return d, nil
+}
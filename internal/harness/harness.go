@@ -0,0 +1,284 @@
+// Package harness runs a skill's test cases and checks their assertions.
+// Each case runs in a temporary copy of its fixture workspace, either live
+// through the Claude CLI or by replaying a recorded stream-json session.
+package harness
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/executor"
+	"github.com/martinemde/skillet/internal/formatter"
+	"github.com/martinemde/skillet/internal/skill"
+)
+
+// Run modes
+const (
+	ModeLive   = "live"
+	ModeReplay = "replay"
+)
+
+// RecordingsDir holds recordings for cases that don't name a replay file
+const RecordingsDir = "recordings"
+
+// ExecuteFunc runs Claude with the given config, writing stream-json to stdout
+type ExecuteFunc func(ctx context.Context, cfg executor.Config, stdout, stderr io.Writer) error
+
+// Options configures a test run
+type Options struct {
+	SkillPath     string                    // Path to the skill's SKILL.md
+	SystemPrompt  func(*skill.Skill) string // Builds the system prompt for live runs
+	Live          bool                      // Ignore recordings and run Claude
+	Record        bool                      // Run Claude and save each session as the case's recording
+	KeepWorkspace bool                      // Keep temp workspaces for inspection
+	Backend       executor.Backend          // Backend for live runs; defaults to the Claude CLI
+	Execute       ExecuteFunc               // Overrides Backend, mainly for tests
+}
+
+// Result is the outcome of a single case
+type Result struct {
+	Case      *Case
+	Mode      string
+	Failures  []string // Assertions that did not hold
+	Err       error    // Set when the case could not run
+	Duration  time.Duration
+	Workspace string // Temp workspace, set when kept
+	Summary   *formatter.Summary
+}
+
+// Passed reports whether the case ran and all assertions held
+func (r *Result) Passed() bool {
+	return r.Err == nil && len(r.Failures) == 0
+}
+
+// Run runs each case in order and returns its result
+func Run(ctx context.Context, cases []*Case, opts Options) []*Result {
+	results := make([]*Result, 0, len(cases))
+	for _, c := range cases {
+		results = append(results, runCase(ctx, c, opts))
+	}
+	return results
+}
+
+// runCase runs a single case in a fresh workspace
+func runCase(ctx context.Context, c *Case, opts Options) *Result {
+	start := time.Now()
+	result := &Result{Case: c, Mode: ModeLive}
+	defer func() { result.Duration = time.Since(start) }()
+
+	workspace, err := os.MkdirTemp("", "skillet-test-")
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create workspace: %w", err)
+		return result
+	}
+	// Claude reports resolved paths (e.g. /private/var on macOS)
+	if resolved, err := filepath.EvalSymlinks(workspace); err == nil {
+		workspace = resolved
+	}
+	if opts.KeepWorkspace {
+		result.Workspace = workspace
+	} else {
+		defer func() { _ = os.RemoveAll(workspace) }()
+	}
+
+	if c.Workspace != "" {
+		if err := copyDir(c.resolve(c.Workspace), workspace); err != nil {
+			result.Err = fmt.Errorf("failed to copy workspace: %w", err)
+			return result
+		}
+	}
+
+	s, err := skill.ParseWithInput(opts.SkillPath, "", arguments.Input{
+		Positional: c.Args,
+		Named:      c.Named,
+		Dir:        workspace,
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("failed to parse skill: %w", err)
+		return result
+	}
+
+	recording, explicit := c.recordingPath()
+	_, statErr := os.Stat(recording)
+	replay := !opts.Live && !opts.Record && (explicit || statErr == nil)
+
+	var stream io.Reader
+	exitCode := 0
+	// Tool paths in the session are relative to the directory Claude ran in
+	sessionDir := workspace
+	if replay {
+		result.Mode = ModeReplay
+		data, err := os.ReadFile(recording)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to read recording: %w", err)
+			return result
+		}
+		stream = bytes.NewReader(data)
+		sessionDir = recordedWorkDir(data)
+	} else {
+		var stdout bytes.Buffer
+		exitCode, err = execute(ctx, c, s, workspace, opts, &stdout)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		if opts.Record {
+			if err := writeRecording(recording, stdout.Bytes()); err != nil {
+				result.Err = err
+				return result
+			}
+		}
+		stream = &stdout
+	}
+
+	summary, err := summarize(stream, s.Name, opts.SkillPath)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Summary = summary
+
+	if replay {
+		if err := applyEdits(summary.Tools, sessionDir, workspace); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	result.Failures = check(c.Expect, summary, exitCode, workspace, sessionDir)
+	return result
+}
+
+// execute runs Claude for a case and returns its exit code
+func execute(ctx context.Context, c *Case, s *skill.Skill, workspace string, opts Options, stdout io.Writer) (int, error) {
+	timeout, _ := c.timeout()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	prompt := c.Prompt
+	if prompt == "" {
+		prompt = s.Description
+	}
+	cfg := executor.Config{
+		Prompt:       prompt,
+		AllowedTools: s.AllowedTools,
+		WorkDir:      workspace,
+	}
+	if opts.SystemPrompt != nil {
+		cfg.SystemPrompt = opts.SystemPrompt(s)
+	}
+	if s.Model != "inherit" {
+		cfg.Model = s.Model
+	}
+	if schema, err := s.OutputSchemaJSON(); err != nil {
+		return 0, err
+	} else if schema != nil {
+		cfg.JSONSchema = string(schema)
+	}
+
+	run := opts.Execute
+	if run == nil {
+		run = func(ctx context.Context, cfg executor.Config, stdout, stderr io.Writer) error {
+			exec, err := executor.New(opts.Backend, cfg, stdout, stderr)
+			if err != nil {
+				return err
+			}
+			return exec.Execute(ctx)
+		}
+	}
+
+	var stderr bytes.Buffer
+	err := run(ctx, cfg, stdout, &stderr)
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0, nil
+	case ctx.Err() == context.DeadlineExceeded:
+		return 0, fmt.Errorf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode(), nil
+	default:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return 0, fmt.Errorf("execution failed: %w: %s", err, msg)
+		}
+		return 0, fmt.Errorf("execution failed: %w", err)
+	}
+}
+
+// recordingPath returns the case's recording and whether the case named it explicitly
+func (c *Case) recordingPath() (string, bool) {
+	if c.Replay != "" {
+		return c.resolve(c.Replay), true
+	}
+	return filepath.Join(filepath.Dir(c.Path), RecordingsDir, c.Name+".jsonl"), false
+}
+
+// writeRecording saves a live session for later replay
+func writeRecording(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to save recording: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save recording: %w", err)
+	}
+	return nil
+}
+
+// summarize runs a stream-json session through the stream parser
+func summarize(stream io.Reader, skillName, skillPath string) (*formatter.Summary, error) {
+	parser := formatter.NewStreamParser(skillName, skillPath, false)
+	events, errs := parser.Parse(stream)
+
+	summary := formatter.NewSummary()
+	for event := range events {
+		summary.Add(event)
+	}
+	if err := <-errs; err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	return summary, nil
+}
+
+// copyDir copies the regular files and directories under src into dst
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
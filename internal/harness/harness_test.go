@@ -0,0 +1,203 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/martinemde/skillet/internal/executor"
+	"github.com/martinemde/skillet/internal/skill"
+)
+
+const testSkill = "../../testdata/tested-skill/SKILL.md"
+
+func TestLoadCases(t *testing.T) {
+	cases, err := LoadCases("../../testdata/tested-skill")
+	if err != nil {
+		t.Fatalf("LoadCases error: %v", err)
+	}
+
+	if len(cases) != 2 {
+		t.Fatalf("Expected 2 cases, got %d", len(cases))
+	}
+	if cases[0].Name != "reads-before-writing" || cases[1].Name != "summarize" {
+		t.Errorf("Cases should be sorted by name, got %q, %q", cases[0].Name, cases[1].Name)
+	}
+	if got := cases[0].Expect.Tools.Called; len(got) != 1 || got[0] != "Read(notes.txt)" {
+		t.Errorf("A single string should load as a list, got %v", got)
+	}
+}
+
+func TestLoadCases_Missing(t *testing.T) {
+	if _, err := LoadCases(t.TempDir()); err == nil || !strings.Contains(err.Error(), "no tests found") {
+		t.Errorf("Expected no tests error, got: %v", err)
+	}
+}
+
+func TestLoadCase_InvalidTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.yaml")
+	if err := os.WriteFile(path, []byte("timeout: soon\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCase(path); err == nil || !strings.Contains(err.Error(), `invalid timeout "soon"`) {
+		t.Errorf("Expected invalid timeout error, got: %v", err)
+	}
+}
+
+func TestRun_Replay(t *testing.T) {
+	cases, err := LoadCases("../../testdata/tested-skill")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := Run(context.Background(), cases, Options{
+		SkillPath: testSkill,
+		Execute: func(context.Context, executor.Config, io.Writer, io.Writer) error {
+			t.Error("Replayed cases should not run Claude")
+			return nil
+		},
+	})
+
+	for _, r := range results {
+		if r.Mode != ModeReplay {
+			t.Errorf("%s: Mode = %q, want replay", r.Case.Name, r.Mode)
+		}
+		if !r.Passed() {
+			t.Errorf("%s should pass, got err=%v failures=%v", r.Case.Name, r.Err, r.Failures)
+		}
+	}
+}
+
+func TestRun_ReplayFailures(t *testing.T) {
+	c := &Case{
+		Name:      "strict",
+		Path:      "../../testdata/tested-skill/tests/strict.yaml",
+		Replay:    "recordings/summarize.jsonl",
+		Workspace: "fixtures/notes",
+		Expect: Expect{
+			Result: TextExpect{Contains: Strings{"CHANGELOG"}},
+			Files:  []FileExpect{{Path: "SUMMARY.md", TextExpect: TextExpect{NotContains: Strings{"Friday"}}}},
+			Tools:  ToolsExpect{Called: Strings{"Bash(go test:*)"}, NotCalled: Strings{"Write(*.md)"}},
+		},
+	}
+
+	results := Run(context.Background(), []*Case{c}, Options{SkillPath: testSkill})
+	r := results[0]
+	if r.Err != nil {
+		t.Fatalf("Run error: %v", r.Err)
+	}
+
+	want := []string{
+		`result does not contain "CHANGELOG"`,
+		`file SUMMARY.md contains "Friday"`,
+		"expected a call matching Bash(go test:*)",
+		"unexpected call matching Write(*.md): Write SUMMARY.md",
+	}
+	if strings.Join(r.Failures, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Failures = %q, want %q", r.Failures, want)
+	}
+}
+
+func TestMapPath(t *testing.T) {
+	workspace := filepath.Join(t.TempDir(), "ws")
+	recorded := "/home/dev/project"
+
+	tests := []struct {
+		file string
+		want string // Empty means not mapped
+	}{
+		{"SUMMARY.md", filepath.Join(workspace, "SUMMARY.md")},
+		{"docs/../notes.md", filepath.Join(workspace, "notes.md")},
+		{"../../.ssh/authorized_keys", ""},
+		{"docs/../../escape.md", ""},
+		{"..", ""},
+		{recorded + "/src/main.go", filepath.Join(workspace, "src", "main.go")},
+		{"/home/dev/.ssh/authorized_keys", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, ok := mapPath(tt.file, recorded, workspace)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("mapPath(%q) = %q, %v; want %q", tt.file, got, ok, tt.want)
+		}
+	}
+}
+
+func TestRun_Live(t *testing.T) {
+	dir := t.TempDir()
+	casePath := filepath.Join(dir, "tests", "live.yaml")
+	c := &Case{
+		Name:   "live",
+		Path:   casePath,
+		Prompt: "Summarize",
+		Expect: Expect{
+			Files: []FileExpect{{Path: "out.txt", TextExpect: TextExpect{Contains: Strings{"done"}}}},
+			Tools: ToolsExpect{Called: Strings{"Write(out.txt)"}},
+		},
+	}
+
+	var gotConfig executor.Config
+	fakeClaude := func(ctx context.Context, cfg executor.Config, stdout, stderr io.Writer) error {
+		gotConfig = cfg
+		out := filepath.Join(cfg.WorkDir, "out.txt")
+		if err := os.WriteFile(out, []byte("done\n"), 0o644); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(stdout, `{"type":"system","subtype":"init","session_id":"s","cwd":%q}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Write","input":{"file_path":%q,"content":"done\n"}}]}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"type":"result","subtype":"success","is_error":false,"result":"Done","session_id":"s"}
+`, cfg.WorkDir, out)
+		return err
+	}
+
+	results := Run(context.Background(), []*Case{c}, Options{
+		SkillPath:    testSkill,
+		SystemPrompt: func(s *skill.Skill) string { return "# " + s.Name },
+		Record:       true,
+		Execute:      fakeClaude,
+	})
+	r := results[0]
+
+	if !r.Passed() {
+		t.Fatalf("Live case should pass, got err=%v failures=%v", r.Err, r.Failures)
+	}
+	if r.Mode != ModeLive {
+		t.Errorf("Mode = %q, want live", r.Mode)
+	}
+	if gotConfig.Prompt != "Summarize" || gotConfig.SystemPrompt != "# tested-skill" || gotConfig.AllowedTools != "Read, Write" {
+		t.Errorf("Unexpected executor config: %+v", gotConfig)
+	}
+	if gotConfig.WorkDir == "" {
+		t.Error("Live runs should set a work dir")
+	}
+	if _, err := os.Stat(gotConfig.WorkDir); !os.IsNotExist(err) {
+		t.Error("Workspace should be removed after the run")
+	}
+
+	recording := filepath.Join(dir, "tests", RecordingsDir, "live.jsonl")
+	data, err := os.ReadFile(recording)
+	if err != nil || !strings.Contains(string(data), `"result":"Done"`) {
+		t.Errorf("Record should save the session to %s: %v", recording, err)
+	}
+}
+
+func TestRun_ExecutionError(t *testing.T) {
+	c := &Case{Name: "broken", Path: filepath.Join(t.TempDir(), "broken.yaml")}
+
+	results := Run(context.Background(), []*Case{c}, Options{
+		SkillPath: testSkill,
+		Execute: func(ctx context.Context, cfg executor.Config, stdout, stderr io.Writer) error {
+			_, _ = io.WriteString(stderr, "claude: not logged in\n")
+			return fmt.Errorf("boom")
+		},
+	})
+
+	if err := results[0].Err; err == nil || err.Error() != "execution failed: boom: claude: not logged in" {
+		t.Errorf("Expected execution error with stderr, got: %v", err)
+	}
+}
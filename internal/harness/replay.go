@@ -0,0 +1,116 @@
+package harness
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+// recordedWorkDir returns the cwd from a recording's system init message
+func recordedWorkDir(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var msg struct {
+			Type string `json:"type"`
+			Cwd  string `json:"cwd"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &msg) == nil && msg.Type == "system" && msg.Cwd != "" {
+			return msg.Cwd
+		}
+	}
+	return ""
+}
+
+// applyEdits replays the successful Write, Edit, and MultiEdit calls of a
+// recorded session into the workspace, so file assertions hold on replay.
+// Paths under the recorded cwd are mapped into the workspace; others are ignored.
+func applyEdits(tools []formatter.ToolOperation, recordedDir, workspace string) error {
+	for _, op := range tools {
+		if op.Status == "error" || op.Status == "pending" {
+			continue
+		}
+
+		file, _ := op.Input["file_path"].(string)
+		target, ok := mapPath(file, recordedDir, workspace)
+		if !ok {
+			continue
+		}
+
+		var err error
+		switch op.Name {
+		case "Write":
+			content, _ := op.Input["content"].(string)
+			if err = os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+				err = os.WriteFile(target, []byte(content), 0o644)
+			}
+		case "Edit":
+			err = editFile(target, []map[string]any{op.Input})
+		case "MultiEdit":
+			edits, _ := op.Input["edits"].([]any)
+			var list []map[string]any
+			for _, e := range edits {
+				if m, ok := e.(map[string]any); ok {
+					list = append(list, m)
+				}
+			}
+			err = editFile(target, list)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to replay %s of %s: %w", op.Name, file, err)
+		}
+	}
+	return nil
+}
+
+// mapPath maps a recorded file path into the workspace. Paths that would
+// land outside the workspace aren't mapped.
+func mapPath(file, recordedDir, workspace string) (string, bool) {
+	if file == "" {
+		return "", false
+	}
+	rel := filepath.Clean(file)
+	if filepath.IsAbs(file) {
+		if recordedDir == "" {
+			return "", false
+		}
+		var err error
+		if rel, err = filepath.Rel(recordedDir, file); err != nil {
+			return "", false
+		}
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(workspace, rel), true
+}
+
+// editFile applies old_string/new_string replacements to a file
+func editFile(path string, edits []map[string]any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+
+	for _, edit := range edits {
+		oldString, _ := edit["old_string"].(string)
+		newString, _ := edit["new_string"].(string)
+		if !strings.Contains(content, oldString) {
+			return fmt.Errorf("old_string not found")
+		}
+		if replaceAll, _ := edit["replace_all"].(bool); replaceAll {
+			content = strings.ReplaceAll(content, oldString, newString)
+		} else {
+			content = strings.Replace(content, oldString, newString, 1)
+		}
+	}
+
+	return os.WriteFile(path, []byte(content), 0o644)
+}
@@ -0,0 +1,124 @@
+package harness
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// WriteText writes a pass/fail line per case, its failures, and a summary
+func WriteText(w io.Writer, results []*Result, useColors bool) error {
+	passStyle := lipgloss.NewStyle().Bold(true)
+	failStyle := lipgloss.NewStyle().Bold(true)
+	detailStyle := lipgloss.NewStyle()
+
+	if useColors {
+		passStyle = passStyle.Foreground(lipgloss.Color("2"))     // Green
+		failStyle = failStyle.Foreground(lipgloss.Color("1"))     // Red
+		detailStyle = detailStyle.Foreground(lipgloss.Color("8")) // Dim
+	}
+
+	passed := 0
+	for _, r := range results {
+		mark := failStyle.Render("✗")
+		if r.Passed() {
+			mark = passStyle.Render("✓")
+			passed++
+		}
+		detail := detailStyle.Render(fmt.Sprintf("(%s, %.2fs)", r.Mode, r.Duration.Seconds()))
+		if _, err := fmt.Fprintf(w, "%s %s %s\n", mark, r.Case.Name, detail); err != nil {
+			return err
+		}
+
+		for _, msg := range r.messages() {
+			if _, err := fmt.Fprintf(w, "    %s\n", msg); err != nil {
+				return err
+			}
+		}
+		if r.Workspace != "" {
+			if _, err := fmt.Fprintf(w, "    %s\n", detailStyle.Render("workspace: "+r.Workspace)); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d passed, %d failed\n", passed, len(results)-passed)
+	return err
+}
+
+// messages returns the error or assertion failures for a result
+func (r *Result) messages() []string {
+	if r.Err != nil {
+		return []string{"error: " + r.Err.Error()}
+	}
+	return r.Failures
+}
+
+// junitSuites is the root element of a JUnit XML report
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as a JUnit XML report with one suite named after the skill
+func WriteJUnit(w io.Writer, skillName string, results []*Result) error {
+	suite := junitSuite{Name: skillName, Tests: len(results)}
+
+	var total float64
+	for _, r := range results {
+		seconds := r.Duration.Seconds()
+		total += seconds
+
+		tc := junitCase{
+			Name:      r.Case.Name,
+			Classname: skillName,
+			Time:      fmt.Sprintf("%.3f", seconds),
+		}
+		switch {
+		case r.Err != nil:
+			suite.Errors++
+			tc.Error = &junitMessage{Message: r.Err.Error(), Body: r.Err.Error()}
+		case len(r.Failures) > 0:
+			suite.Failures++
+			tc.Failure = &junitMessage{Message: r.Failures[0], Body: strings.Join(r.Failures, "\n")}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
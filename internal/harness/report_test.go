@@ -0,0 +1,56 @@
+package harness
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var reportResults = []*Result{
+	{Case: &Case{Name: "passes"}, Mode: ModeReplay, Duration: 1500 * time.Millisecond},
+	{Case: &Case{Name: "fails"}, Mode: ModeLive, Failures: []string{"result does not contain \"x\"", "file a.txt does not exist"}},
+	{Case: &Case{Name: "errors"}, Mode: ModeLive, Err: errors.New("execution failed")},
+}
+
+func TestWriteText(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteText(&out, reportResults, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"✓ passes (replay, 1.50s)",
+		"✗ fails (live, 0.00s)\n    result does not contain \"x\"\n    file a.txt does not exist",
+		"✗ errors (live, 0.00s)\n    error: execution failed",
+		"1 passed, 2 failed",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteJUnit(&out, "my-skill", reportResults); err != nil {
+		t.Fatal(err)
+	}
+
+	var report junitSuites
+	if err := xml.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("Report should be valid XML: %v\n%s", err, out.String())
+	}
+	suite := report.Suites[0]
+	if suite.Name != "my-skill" || suite.Tests != 3 || suite.Failures != 1 || suite.Errors != 1 {
+		t.Errorf("Unexpected suite: %+v", suite)
+	}
+	if suite.Cases[1].Failure == nil || suite.Cases[1].Failure.Body != "result does not contain \"x\"\nfile a.txt does not exist" {
+		t.Errorf("Failure should list every assertion, got %+v", suite.Cases[1].Failure)
+	}
+	if suite.Cases[2].Error == nil || suite.Cases[0].Failure != nil {
+		t.Errorf("Unexpected cases: %+v", suite.Cases)
+	}
+}
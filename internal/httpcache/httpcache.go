@@ -0,0 +1,253 @@
+// Package httpcache caches downloaded skills on disk, keyed by URL, and
+// revalidates them with ETag and Last-Modified so unchanged skills aren't
+// downloaded again.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/martinemde/skillet/internal/atomicfile"
+)
+
+// ErrNotCached is returned in offline mode for URLs that aren't in the cache
+var ErrNotCached = errors.New("not in cache")
+
+// Entry describes a cached response
+type Entry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Size         int64     `json:"size"`
+	FetchedAt    time.Time `json:"fetched_at"` // Last download or successful revalidation
+	UsedAt       time.Time `json:"used_at"`    // Last time the entry was read
+
+	key string
+}
+
+// Cache stores responses in a directory as <key>.json metadata and <key>.body content
+type Cache struct {
+	dir     string
+	offline bool
+	client  *http.Client
+}
+
+// New creates a cache in dir. Offline caches never use the network.
+func New(dir string, offline bool) *Cache {
+	return &Cache{dir: dir, offline: offline, client: http.DefaultClient}
+}
+
+// Dir returns the cache directory
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get returns a URL's body and Content-Type, reading at most limit+1 bytes
+// so callers can report content over the limit. Cached responses are
+// revalidated; if the network fails, the cached copy is used.
+func (c *Cache) Get(url string, limit int64) ([]byte, string, error) {
+	entry, body, cacheErr := c.load(url)
+	cached := cacheErr == nil
+
+	if c.offline {
+		if !cached {
+			return nil, "", fmt.Errorf("%s: %w (offline)", url, ErrNotCached)
+		}
+		return c.use(entry, body, limit)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download URL: %w", err)
+	}
+	if cached {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if cached {
+			return c.use(entry, body, limit)
+		}
+		return nil, "", fmt.Errorf("failed to download URL: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		entry.FetchedAt = time.Now()
+		return c.use(entry, body, limit)
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("failed to download URL: HTTP %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read URL content: %w", err)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if int64(len(content)) <= limit {
+		now := time.Now()
+		_ = c.store(&Entry{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			ContentType:  contentType,
+			Size:         int64(len(content)),
+			FetchedAt:    now,
+			UsedAt:       now,
+		}, content)
+	}
+	return content, contentType, nil
+}
+
+// use returns a cached body and records that it was used
+func (c *Cache) use(entry *Entry, body []byte, limit int64) ([]byte, string, error) {
+	entry.UsedAt = time.Now()
+	_ = c.writeMeta(entry)
+	if int64(len(body)) > limit+1 {
+		body = body[:limit+1]
+	}
+	return body, entry.ContentType, nil
+}
+
+// key returns the file name prefix for a URL
+func key(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// load reads a URL's cached entry and body
+func (c *Cache) load(url string) (*Entry, []byte, error) {
+	k := key(url)
+	entry, err := c.readMeta(k)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := os.ReadFile(filepath.Join(c.dir, k+".body"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry, body, nil
+}
+
+// readMeta reads an entry's metadata
+func (c *Cache) readMeta(k string) (*Entry, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, k+".json"))
+	if err != nil {
+		return nil, err
+	}
+	entry := &Entry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	entry.key = k
+	return entry, nil
+}
+
+// store writes an entry's body, then its metadata
+func (c *Cache) store(entry *Entry, body []byte) error {
+	entry.key = key(entry.URL)
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	if err := atomicfile.Write(filepath.Join(c.dir, entry.key+".body"), body); err != nil {
+		return err
+	}
+	return c.writeMeta(entry)
+}
+
+// writeMeta writes an entry's metadata
+func (c *Cache) writeMeta(entry *Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(filepath.Join(c.dir, entry.key+".json"), data)
+}
+
+// List returns the cached entries, most recently used first
+func (c *Cache) List() ([]Entry, error) {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	var entries []Entry
+	for _, f := range files {
+		k, ok := strings.CutSuffix(f.Name(), ".json")
+		if !ok || strings.HasPrefix(k, ".") {
+			continue
+		}
+		entry, err := c.readMeta(k)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].UsedAt.After(entries[j].UsedAt) })
+	return entries, nil
+}
+
+// Remove deletes a cached entry
+func (c *Cache) Remove(entry Entry) error {
+	k := entry.key
+	if k == "" {
+		k = key(entry.URL)
+	}
+	for _, name := range []string{k + ".json", k + ".body"} {
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Prune removes entries not used since before and returns how many were removed
+func (c *Cache) Prune(before time.Time) (int, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, entry := range entries {
+		if entry.UsedAt.Before(before) {
+			if err := c.Remove(entry); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// Clear removes every cached entry
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
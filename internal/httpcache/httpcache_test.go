@@ -0,0 +1,133 @@
+package httpcache
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGet_Revalidates(t *testing.T) {
+	body := "version 1"
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + body + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "text/markdown")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cache := New(t.TempDir(), false)
+	for i := 0; i < 2; i++ {
+		got, contentType, err := cache.Get(server.URL+"/SKILL.md", 1024)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if string(got) != "version 1" || contentType != "text/markdown" {
+			t.Errorf("Get() = %q, %q", got, contentType)
+		}
+	}
+	if downloads != 1 {
+		t.Errorf("downloaded %d times, want 1 with revalidation", downloads)
+	}
+
+	body = "version 2"
+	if got, _, _ := cache.Get(server.URL+"/SKILL.md", 1024); string(got) != "version 2" {
+		t.Errorf("Get() after change = %q, want version 2", got)
+	}
+}
+
+func TestGet_Offline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write([]byte("cached"))
+	}))
+	dir := t.TempDir()
+	url := server.URL + "/SKILL.md"
+
+	if _, _, err := New(dir, true).Get(url, 1024); !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline Get() of an uncached URL error = %v, want ErrNotCached", err)
+	}
+	if _, _, err := New(dir, false).Get(url, 1024); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	server.Close()
+
+	// Cached copies are used offline and when the server is unreachable
+	for _, offline := range []bool{true, false} {
+		got, _, err := New(dir, offline).Get(url, 1024)
+		if err != nil || string(got) != "cached" {
+			t.Errorf("Get(offline=%t) = %q, %v, want the cached copy", offline, got, err)
+		}
+	}
+}
+
+func TestGet_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+	cache := New(t.TempDir(), false)
+
+	if _, _, err := cache.Get(server.URL+"/missing", 1024); err == nil || err.Error() != "failed to download URL: HTTP 404" {
+		t.Errorf("Get() error = %v, want HTTP 404", err)
+	}
+
+	// Content over the limit is returned for the caller to reject, but not cached
+	got, _, err := cache.Get(server.URL+"/large", 4)
+	if err != nil || len(got) != 5 {
+		t.Errorf("Get() over the limit = %q, %v, want 5 bytes", got, err)
+	}
+	if entries, _ := cache.List(); len(entries) != 0 {
+		t.Errorf("List() = %+v, want nothing cached", entries)
+	}
+}
+
+func TestListPruneClear(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+	cache := New(t.TempDir(), false)
+
+	for _, p := range []string{"/a", "/b"} {
+		if _, _, err := cache.Get(server.URL+p, 1024); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := cache.List()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("List() = %+v, %v, want 2 entries", entries, err)
+	}
+	if entries[0].URL != server.URL+"/b" || entries[0].Size != 2 {
+		t.Errorf("List()[0] = %+v, want the most recently used entry first", entries[0])
+	}
+
+	if n, err := cache.Prune(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("Prune() of recent entries = %d, %v, want 0", n, err)
+	}
+	if n, err := cache.Prune(time.Now().Add(time.Hour)); err != nil || n != 2 {
+		t.Errorf("Prune() = %d, %v, want 2", n, err)
+	}
+
+	if _, _, err := cache.Get(server.URL+"/a", 1024); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if entries, _ := cache.List(); len(entries) != 0 {
+		t.Errorf("List() after Clear() = %+v", entries)
+	}
+}
@@ -0,0 +1,342 @@
+// Package index caches skill and command discovery on disk so completion,
+// --list, and name resolution don't re-walk and re-parse every source.
+//
+// Each source's listing is keyed by the modification times of the directories
+// that were walked, so adding or removing a resource re-walks only that
+// source. Frontmatter summaries are keyed by each file's mtime and size, so
+// editing a resource re-parses only that file.
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/atomicfile"
+	"github.com/martinemde/skillet/internal/command"
+	"github.com/martinemde/skillet/internal/commandpath"
+	"github.com/martinemde/skillet/internal/discovery"
+	"github.com/martinemde/skillet/internal/skill"
+	"github.com/martinemde/skillet/internal/skillpath"
+	"github.com/martinemde/skillet/internal/xdg"
+)
+
+const (
+	// FileName is the index file in skillet's cache directory
+	FileName = "index.json"
+
+	// version is bumped whenever the file format changes; older indexes are discarded
+	version = 1
+
+	// maxAge drops sources and summaries that haven't been used for this long
+	maxAge = 30 * 24 * time.Hour
+
+	// skillDepth is how deep SKILL.md files are found below a skills directory
+	// (namespace/name), so deeper directories don't affect the listing
+	skillDepth = 2
+)
+
+// Summary is what --list needs from a resource's frontmatter
+type Summary struct {
+	NotUserInvocable bool `json:"notUserInvocable,omitempty"`
+	Invalid          bool `json:"invalid,omitempty"` // Fails to parse; see skillet lint
+}
+
+// resource is a skill or command found in a source
+type resource struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"` // Without the source's namespace
+	Path      string `json:"path"`
+}
+
+// listing is the cached contents of one source directory
+type listing struct {
+	Dirs      map[string]time.Time `json:"dirs"` // Walked directory -> mtime
+	Resources []resource           `json:"resources"`
+	UsedAt    time.Time            `json:"usedAt"`
+}
+
+// summary is a cached Summary for one file
+type summary struct {
+	Summary
+	ModTime time.Time `json:"modTime"`
+	Size    int64     `json:"size"`
+	UsedAt  time.Time `json:"usedAt"`
+}
+
+// file is the on-disk index
+type file struct {
+	Version   int                 `json:"version"`
+	Listings  map[string]*listing `json:"listings"`  // "skills:" or "commands:" + source path
+	Summaries map[string]*summary `json:"summaries"` // Resource path
+}
+
+// Index is an on-disk cache of discovered skills and commands
+type Index struct {
+	path  string
+	data  file
+	dirty bool
+}
+
+// Default opens the index in skillet's cache directory
+func Default() (*Index, error) {
+	dir, err := xdg.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return Open(filepath.Join(dir, FileName)), nil
+}
+
+// Open loads the index at path. A missing, unreadable, or outdated index
+// starts empty, since it can always be rebuilt.
+func Open(path string) *Index {
+	ix := &Index{path: path}
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, &ix.data) != nil || ix.data.Version != version {
+			ix.data = file{}
+		}
+	}
+	ix.data.Version = version
+	if ix.data.Listings == nil {
+		ix.data.Listings = make(map[string]*listing)
+	}
+	if ix.data.Summaries == nil {
+		ix.data.Summaries = make(map[string]*summary)
+	}
+	return ix
+}
+
+// Save writes the index if anything changed, dropping entries unused for 30 days
+func (ix *Index) Save() error {
+	if !ix.dirty {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	for key, l := range ix.data.Listings {
+		if l.UsedAt.Before(cutoff) {
+			delete(ix.data.Listings, key)
+		}
+	}
+	for key, s := range ix.data.Summaries {
+		if s.UsedAt.Before(cutoff) {
+			delete(ix.data.Summaries, key)
+		}
+	}
+
+	data, err := json.Marshal(ix.data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0o755); err != nil {
+		return err
+	}
+	if err := atomicfile.Write(ix.path, data); err != nil {
+		return err
+	}
+	ix.dirty = false
+	return nil
+}
+
+// SkillFinder returns a discovery.Finder that serves skills from the index
+func (ix *Index) SkillFinder() discovery.Finder {
+	return &skillFinder{index: ix}
+}
+
+// CommandFinder returns a command.Finder that serves commands from the index
+func (ix *Index) CommandFinder() command.Finder {
+	return &commandFinder{index: ix}
+}
+
+// SkillSummary returns the frontmatter summary of the SKILL.md at path
+func (ix *Index) SkillSummary(path string) Summary {
+	return ix.summary(path, func() Summary {
+		// Missing required arguments are expected here; anything else is invalid
+		parsed, err := skill.Parse(path, "")
+		var argErr *arguments.Error
+		switch {
+		case err == nil:
+			return Summary{NotUserInvocable: !parsed.IsUserInvocable()}
+		case errors.As(err, &argErr):
+			return Summary{}
+		default:
+			return Summary{Invalid: true}
+		}
+	})
+}
+
+// CommandSummary returns the frontmatter summary of the command at path
+func (ix *Index) CommandSummary(path string) Summary {
+	return ix.summary(path, func() Summary {
+		var argErr *arguments.Error
+		if _, err := command.Parse(path, ""); err != nil && !errors.As(err, &argErr) {
+			return Summary{Invalid: true}
+		}
+		return Summary{}
+	})
+}
+
+// summary returns the cached summary for path, or parses it if the file changed
+func (ix *Index) summary(path string, parse func() Summary) Summary {
+	info, err := os.Stat(path)
+	if err != nil {
+		return parse()
+	}
+
+	now := time.Now()
+	if s, ok := ix.data.Summaries[path]; ok && s.ModTime.Equal(info.ModTime()) && s.Size == info.Size() {
+		ix.touch(&s.UsedAt, now)
+		return s.Summary
+	}
+
+	s := &summary{Summary: parse(), ModTime: info.ModTime(), Size: info.Size(), UsedAt: now}
+	ix.data.Summaries[path] = s
+	ix.dirty = true
+	return s.Summary
+}
+
+// find returns the resources in dir, re-walking it only if a directory changed
+func (ix *Index) find(kind, dir string, maxDepth int, walk func() []resource) []resource {
+	key := kind + ":" + dir
+	now := time.Now()
+	if l, ok := ix.data.Listings[key]; ok && unchanged(l.Dirs) {
+		ix.touch(&l.UsedAt, now)
+		return l.Resources
+	}
+
+	// Record mtimes before walking, so changes made during the walk are seen next time
+	dirs := dirTimes(dir, maxDepth)
+	resources := walk()
+	if len(dirs) == 0 {
+		// Missing sources are cheap to check and aren't worth caching
+		if _, ok := ix.data.Listings[key]; ok {
+			delete(ix.data.Listings, key)
+			ix.dirty = true
+		}
+		return resources
+	}
+	ix.data.Listings[key] = &listing{Dirs: dirs, Resources: resources, UsedAt: now}
+	ix.dirty = true
+	return resources
+}
+
+// touch records a use, marking the index dirty at most once a day so
+// unchanged lookups don't rewrite it
+func (ix *Index) touch(usedAt *time.Time, now time.Time) {
+	if now.Sub(*usedAt) > 24*time.Hour {
+		*usedAt = now
+		ix.dirty = true
+	}
+}
+
+// dirTimes returns the mtime of root and the directories below it, up to
+// maxDepth levels deep (negative for no limit)
+func dirTimes(root string, maxDepth int) map[string]time.Time {
+	dirs := make(map[string]time.Time)
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		dirs[path] = info.ModTime()
+
+		if maxDepth >= 0 && path != root {
+			rel, _ := filepath.Rel(root, path)
+			if strings.Count(rel, string(filepath.Separator))+1 >= maxDepth {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return dirs
+}
+
+// unchanged reports whether every directory still has its recorded mtime
+func unchanged(dirs map[string]time.Time) bool {
+	for dir, modTime := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			return false
+		}
+	}
+	return len(dirs) > 0
+}
+
+// withNamespace prefixes namespace with a source's namespace, if it has one
+func withNamespace(sourceNamespace, namespace string) string {
+	switch {
+	case sourceNamespace == "":
+		return namespace
+	case namespace == "":
+		return sourceNamespace
+	default:
+		return sourceNamespace + ":" + namespace
+	}
+}
+
+// skillFinder finds skills through the index
+type skillFinder struct {
+	index *Index
+}
+
+// Find returns the skills in source, walking it with discovery.DirectoryFinder when it changed
+func (f *skillFinder) Find(source skillpath.Source) ([]discovery.Skill, error) {
+	resources := f.index.find("skills", source.Path, skillDepth, func() []resource {
+		bare := source
+		bare.Namespace = ""
+		found, _ := (&discovery.DirectoryFinder{}).Find(bare)
+		resources := make([]resource, len(found))
+		for i, s := range found {
+			resources[i] = resource{Name: s.Name, Namespace: s.Namespace, Path: s.Path}
+		}
+		return resources
+	})
+
+	skills := make([]discovery.Skill, len(resources))
+	for i, r := range resources {
+		skills[i] = discovery.Skill{
+			Name:      r.Name,
+			Path:      r.Path,
+			Source:    source,
+			Namespace: withNamespace(source.Namespace, r.Namespace),
+		}
+	}
+	return skills, nil
+}
+
+// commandFinder finds commands through the index
+type commandFinder struct {
+	index *Index
+}
+
+// Find returns the commands in source, walking it with command.DirectoryFinder when it changed
+func (f *commandFinder) Find(source commandpath.Source) ([]command.DiscoveredCommand, error) {
+	resources := f.index.find("commands", source.Path, -1, func() []resource {
+		bare := source
+		bare.Namespace = ""
+		found, _ := (&command.DirectoryFinder{}).Find(bare)
+		resources := make([]resource, len(found))
+		for i, c := range found {
+			resources[i] = resource{Name: c.Name, Namespace: c.Namespace, Path: c.Path}
+		}
+		return resources
+	})
+
+	commands := make([]command.DiscoveredCommand, len(resources))
+	for i, r := range resources {
+		commands[i] = command.DiscoveredCommand{
+			Name:      r.Name,
+			Path:      r.Path,
+			Source:    source,
+			Namespace: withNamespace(source.Namespace, r.Namespace),
+		}
+	}
+	return commands, nil
+}
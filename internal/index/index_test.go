@@ -0,0 +1,192 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/martinemde/skillet/internal/command"
+	"github.com/martinemde/skillet/internal/commandpath"
+	"github.com/martinemde/skillet/internal/discovery"
+	"github.com/martinemde/skillet/internal/skillpath"
+)
+
+// writeFile creates path and its parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// age sets the mtime of root and every directory below it to an hour ago,
+// so later changes get a different mtime even on coarse filesystem clocks
+func age(t *testing.T, root string) time.Time {
+	t.Helper()
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		return os.Chtimes(path, old, old)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return old
+}
+
+func skillNames(t *testing.T, ix *Index, source skillpath.Source) []string {
+	t.Helper()
+	skills, err := ix.SkillFinder().Find(source)
+	if err != nil {
+		t.Fatalf("Find error: %v", err)
+	}
+	var names []string
+	for _, s := range skills {
+		names = append(names, s.QualifiedName())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSkillFinder_MatchesDirectoryFinder(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "review", "SKILL.md"), "x")
+	writeFile(t, filepath.Join(dir, "frontend", "test", "SKILL.md"), "x")
+	writeFile(t, filepath.Join(dir, "frontend", "test", "scripts", "deep", "SKILL.md"), "x")
+	source := skillpath.Source{Path: dir, Name: "plugin", Namespace: "acme"}
+
+	want, _ := discovery.New(skillpath.NewWithSources([]skillpath.Source{source})).Discover()
+	got, err := discovery.NewWithFinder(skillpath.NewWithSources([]skillpath.Source{source}), Open(filepath.Join(t.TempDir(), FileName)).SkillFinder()).Discover()
+	if err != nil {
+		t.Fatalf("Discover error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() through the index = %+v, want %+v", got, want)
+	}
+}
+
+func TestCommandFinder_MatchesDirectoryFinder(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "deploy.md"), "x")
+	writeFile(t, filepath.Join(dir, "frontend", "build.md"), "x")
+	source := commandpath.Source{Path: dir, Name: "project"}
+
+	want, _ := command.NewDiscoverer(commandpath.NewWithSources([]commandpath.Source{source})).Discover()
+	got, err := command.NewDiscovererWithFinder(commandpath.NewWithSources([]commandpath.Source{source}), Open(filepath.Join(t.TempDir(), FileName)).CommandFinder()).Discover()
+	if err != nil {
+		t.Fatalf("Discover error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() through the index = %+v, want %+v", got, want)
+	}
+}
+
+func TestSkillFinder_Invalidation(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), FileName)
+	writeFile(t, filepath.Join(dir, "review", "SKILL.md"), "x")
+	old := age(t, dir)
+	source := skillpath.Source{Path: dir, Name: "project"}
+
+	ix := Open(indexPath)
+	if got := skillNames(t, ix, source); !reflect.DeepEqual(got, []string{"review"}) {
+		t.Fatalf("names = %v, want [review]", got)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	// A change hidden from the directory mtimes is served from the index
+	writeFile(t, filepath.Join(dir, "hidden", "SKILL.md"), "x")
+	if err := os.Chtimes(dir, old, old); err != nil {
+		t.Fatal(err)
+	}
+	ix = Open(indexPath)
+	if got := skillNames(t, ix, source); !reflect.DeepEqual(got, []string{"review"}) {
+		t.Errorf("names = %v, want the indexed [review]", got)
+	}
+
+	// Adding a namespaced skill changes its directory's mtime
+	writeFile(t, filepath.Join(dir, "frontend", "test", "SKILL.md"), "x")
+	if got, want := skillNames(t, ix, source), []string{"frontend:test", "hidden", "review"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %v, want %v", got, want)
+	}
+
+	// Removing a skill changes its parent's mtime
+	if err := os.RemoveAll(filepath.Join(dir, "review")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := skillNames(t, ix, source), []string{"frontend:test", "hidden"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %v, want %v", got, want)
+	}
+}
+
+func TestSkillSummary(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "review", "SKILL.md")
+	writeFile(t, path, "---\nname: [unclosed\n---\n")
+
+	ix := Open(filepath.Join(t.TempDir(), FileName))
+	if got := ix.SkillSummary(path); !got.Invalid {
+		t.Errorf("SkillSummary(invalid) = %+v, want Invalid", got)
+	}
+
+	writeFile(t, path, "---\nname: review\ndescription: Reviews code\nuser-invocable: false\n---\nReview the diff.\n")
+	if got := ix.SkillSummary(path); got != (Summary{NotUserInvocable: true}) {
+		t.Errorf("SkillSummary(edited) = %+v, want NotUserInvocable", got)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	// Reloaded summaries are used while the file is unchanged
+	ix = Open(ix.path)
+	ix.data.Summaries[path].NotUserInvocable = false
+	if got := ix.SkillSummary(path); got != (Summary{}) {
+		t.Errorf("SkillSummary(cached) = %+v, want the cached summary", got)
+	}
+}
+
+func TestCommandSummary(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deploy.md")
+	writeFile(t, path, "---\ndescription: Deploy\nargument-hint: <env>\n---\nDeploy to $1.\n")
+
+	ix := Open(filepath.Join(t.TempDir(), FileName))
+	if got := ix.CommandSummary(path); got.Invalid {
+		t.Errorf("CommandSummary(valid) = %+v, want valid", got)
+	}
+}
+
+func TestOpen_DiscardsUnreadableIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	for _, content := range []string{"not json", `{"version": 999, "listings": {"skills:/x": {}}}`} {
+		writeFile(t, path, content)
+		ix := Open(path)
+		if len(ix.data.Listings) != 0 || ix.data.Version != version {
+			t.Errorf("Open(%q) = %+v, want an empty index", content, ix.data)
+		}
+	}
+}
+
+func TestSave_Unchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", FileName)
+	source := skillpath.Source{Path: filepath.Join(t.TempDir(), "missing")}
+
+	ix := Open(path)
+	skillNames(t, ix, source)
+	if err := ix.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Save wrote an index with nothing in it: %v", err)
+	}
+}
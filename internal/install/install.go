@@ -0,0 +1,438 @@
+// Package install copies skills from local paths, URLs, and git sources into
+// a project's or the user's .claude/skills, and records where each came from
+// so they can be checked for updates.
+package install
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/martinemde/skillet/internal/frontmatter"
+	"github.com/martinemde/skillet/internal/resolver"
+	"github.com/martinemde/skillet/internal/skillpath"
+	"github.com/martinemde/skillet/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile records installed skills in the skills directory
+const ManifestFile = "installed_skills.json"
+
+// Manifest is the format of installed_skills.json
+type Manifest struct {
+	Version int                   `json:"version"`
+	Skills  map[string]*Installed `json:"skills"` // Keyed by [namespace:]name
+
+	path string
+}
+
+// Installed records where an installed skill came from
+type Installed struct {
+	Source      string    `json:"source"`           // Absolute path, URL, or git source
+	Commit      string    `json:"commit,omitempty"` // Commit a git source resolved to
+	SHA256      string    `json:"sha256"`           // Hash of the source content when installed
+	FilesSHA256 string    `json:"filesSha256"`      // Hash of the installed directory, to spot local edits
+	InstalledAt time.Time `json:"installedAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Version returns the short commit for git sources or the short content hash
+func (i *Installed) Version() string {
+	if i.Commit != "" {
+		return shortHash(i.Commit)
+	}
+	return "sha256:" + shortHash(i.SHA256)
+}
+
+// Installer installs skills into one skills directory
+type Installer struct {
+	// Dir is the skills directory, e.g. .claude/skills
+	Dir string
+	// Resolver resolves sources; its Offline and Lock settings apply
+	Resolver *resolver.Resolver
+}
+
+// New creates an Installer for a scope (skillpath.ScopeProject or ScopeUser)
+func New(scope string, r *resolver.Resolver) (*Installer, error) {
+	dir, err := skillpath.ScopeDir(scope, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Installer{Dir: dir, Resolver: r}, nil
+}
+
+// Result describes an installed skill
+type Result struct {
+	Name      string // [namespace:]name
+	Path      string // Installed SKILL.md
+	Installed *Installed
+}
+
+// LoadManifest reads the skills directory's manifest. A missing file is empty.
+func (in *Installer) LoadManifest() (*Manifest, error) {
+	m := &Manifest{Version: 1, Skills: map[string]*Installed{}, path: filepath.Join(in.Dir, ManifestFile)}
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", ManifestFile, err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", m.path, err)
+	}
+	if m.Skills == nil {
+		m.Skills = map[string]*Installed{}
+	}
+	return m, nil
+}
+
+// Save writes the manifest
+func (m *Manifest) Save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", ManifestFile, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ManifestFile, err)
+	}
+	if err := os.WriteFile(m.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ManifestFile, err)
+	}
+	return nil
+}
+
+// Names returns the installed skill names in sorted order
+func (m *Manifest) Names() []string {
+	names := make([]string, 0, len(m.Skills))
+	for name := range m.Skills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Install copies the skill from source into the skills directory as name,
+// or as the skill's own name when name is empty. An existing skill is only
+// replaced with force.
+func (in *Installer) Install(source, name string, force bool) (*Result, error) {
+	m, err := in.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := in.Resolver.Resolve(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", source, err)
+	}
+	defer result.Cleanup()
+	if result.Type != resolver.ResourceTypeSkill {
+		return nil, fmt.Errorf("%s is a command, only skills can be installed", source)
+	}
+
+	if name == "" {
+		name, err = skillName(result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	dest, err := in.skillDir(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dest); err == nil && !force {
+		return nil, fmt.Errorf("skill %s already exists at %s (use --force to replace it)", name, dest)
+	}
+
+	// Record local sources by absolute path so updates work from anywhere
+	origin := source
+	if !resolver.IsRemote(source) {
+		origin = filepath.Dir(result.Path)
+	}
+	return in.write(m, name, origin, dest, result)
+}
+
+// write copies a resolved skill to dest and records it in the manifest
+func (in *Installer) write(m *Manifest, name, origin, dest string, result *resolver.ResolveResult) (*Result, error) {
+	sum, err := result.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	// A single downloaded SKILL.md sits alone in a temp directory
+	src := filepath.Dir(result.Path)
+	if result.BaseURL != "" {
+		src = result.Path
+	}
+	if err := replaceDir(dest, src); err != nil {
+		return nil, err
+	}
+	filesSum, err := resolver.HashTree(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	entry := &Installed{
+		Source:      origin,
+		Commit:      result.Commit,
+		SHA256:      sum,
+		FilesSHA256: filesSum,
+		InstalledAt: now,
+		UpdatedAt:   now,
+	}
+	if previous, ok := m.Skills[name]; ok && previous.Source == origin {
+		entry.InstalledAt = previous.InstalledAt
+	}
+	m.Skills[name] = entry
+	if err := m.Save(); err != nil {
+		return nil, err
+	}
+
+	return &Result{Name: name, Path: filepath.Join(dest, skillpath.SkillFile), Installed: entry}, nil
+}
+
+// Uninstall removes a skill installed by skillet
+func (in *Installer) Uninstall(name string) error {
+	m, err := in.LoadManifest()
+	if err != nil {
+		return err
+	}
+	if _, ok := m.Skills[name]; !ok {
+		return fmt.Errorf("%s is not installed in %s", name, in.Dir)
+	}
+	dest, err := in.skillDir(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dest); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", dest, err)
+	}
+	removeEmptyParents(filepath.Dir(dest), in.Dir)
+
+	delete(m.Skills, name)
+	return m.Save()
+}
+
+// Status compares an installed skill with its source
+type Status struct {
+	Name      string
+	Installed *Installed
+	Latest    string // Version available from the source
+	Outdated  bool   // The source has changed since installing
+	Modified  bool   // The installed files were edited
+	Err       error  // Set when the source couldn't be checked
+}
+
+// Check compares each installed skill, or only the named ones, with its source
+func (in *Installer) Check(names ...string) ([]Status, error) {
+	m, err := in.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		names = m.Names()
+	}
+
+	statuses := make([]Status, 0, len(names))
+	for _, name := range names {
+		entry, ok := m.Skills[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not installed in %s", name, in.Dir)
+		}
+		status := Status{Name: name, Installed: entry}
+
+		if dest, err := in.skillDir(name); err == nil {
+			sum, err := resolver.HashTree(dest)
+			status.Modified = err != nil || sum != entry.FilesSHA256
+		}
+
+		result, err := in.Resolver.Resolve(entry.Source)
+		if err != nil {
+			status.Err = err
+		} else {
+			latest := &Installed{Commit: result.Commit}
+			latest.SHA256, status.Err = result.Hash()
+			result.Cleanup()
+			status.Latest = latest.Version()
+			status.Outdated = status.Err == nil && (latest.Commit != entry.Commit || latest.SHA256 != entry.SHA256)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Update reinstalls a skill from its source. Local edits are only
+// overwritten with force.
+func (in *Installer) Update(name string, force bool) (*Result, error) {
+	m, err := in.LoadManifest()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := m.Skills[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not installed in %s", name, in.Dir)
+	}
+	dest, err := in.skillDir(name)
+	if err != nil {
+		return nil, err
+	}
+	if !force {
+		if sum, err := resolver.HashTree(dest); err == nil && sum != entry.FilesSHA256 {
+			return nil, fmt.Errorf("%s has local changes (use --force to overwrite them)", name)
+		}
+	}
+
+	result, err := in.Resolver.Resolve(entry.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", entry.Source, err)
+	}
+	defer result.Cleanup()
+	return in.write(m, name, entry.Source, dest, result)
+}
+
+// skillDir returns the directory for a [namespace:]name in the skills directory
+func (in *Installer) skillDir(name string) (string, error) {
+	parts := strings.Split(name, ":")
+	for i, part := range parts {
+		kind := "namespace"
+		if i == len(parts)-1 {
+			kind = "skill"
+		}
+		if err := validation.ValidateName(part, kind); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(append([]string{in.Dir}, parts...)...), nil
+}
+
+// skillName returns the frontmatter name of a resolved skill, falling back
+// to its directory or URL path
+func skillName(result *resolver.ResolveResult) (string, error) {
+	data, err := os.ReadFile(result.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read skill: %w", err)
+	}
+	parsed, err := frontmatter.Parse(string(data), false)
+	if err == nil && parsed.HasFrontmatter {
+		var fm struct {
+			Name string `yaml:"name"`
+		}
+		if yaml.Unmarshal([]byte(parsed.FrontmatterYAML), &fm) == nil && fm.Name != "" {
+			return fm.Name, nil
+		}
+	}
+
+	if result.BaseURL != "" {
+		return path.Base(result.BaseURL), nil
+	}
+	return filepath.Base(filepath.Dir(result.Path)), nil
+}
+
+// replaceDir replaces dest with a copy of src, a directory or a single
+// SKILL.md. The copy is made beside dest so a failure leaves dest intact.
+func replaceDir(dest, src string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to install skill: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dest), ".install-*")
+	if err != nil {
+		return fmt.Errorf("failed to install skill: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if info, err := os.Stat(src); err == nil && !info.IsDir() {
+		err = copyFile(filepath.Join(tmp, skillpath.SkillFile), src, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to install skill: %w", err)
+		}
+	} else if err := copyTree(tmp, src); err != nil {
+		return fmt.Errorf("failed to install skill: %w", err)
+	}
+
+	if err := os.RemoveAll(dest); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", dest, err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return fmt.Errorf("failed to install skill: %w", err)
+	}
+	return nil
+}
+
+// copyTree copies the directories and regular files under src into dest
+func copyTree(dest, src string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		switch {
+		case d.IsDir():
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0o755)
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			perm := os.FileMode(0o644)
+			if info.Mode()&0o111 != 0 {
+				perm = 0o755
+			}
+			return copyFile(target, p, perm)
+		}
+		return nil
+	})
+}
+
+// copyFile copies one file with the given permissions
+func copyFile(dest, src string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// removeEmptyParents removes empty namespace directories up to root
+func removeEmptyParents(dir, root string) {
+	for dir != root && strings.HasPrefix(dir, root) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// shortHash abbreviates a commit or hash for display
+func shortHash(s string) string {
+	if len(s) > 12 {
+		return s[:12]
+	}
+	return s
+}
@@ -0,0 +1,160 @@
+package install
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/martinemde/skillet/internal/resolver"
+)
+
+const reviewSkill = "---\nname: review\ndescription: Review code\n---\nRun {baseDir}/scripts/run.sh\n"
+
+// writeSkill writes a skill directory with a script
+func writeSkill(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("#!/bin/sh\necho ok\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newInstaller(t *testing.T) *Installer {
+	t.Helper()
+	return &Installer{Dir: filepath.Join(t.TempDir(), ".claude", "skills"), Resolver: resolver.NewWithPaths(nil, nil)}
+}
+
+func TestInstall_Local(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "review-src")
+	writeSkill(t, source, reviewSkill)
+	in := newInstaller(t)
+
+	result, err := in.Install(source, "", false)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if result.Name != "review" || result.Path != filepath.Join(in.Dir, "review", "SKILL.md") {
+		t.Errorf("Install() = %+v, want review named from its frontmatter", result)
+	}
+	info, err := os.Stat(filepath.Join(in.Dir, "review", "scripts", "run.sh"))
+	if err != nil || info.Mode()&0o111 == 0 {
+		t.Errorf("script should be copied and executable: %v", err)
+	}
+
+	m, err := in.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := m.Skills["review"]
+	if entry == nil || entry.Source != source || entry.SHA256 == "" || entry.FilesSHA256 == "" {
+		t.Errorf("manifest entry = %+v, want the absolute source and hashes", entry)
+	}
+
+	if _, err := in.Install(source, "", false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second Install() error = %v, want already exists", err)
+	}
+	if _, err := in.Install(source, "", true); err != nil {
+		t.Errorf("Install() with force error = %v", err)
+	}
+}
+
+func TestCheckAndUpdate(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "review")
+	writeSkill(t, source, reviewSkill)
+	in := newInstaller(t)
+	if _, err := in.Install(source, "tools:review", false); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	statuses, err := in.Check()
+	if err != nil || len(statuses) != 1 || statuses[0].Outdated || statuses[0].Modified || statuses[0].Err != nil {
+		t.Fatalf("Check() = %+v, %v, want one current skill", statuses, err)
+	}
+
+	writeSkill(t, source, reviewSkill+"Then summarize.\n")
+	statuses, _ = in.Check("tools:review")
+	if !statuses[0].Outdated || statuses[0].Latest == statuses[0].Installed.Version() {
+		t.Errorf("Check() after the source changed = %+v, want outdated", statuses[0])
+	}
+
+	if _, err := in.Update("tools:review", false); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(in.Dir, "tools", "review", "SKILL.md"))
+	if !strings.Contains(string(content), "Then summarize.") {
+		t.Errorf("Update() should install the new content, got: %s", content)
+	}
+	if statuses, _ := in.Check(); statuses[0].Outdated {
+		t.Errorf("Check() after Update() = %+v, want current", statuses[0])
+	}
+
+	// Local edits are reported and protected
+	if err := os.WriteFile(filepath.Join(in.Dir, "tools", "review", "SKILL.md"), []byte(reviewSkill+"Edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if statuses, _ := in.Check(); !statuses[0].Modified {
+		t.Errorf("Check() after a local edit = %+v, want modified", statuses[0])
+	}
+	if _, err := in.Update("tools:review", false); err == nil || !strings.Contains(err.Error(), "local changes") {
+		t.Errorf("Update() of an edited skill error = %v, want local changes", err)
+	}
+	if _, err := in.Update("tools:review", true); err != nil {
+		t.Errorf("Update() with force error = %v", err)
+	}
+}
+
+func TestUninstall(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "review")
+	writeSkill(t, source, reviewSkill)
+	in := newInstaller(t)
+	if _, err := in.Install(source, "tools:review", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := in.Uninstall("tools:review"); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(in.Dir, "tools")); !os.IsNotExist(err) {
+		t.Error("Uninstall() should remove the skill and its empty namespace")
+	}
+	if m, _ := in.LoadManifest(); len(m.Skills) != 0 {
+		t.Errorf("manifest after Uninstall() = %+v", m.Skills)
+	}
+	if err := in.Uninstall("review"); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("Uninstall() of an unknown skill error = %v", err)
+	}
+}
+
+func TestInstall_URL(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/skills/review/SKILL.md" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(reviewSkill))
+	}))
+	defer server.Close()
+	in := newInstaller(t)
+
+	source := server.URL + "/skills/review/SKILL.md"
+	result, err := in.Install(source, "", false)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(result.Path))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("a single-file URL should install only SKILL.md, got %v, %v", entries, err)
+	}
+	if result.Installed.Source != source {
+		t.Errorf("Source = %s, want the URL", result.Installed.Source)
+	}
+}
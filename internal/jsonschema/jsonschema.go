@@ -0,0 +1,419 @@
+// Package jsonschema validates JSON values against a practical subset of JSON Schema.
+// It supports the keywords skills use to describe structured output: type, enum,
+// const, properties, required, additionalProperties, items, length and range
+// limits, pattern, allOf/anyOf/oneOf, and local $ref into $defs or definitions.
+// Unknown keywords are ignored, matching how validators treat annotations.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is a compiled JSON Schema document
+type Schema struct {
+	root map[string]any
+	raw  []byte
+}
+
+// ValidationError describes why a value did not match the schema
+type ValidationError struct {
+	Path    string // JSON pointer-like path to the offending value ("" for the root)
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Compile parses a JSON Schema document
+func Compile(data []byte) (*Schema, error) {
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	s := &Schema{root: root, raw: data}
+	if err := s.checkRefCycles(root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return s, nil
+}
+
+// checkRefCycles rejects $ref chains that lead back to themselves, like
+// {"$ref": "#"}, which could never validate anything
+func (s *Schema) checkRefCycles(node any) error {
+	switch n := node.(type) {
+	case map[string]any:
+		seen := make(map[string]bool)
+		for schema := n; ; {
+			ref, ok := schema["$ref"].(string)
+			if !ok {
+				break
+			}
+			if seen[ref] {
+				return fmt.Errorf("circular $ref %q", ref)
+			}
+			seen[ref] = true
+			target, err := s.resolveRef(ref)
+			if err != nil {
+				break // Reported when validating
+			}
+			schema = target
+		}
+		for _, child := range n {
+			if err := s.checkRefCycles(child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range n {
+			if err := s.checkRefCycles(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// String returns the schema as compact JSON, suitable for passing on the command line
+func (s *Schema) String() string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, s.raw); err != nil {
+		return string(s.raw)
+	}
+	return buf.String()
+}
+
+// ValidateJSON decodes data and validates it against the schema
+func (s *Schema) ValidateJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("not valid JSON: %v", err)}
+	}
+	return s.Validate(value)
+}
+
+// Validate checks a decoded JSON value (as produced by encoding/json) against the schema
+func (s *Schema) Validate(value any) error {
+	return s.validate(s.root, value, "", nil)
+}
+
+// validate checks value against schema. refs holds the $refs being followed
+// for the value at path, so a cycle through allOf, anyOf or oneOf that never
+// reaches a nested value is reported instead of recursing forever.
+func (s *Schema) validate(schema map[string]any, value any, path string, refs map[string]bool) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		if refs[ref] {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("circular $ref %q", ref)}
+		}
+		target, err := s.resolveRef(ref)
+		if err != nil {
+			return &ValidationError{Path: path, Message: err.Error()}
+		}
+		if refs == nil {
+			refs = make(map[string]bool)
+		}
+		refs[ref] = true
+		defer delete(refs, ref)
+		return s.validate(target, value, path, refs)
+	}
+
+	if t, ok := schema["type"]; ok {
+		if err := checkType(t, value, path); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("value %s is not one of %s", encode(value), encode(enum))}
+		}
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("value %s must equal %s", encode(value), encode(c))}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if err := s.validateObject(schema, v, path); err != nil {
+			return err
+		}
+	case []any:
+		if err := s.validateArray(schema, v, path); err != nil {
+			return err
+		}
+	case string:
+		if err := validateString(schema, v, path); err != nil {
+			return err
+		}
+	case float64:
+		if err := validateNumber(schema, v, path); err != nil {
+			return err
+		}
+	}
+
+	return s.validateCombinators(schema, value, path, refs)
+}
+
+func (s *Schema) validateObject(schema map[string]any, obj map[string]any, path string) error {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, exists := obj[name]; !exists {
+				return &ValidationError{Path: path, Message: fmt.Sprintf("missing required property %q", name)}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+
+	// Validate in sorted order so errors are deterministic
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "/" + key
+		if propSchema, ok := properties[key].(map[string]any); ok {
+			if err := s.validate(propSchema, obj[key], childPath, nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return &ValidationError{Path: path, Message: fmt.Sprintf("unexpected property %q", key)}
+			}
+		case map[string]any:
+			if err := s.validate(additional, obj[key], childPath, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateArray(schema map[string]any, arr []any, path string) error {
+	if min, ok := number(schema["minItems"]); ok && float64(len(arr)) < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("expected at least %v items, got %d", min, len(arr))}
+	}
+	if max, ok := number(schema["maxItems"]); ok && float64(len(arr)) > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("expected at most %v items, got %d", max, len(arr))}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range arr {
+			if err := s.validate(items, item, fmt.Sprintf("%s/%d", path, i), nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateString(schema map[string]any, str string, path string) error {
+	length := float64(len([]rune(str)))
+	if min, ok := number(schema["minLength"]); ok && length < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("string shorter than %v characters", min)}
+	}
+	if max, ok := number(schema["maxLength"]); ok && length > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("string longer than %v characters", max)}
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("invalid pattern %q: %v", pattern, err)}
+		}
+		if !re.MatchString(str) {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("string %q does not match pattern %q", str, pattern)}
+		}
+	}
+	return nil
+}
+
+func validateNumber(schema map[string]any, n float64, path string) error {
+	if min, ok := number(schema["minimum"]); ok && n < min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%v is less than minimum %v", n, min)}
+	}
+	if max, ok := number(schema["maximum"]); ok && n > max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%v is greater than maximum %v", n, max)}
+	}
+	if min, ok := number(schema["exclusiveMinimum"]); ok && n <= min {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%v must be greater than %v", n, min)}
+	}
+	if max, ok := number(schema["exclusiveMaximum"]); ok && n >= max {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%v must be less than %v", n, max)}
+	}
+	return nil
+}
+
+func (s *Schema) validateCombinators(schema map[string]any, value any, path string, refs map[string]bool) error {
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, sub := range all {
+			if subSchema, ok := sub.(map[string]any); ok {
+				if err := s.validate(subSchema, value, path, refs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		if s.countMatches(anyOf, value, path, refs) == 0 {
+			return &ValidationError{Path: path, Message: "value does not match any schema in anyOf"}
+		}
+	}
+
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		if n := s.countMatches(oneOf, value, path, refs); n != 1 {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("value must match exactly one schema in oneOf, matched %d", n)}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) countMatches(schemas []any, value any, path string, refs map[string]bool) int {
+	matches := 0
+	for _, sub := range schemas {
+		if subSchema, ok := sub.(map[string]any); ok {
+			if s.validate(subSchema, value, path, refs) == nil {
+				matches++
+			}
+		}
+	}
+	return matches
+}
+
+// resolveRef resolves a local reference like "#/$defs/item" or "#/definitions/item"
+func (s *Schema) resolveRef(ref string) (map[string]any, error) {
+	if ref == "#" {
+		return s.root, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q: only local references are supported", ref)
+	}
+
+	var node any = s.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		node, ok = m[part]
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+
+	target, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("$ref %q does not point to a schema", ref)
+	}
+	return target, nil
+}
+
+// checkType validates the "type" keyword, which may be a string or a list of strings
+func checkType(t any, value any, path string) error {
+	var types []string
+	switch v := t.(type) {
+	case string:
+		types = []string{v}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	for _, typ := range types {
+		if matchesType(typ, value) {
+			return nil
+		}
+	}
+
+	return &ValidationError{Path: path, Message: fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), typeName(value))}
+}
+
+func matchesType(typ string, value any) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func typeName(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func number(v any) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
+
+func encode(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
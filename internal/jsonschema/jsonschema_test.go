@@ -0,0 +1,136 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateJSON(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"required": ["name", "tags"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "pattern": "^[a-z-]+$"},
+			"count": {"type": "integer", "minimum": 0, "maximum": 10},
+			"tags": {"type": "array", "maxItems": 2, "items": {"$ref": "#/$defs/tag"}},
+			"level": {"enum": ["low", "high"]},
+			"note": {"type": ["string", "null"]},
+			"id": {"oneOf": [{"type": "string"}, {"type": "integer"}]}
+		},
+		"$defs": {
+			"tag": {"type": "string", "maxLength": 5}
+		}
+	}`
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"valid", `{"name":"ok","tags":["a"],"count":3,"level":"low","note":null,"id":7}`, ""},
+		{"not JSON", `{"name":`, "not valid JSON"},
+		{"wrong root type", `[]`, "expected object, got array"},
+		{"missing required", `{"name":"ok"}`, `missing required property "tags"`},
+		{"additional property", `{"name":"ok","tags":[],"extra":1}`, `unexpected property "extra"`},
+		{"pattern", `{"name":"Not OK","tags":[]}`, "/name: string"},
+		{"empty string", `{"name":"","tags":[]}`, "shorter than"},
+		{"integer", `{"name":"ok","tags":[],"count":1.5}`, "/count: expected integer, got number"},
+		{"maximum", `{"name":"ok","tags":[],"count":11}`, "greater than maximum"},
+		{"max items", `{"name":"ok","tags":["a","b","c"]}`, "at most 2 items"},
+		{"ref item", `{"name":"ok","tags":["toolong"]}`, "/tags/0: string longer than 5"},
+		{"enum", `{"name":"ok","tags":[],"level":"mid"}`, `value "mid" is not one of ["low","high"]`},
+		{"type list", `{"name":"ok","tags":[],"note":1}`, "expected string or null"},
+		{"oneOf", `{"name":"ok","tags":[],"id":true}`, "matched 0"},
+	}
+
+	compiled, err := Compile([]byte(schema))
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := compiled.ValidateJSON([]byte(tt.input))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Error = %q, want it to contain %q", err.Error(), tt.wantErr)
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected *ValidationError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestCompile_InvalidSchema(t *testing.T) {
+	if _, err := Compile([]byte(`{"type":`)); err == nil {
+		t.Error("Expected error for malformed schema")
+	}
+}
+
+func TestCompile_CircularRef(t *testing.T) {
+	schemas := []string{
+		`{"$ref":"#"}`,
+		`{"$defs":{"a":{"$ref":"#/$defs/a"}},"properties":{"x":{"$ref":"#/$defs/a"}}}`,
+		`{"$defs":{"a":{"$ref":"#/$defs/b"},"b":{"$ref":"#/$defs/a"}}}`,
+	}
+	for _, schema := range schemas {
+		if _, err := Compile([]byte(schema)); err == nil || !strings.Contains(err.Error(), "circular $ref") {
+			t.Errorf("Compile(%s) error = %v, want circular $ref", schema, err)
+		}
+	}
+}
+
+func TestValidate_RecursiveRef(t *testing.T) {
+	// A tree recurses through nested values, so it terminates
+	tree, err := Compile([]byte(`{
+		"$ref": "#/$defs/node",
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if err := tree.ValidateJSON([]byte(`{"children":[{"children":[{}]},{}]}`)); err != nil {
+		t.Errorf("Expected valid tree, got %v", err)
+	}
+	if err := tree.ValidateJSON([]byte(`{"children":[{"children":[1]}]}`)); err == nil || !strings.Contains(err.Error(), "/children/0/children/0") {
+		t.Errorf("Error = %v, want nested type error", err)
+	}
+
+	// A cycle through a combinator never reaches a nested value
+	loop, err := Compile([]byte(`{"allOf":[{"$ref":"#"}]}`))
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	err = loop.ValidateJSON([]byte(`{}`))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "circular $ref") {
+		t.Errorf("Error = %v, want circular $ref ValidationError", err)
+	}
+}
+
+func TestString_Compact(t *testing.T) {
+	schema, err := Compile([]byte("{\n  \"type\": \"object\"\n}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.String() != `{"type":"object"}` {
+		t.Errorf("String() = %q, want compact JSON", schema.String())
+	}
+}
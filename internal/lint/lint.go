@@ -0,0 +1,391 @@
+// Package lint checks skills and commands for problems that would make
+// them fail to load or behave differently than their author intended.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/command"
+	"github.com/martinemde/skillet/internal/skill"
+	"github.com/martinemde/skillet/internal/skillpath"
+	"github.com/martinemde/skillet/internal/toolrule"
+	"github.com/martinemde/skillet/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity of a diagnostic
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Kind of file being linted
+type Kind string
+
+const (
+	KindSkill   Kind = "skill"
+	KindCommand Kind = "command"
+)
+
+// Rule IDs
+const (
+	RuleReadError          = "read-error"
+	RuleFrontmatterMissing = "frontmatter-missing"
+	RuleYAMLSyntax         = "yaml-syntax"
+	RuleUnknownKey         = "unknown-key"
+	RuleNameInvalid        = "name-invalid"
+	RuleNameMismatch       = "name-mismatch"
+	RuleDescriptionMissing = "description-missing"
+	RuleDescriptionLength  = "description-too-long"
+	RuleAllowedTools       = "allowed-tools"
+	RuleUnknownTool        = "unknown-tool"
+	RuleArguments          = "arguments"
+	RuleMissingFile        = "missing-file"
+	RuleArgumentHint       = "argument-hint"
+)
+
+// RuleDescriptions describes each rule, for SARIF output and documentation
+var RuleDescriptions = map[string]string{
+	RuleReadError:          "The file could not be read",
+	RuleFrontmatterMissing: "Skills must start with YAML frontmatter between --- lines",
+	RuleYAMLSyntax:         "Frontmatter must be valid YAML with values of the expected type",
+	RuleUnknownKey:         "Frontmatter keys must be known to Claude Code, agentskills.io, or skillet",
+	RuleNameInvalid:        "Names must be lowercase letters, numbers, and hyphens",
+	RuleNameMismatch:       "A skill's name must match its directory name",
+	RuleDescriptionMissing: "Skills must have a description",
+	RuleDescriptionLength:  "Descriptions must be at most 1024 characters",
+	RuleAllowedTools:       "allowed-tools entries must be valid tool or permission rule syntax",
+	RuleUnknownTool:        "allowed-tools entries should name a known tool",
+	RuleArguments:          "Argument declarations must be valid",
+	RuleMissingFile:        "Files referenced via {baseDir} must exist",
+	RuleArgumentHint:       "Content using $ARGUMENTS should declare an argument-hint",
+}
+
+// maxDescriptionLength matches skill.Validate
+const maxDescriptionLength = 1024
+
+var (
+	// yamlLineRegex extracts the line number from yaml.v3 error messages
+	yamlLineRegex = regexp.MustCompile(`line (\d+)`)
+	// yamlLinePrefixRegex matches the "line N: " prefix of yaml.v3 error messages
+	yamlLinePrefixRegex = regexp.MustCompile(`^line \d+: `)
+	// baseDirRefRegex matches file references like {baseDir}/scripts/run.sh
+	baseDirRefRegex = regexp.MustCompile(`\{baseDir\}/([^\s` + "`" + `'"()\[\]<>,;:*$]+)`)
+)
+
+// Diagnostic is a single problem found in a file
+type Diagnostic struct {
+	Path     string   `json:"path"`
+	Line     int      `json:"line,omitempty"` // 1-based; 0 means the whole file
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Target is a file to lint
+type Target struct {
+	Path string
+	Kind Kind
+}
+
+// Files lints each target and returns all diagnostics, sorted by path and line
+func Files(targets []Target) []Diagnostic {
+	var diags []Diagnostic
+	for _, target := range targets {
+		diags = append(diags, File(target)...)
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].Path != diags[j].Path {
+			return diags[i].Path < diags[j].Path
+		}
+		return diags[i].Line < diags[j].Line
+	})
+	return diags
+}
+
+// File lints a single skill or command file
+func File(target Target) []Diagnostic {
+	l := &linter{path: target.Path, kind: target.Kind}
+
+	data, err := os.ReadFile(target.Path)
+	if err != nil {
+		l.report(0, RuleReadError, SeverityError, "failed to read file: %v", err)
+		return l.diags
+	}
+	l.lines = strings.Split(string(data), "\n")
+
+	l.lintFrontmatter()
+	l.lintContent()
+
+	return l.diags
+}
+
+// HasErrors reports whether any diagnostic is an error
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type linter struct {
+	path  string
+	kind  Kind
+	lines []string
+	diags []Diagnostic
+
+	// Frontmatter bounds as 0-based line indexes of the --- delimiters; -1 if absent
+	start, end int
+
+	// Decoded frontmatter values used by content checks
+	decoded      bool
+	argumentHint string
+	hasArguments bool
+}
+
+func (l *linter) report(line int, rule string, severity Severity, format string, args ...any) {
+	l.diags = append(l.diags, Diagnostic{
+		Path:     l.path,
+		Line:     line,
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// findFrontmatter locates the --- delimiters the same way frontmatter.Parse does
+func (l *linter) findFrontmatter() {
+	l.start, l.end = -1, -1
+	for i, line := range l.lines {
+		if strings.TrimSpace(line) != "---" {
+			continue
+		}
+		if l.start == -1 {
+			l.start = i
+		} else {
+			l.end = i
+			return
+		}
+	}
+}
+
+// fileLine converts a 1-based line within the frontmatter YAML to a 1-based file line
+func (l *linter) fileLine(yamlLine int) int {
+	return l.start + 1 + yamlLine
+}
+
+func (l *linter) lintFrontmatter() {
+	l.findFrontmatter()
+	if l.end == -1 {
+		if l.kind == KindSkill {
+			l.report(1, RuleFrontmatterMissing, SeverityError, "missing YAML frontmatter (expected --- at the start and end)")
+		}
+		l.lintName("", 0)
+		l.decoded = l.kind == KindCommand
+		return
+	}
+
+	yamlText := strings.Join(l.lines[l.start+1:l.end], "\n")
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlText), &doc); err != nil {
+		l.report(l.yamlErrorLine(err), RuleYAMLSyntax, SeverityError, "%s", yamlMessage(err))
+		return
+	}
+
+	var mapping *yaml.Node
+	if len(doc.Content) > 0 {
+		mapping = doc.Content[0]
+	}
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		if mapping != nil {
+			l.report(l.fileLine(mapping.Line), RuleYAMLSyntax, SeverityError, "frontmatter must be a mapping of keys to values")
+			return
+		}
+		mapping = &yaml.Node{Kind: yaml.MappingNode}
+	}
+
+	keyLines := make(map[string]int)
+	known := knownKeys(l.kind)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		keyLines[key.Value] = l.fileLine(key.Line)
+		if !known[key.Value] {
+			l.report(l.fileLine(key.Line), RuleUnknownKey, SeverityWarning, "unknown frontmatter key %q", key.Value)
+		}
+	}
+
+	switch l.kind {
+	case KindSkill:
+		var s skill.Skill
+		if err := mapping.Decode(&s); err != nil {
+			l.report(l.yamlErrorLine(err), RuleYAMLSyntax, SeverityError, "%s", yamlMessage(err))
+			return
+		}
+		l.lintName(s.Name, keyLines["name"])
+		l.lintDescription(s.Description, keyLines["description"], true)
+		l.lintAllowedTools(s.AllowedTools, keyLines["allowed-tools"])
+		l.lintArguments(s.Arguments, keyLines["arguments"])
+		l.argumentHint, l.hasArguments = s.ArgumentHint, len(s.Arguments) > 0
+		l.decoded = true
+	case KindCommand:
+		var c command.Command
+		if err := mapping.Decode(&c); err != nil {
+			l.report(l.yamlErrorLine(err), RuleYAMLSyntax, SeverityError, "%s", yamlMessage(err))
+			return
+		}
+		l.lintName("", 0)
+		l.lintDescription(c.Description, keyLines["description"], false)
+		l.lintAllowedTools(c.AllowedTools, keyLines["allowed-tools"])
+		l.lintArguments(c.Arguments, keyLines["arguments"])
+		l.argumentHint, l.hasArguments = c.ArgumentHint, len(c.Arguments) > 0
+		l.decoded = true
+	}
+}
+
+// lintName checks the declared or derived name against the file location
+func (l *linter) lintName(declared string, line int) {
+	var derived, resourceType string
+	switch l.kind {
+	case KindSkill:
+		derived = filepath.Base(filepath.Dir(l.path))
+		resourceType = "skill"
+	case KindCommand:
+		derived = strings.TrimSuffix(filepath.Base(l.path), ".md")
+		resourceType = "command"
+	}
+
+	name := declared
+	if name == "" {
+		name = derived
+	}
+	if line == 0 {
+		line = 1
+	}
+
+	if err := validation.ValidateName(name, resourceType); err != nil {
+		l.report(line, RuleNameInvalid, SeverityError, "%s", err)
+		return
+	}
+
+	if declared != "" && declared != derived {
+		l.report(line, RuleNameMismatch, SeverityError, "name %q does not match directory %q", declared, derived)
+	}
+}
+
+func (l *linter) lintDescription(description string, line int, required bool) {
+	if description == "" {
+		if required {
+			l.report(l.start+1, RuleDescriptionMissing, SeverityError, "description is required")
+		}
+		return
+	}
+	if len(description) > maxDescriptionLength {
+		l.report(line, RuleDescriptionLength, SeverityError, "description too long: max %d characters, got %d", maxDescriptionLength, len(description))
+	}
+}
+
+func (l *linter) lintAllowedTools(allowedTools string, line int) {
+	for _, entry := range toolrule.Split(allowedTools) {
+		rule, err := toolrule.Parse(entry)
+		if err != nil {
+			l.report(line, RuleAllowedTools, SeverityError, "%s", err)
+			continue
+		}
+		if !rule.IsKnown() {
+			l.report(line, RuleUnknownTool, SeverityWarning, "unknown tool %q in allowed-tools", rule.Tool)
+		}
+	}
+}
+
+func (l *linter) lintArguments(decls []arguments.Declaration, line int) {
+	if err := arguments.Validate(decls); err != nil {
+		l.report(line, RuleArguments, SeverityError, "%s", err)
+	}
+}
+
+// lintContent checks {baseDir} references and $ARGUMENTS usage
+func (l *linter) lintContent() {
+	baseDir := filepath.Dir(l.path)
+	reportedHint := false
+
+	for i, line := range l.lines {
+		for _, match := range baseDirRefRegex.FindAllStringSubmatch(line, -1) {
+			ref := strings.TrimRight(match[1], ".")
+			if ref == "" {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(baseDir, filepath.FromSlash(ref))); err != nil {
+				l.report(i+1, RuleMissingFile, SeverityError, "referenced file {baseDir}/%s does not exist", ref)
+			}
+		}
+
+		inContent := l.end == -1 || i > l.end
+		if inContent && l.decoded && !reportedHint && strings.Contains(line, "$ARGUMENTS") && l.argumentHint == "" && !l.hasArguments {
+			l.report(i+1, RuleArgumentHint, SeverityWarning, "$ARGUMENTS is used but no argument-hint is set")
+			reportedHint = true
+		}
+	}
+}
+
+// yamlErrorLine maps a yaml.v3 error to a file line, defaulting to the opening ---
+func (l *linter) yamlErrorLine(err error) int {
+	if match := yamlLineRegex.FindStringSubmatch(err.Error()); match != nil {
+		if n, convErr := strconv.Atoi(match[1]); convErr == nil {
+			return l.fileLine(n)
+		}
+	}
+	return l.start + 1
+}
+
+// yamlMessage trims yaml.v3's prefixes, since the line is reported separately.
+// Type errors only report the first problem.
+func yamlMessage(err error) string {
+	msg := err.Error()
+	if typeErr, ok := err.(*yaml.TypeError); ok && len(typeErr.Errors) > 0 {
+		msg = typeErr.Errors[0]
+	}
+	msg = strings.TrimPrefix(msg, "yaml: ")
+	return yamlLinePrefixRegex.ReplaceAllString(msg, "")
+}
+
+// knownKeys returns the frontmatter keys accepted for a kind, from the yaml struct tags
+func knownKeys(kind Kind) map[string]bool {
+	var t reflect.Type
+	switch kind {
+	case KindSkill:
+		t = reflect.TypeOf(skill.Skill{})
+	case KindCommand:
+		t = reflect.TypeOf(command.Command{})
+	}
+
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("yaml")
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// KindForPath guesses whether a markdown file is a skill or a command
+func KindForPath(path string) Kind {
+	if strings.EqualFold(filepath.Base(path), skillpath.SkillFile) {
+		return KindSkill
+	}
+	return KindCommand
+}
@@ -0,0 +1,270 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile creates a file under dir, making parent directories
+func writeFile(t *testing.T, dir, rel, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFile_Skill(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantRule string
+		wantLine int
+		wantMsg  string
+	}{
+		{
+			name:     "yaml syntax",
+			content:  "---\nname: my-skill\ndescription: Test\n  bad: value\n---\nBody\n",
+			wantRule: RuleYAMLSyntax,
+			wantLine: 4,
+		},
+		{
+			name:     "wrong value type",
+			content:  "---\nname: my-skill\ndescription: Test\nallowed-tools:\n  - Read\n---\nBody\n",
+			wantRule: RuleYAMLSyntax,
+			wantLine: 5,
+			wantMsg:  "cannot unmarshal",
+		},
+		{
+			name:     "missing frontmatter",
+			content:  "Just content\n",
+			wantRule: RuleFrontmatterMissing,
+			wantLine: 1,
+		},
+		{
+			name:     "name mismatch",
+			content:  "---\nname: other-name\ndescription: Test\n---\nBody\n",
+			wantRule: RuleNameMismatch,
+			wantLine: 2,
+			wantMsg:  `name "other-name" does not match directory "my-skill"`,
+		},
+		{
+			name:     "invalid name",
+			content:  "---\nname: My_Skill\ndescription: Test\n---\nBody\n",
+			wantRule: RuleNameInvalid,
+			wantLine: 2,
+		},
+		{
+			name:     "missing description",
+			content:  "---\nname: my-skill\n---\nBody\n",
+			wantRule: RuleDescriptionMissing,
+			wantLine: 1,
+		},
+		{
+			name:     "description too long",
+			content:  "---\nname: my-skill\ndescription: " + strings.Repeat("a", 1025) + "\n---\nBody\n",
+			wantRule: RuleDescriptionLength,
+			wantLine: 3,
+		},
+		{
+			name:     "unknown key",
+			content:  "---\nname: my-skill\ndescription: Test\nallowed_tools: Read\n---\nBody\n",
+			wantRule: RuleUnknownKey,
+			wantLine: 4,
+			wantMsg:  `"allowed_tools"`,
+		},
+		{
+			name:     "invalid allowed-tools rule",
+			content:  "---\nname: my-skill\ndescription: Test\nallowed-tools: Read Bash(git\n---\nBody\n",
+			wantRule: RuleAllowedTools,
+			wantLine: 4,
+			wantMsg:  "missing ')'",
+		},
+		{
+			name:     "unknown tool",
+			content:  "---\nname: my-skill\ndescription: Test\nallowed-tools: Read, Reed\n---\nBody\n",
+			wantRule: RuleUnknownTool,
+			wantLine: 4,
+			wantMsg:  `"Reed"`,
+		},
+		{
+			name:     "invalid arguments",
+			content:  "---\nname: my-skill\ndescription: Test\narguments:\n  - name: file\n    type: list\n---\nBody\n",
+			wantRule: RuleArguments,
+			wantLine: 4,
+		},
+		{
+			name:     "missing baseDir file",
+			content:  "---\nname: my-skill\ndescription: Test\n---\n\nRun `{baseDir}/scripts/missing.sh` now.\n",
+			wantRule: RuleMissingFile,
+			wantLine: 6,
+			wantMsg:  "{baseDir}/scripts/missing.sh",
+		},
+		{
+			name:     "arguments without hint",
+			content:  "---\nname: my-skill\ndescription: Test\n---\n\nDo $ARGUMENTS\n",
+			wantRule: RuleArgumentHint,
+			wantLine: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), "my-skill/SKILL.md", tt.content)
+			diags := File(Target{Path: path, Kind: KindSkill})
+
+			if len(diags) != 1 {
+				t.Fatalf("Expected 1 diagnostic, got %d: %+v", len(diags), diags)
+			}
+			d := diags[0]
+			if d.Rule != tt.wantRule || d.Line != tt.wantLine {
+				t.Errorf("Diagnostic = %s:%d, want %s:%d (%s)", d.Rule, d.Line, tt.wantRule, tt.wantLine, d.Message)
+			}
+			if tt.wantMsg != "" && !strings.Contains(d.Message, tt.wantMsg) {
+				t.Errorf("Message = %q, want it to contain %q", d.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestFile_CleanSkill(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "clean/scripts/run.sh", "#!/bin/sh\n")
+	path := writeFile(t, dir, "clean/SKILL.md", `---
+name: clean
+description: A skill with no problems
+argument-hint: <file>
+allowed-tools: Read, Bash({baseDir}/scripts/run.sh:*), mcp__github__get_issue
+---
+
+Run {baseDir}/scripts/run.sh on $ARGUMENTS.
+`)
+
+	if diags := File(Target{Path: path, Kind: KindSkill}); len(diags) != 0 {
+		t.Errorf("Expected no diagnostics, got %+v", diags)
+	}
+}
+
+func TestFile_Command(t *testing.T) {
+	dir := t.TempDir()
+
+	clean := writeFile(t, dir, "commands/deploy.md", "Deploy $ARGUMENTS\n")
+	diags := File(Target{Path: clean, Kind: KindCommand})
+	if len(diags) != 1 || diags[0].Rule != RuleArgumentHint {
+		t.Errorf("Command without frontmatter should only warn about argument-hint, got %+v", diags)
+	}
+
+	named := writeFile(t, dir, "commands/Bad_Name.md", "---\nname: bad\n---\nDo it\n")
+	diags = File(Target{Path: named, Kind: KindCommand})
+	rules := make([]string, len(diags))
+	for i, d := range diags {
+		rules[i] = d.Rule
+	}
+	if strings.Join(rules, ",") != RuleUnknownKey+","+RuleNameInvalid {
+		t.Errorf("Rules = %v, want unknown-key and name-invalid", rules)
+	}
+}
+
+func TestTargetsFromPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".claude/skills/one/SKILL.md", "")
+	writeFile(t, dir, ".claude/skills/ns/two/SKILL.md", "")
+	writeFile(t, dir, ".claude/commands/run.md", "")
+	writeFile(t, dir, ".claude/README.md", "")
+
+	targets, err := TargetsFromPaths([]string{filepath.Join(dir, ".claude")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]Kind)
+	for _, target := range targets {
+		rel, _ := filepath.Rel(dir, target.Path)
+		got[filepath.ToSlash(rel)] = target.Kind
+	}
+	want := map[string]Kind{
+		".claude/skills/one/SKILL.md":    KindSkill,
+		".claude/skills/ns/two/SKILL.md": KindSkill,
+		".claude/commands/run.md":        KindCommand,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Targets = %v, want %v", got, want)
+	}
+	for path, kind := range want {
+		if got[path] != kind {
+			t.Errorf("Target %s = %q, want %q", path, got[path], kind)
+		}
+	}
+
+	skillDir, err := TargetsFromPaths([]string{filepath.Join(dir, ".claude/skills/one")})
+	if err != nil || len(skillDir) != 1 || skillDir[0].Kind != KindSkill {
+		t.Errorf("A skill directory should lint its SKILL.md, got %+v, %v", skillDir, err)
+	}
+}
+
+func TestWrite_Formats(t *testing.T) {
+	diags := []Diagnostic{
+		{Path: "skills/a/SKILL.md", Line: 3, Rule: RuleUnknownKey, Severity: SeverityWarning, Message: `unknown frontmatter key "x"`},
+		{Path: "skills/a/SKILL.md", Line: 5, Rule: RuleMissingFile, Severity: SeverityError, Message: "missing"},
+	}
+
+	var text bytes.Buffer
+	if err := Write(&text, FormatText, diags, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`skills/a/SKILL.md:3: warning: unknown frontmatter key "x" (unknown-key)`,
+		"1 error, 1 warning in 1 file",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Text output should contain %q, got:\n%s", want, text.String())
+		}
+	}
+
+	var jsonOut bytes.Buffer
+	if err := Write(&jsonOut, FormatJSON, diags, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Diagnostic
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil || len(decoded) != 2 {
+		t.Errorf("JSON output should decode to diagnostics, got %v: %s", err, jsonOut.String())
+	}
+
+	var sarif bytes.Buffer
+	if err := Write(&sarif, FormatSARIF, diags, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(sarif.Bytes(), &log); err != nil {
+		t.Fatalf("SARIF output should be JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 2 {
+		t.Fatalf("Unexpected SARIF log: %s", sarif.String())
+	}
+	result := log.Runs[0].Results[1]
+	if result.RuleID != RuleMissingFile || result.Level != "error" || result.Locations[0].PhysicalLocation.Region.StartLine != 5 {
+		t.Errorf("Unexpected SARIF result: %+v", result)
+	}
+
+	if err := Write(&text, "xml", diags, 1, false); err == nil {
+		t.Error("Unknown format should fail")
+	}
+}
+
+func TestWriteText_NoProblems(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteText(&out, nil, 3, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "No problems found in 3 files\n" {
+		t.Errorf("Output = %q", out.String())
+	}
+}
@@ -0,0 +1,199 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Output formats
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// Formats lists the supported output formats
+var Formats = []string{FormatText, FormatJSON, FormatSARIF}
+
+// Write writes diagnostics in the given format. files is the number of files linted.
+func Write(w io.Writer, format string, diags []Diagnostic, files int, useColors bool) error {
+	switch format {
+	case "", FormatText:
+		return WriteText(w, diags, files, useColors)
+	case FormatJSON:
+		return WriteJSON(w, diags)
+	case FormatSARIF:
+		return WriteSARIF(w, diags)
+	default:
+		return fmt.Errorf("unknown lint format %q (must be text, json, or sarif)", format)
+	}
+}
+
+// WriteText writes diagnostics as "path:line: severity: message (rule)" lines and a summary
+func WriteText(w io.Writer, diags []Diagnostic, files int, useColors bool) error {
+	locationStyle := lipgloss.NewStyle().Bold(true)
+	errorStyle := lipgloss.NewStyle()
+	warningStyle := lipgloss.NewStyle()
+	ruleStyle := lipgloss.NewStyle()
+
+	if useColors {
+		errorStyle = errorStyle.Foreground(lipgloss.Color("1"))     // Red
+		warningStyle = warningStyle.Foreground(lipgloss.Color("3")) // Yellow
+		ruleStyle = ruleStyle.Foreground(lipgloss.Color("8"))       // Dim
+	}
+
+	errors, warnings := 0, 0
+	for _, d := range diags {
+		location := d.Path
+		if d.Line > 0 {
+			location = fmt.Sprintf("%s:%d", d.Path, d.Line)
+		}
+
+		severity := warningStyle.Render(string(d.Severity))
+		if d.Severity == SeverityError {
+			severity = errorStyle.Render(string(d.Severity))
+			errors++
+		} else {
+			warnings++
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s: %s %s\n", locationStyle.Render(location), severity, d.Message, ruleStyle.Render("("+d.Rule+")")); err != nil {
+			return err
+		}
+	}
+
+	if len(diags) == 0 {
+		_, err := fmt.Fprintf(w, "No problems found in %d %s\n", files, plural(files, "file", "files"))
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d %s, %d %s in %d %s\n",
+		errors, plural(errors, "error", "errors"),
+		warnings, plural(warnings, "warning", "warnings"),
+		files, plural(files, "file", "files"))
+	return err
+}
+
+// WriteJSON writes diagnostics as a JSON array
+func WriteJSON(w io.Writer, diags []Diagnostic) error {
+	if diags == nil {
+		diags = []Diagnostic{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diags)
+}
+
+// SARIF 2.1.0 types, limited to what code scanning tools read
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSARIF writes diagnostics as a SARIF 2.1.0 log for code scanning tools
+func WriteSARIF(w io.Writer, diags []Diagnostic) error {
+	ruleIDs := make([]string, 0, len(RuleDescriptions))
+	for id := range RuleDescriptions {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+
+	rules := make([]sarifRule, len(ruleIDs))
+	for i, id := range ruleIDs {
+		rules[i] = sarifRule{ID: id, ShortDescription: sarifMessage{Text: RuleDescriptions[id]}}
+	}
+
+	results := make([]sarifResult, len(diags))
+	for i, d := range diags {
+		location := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(d.Path)},
+		}
+		if d.Line > 0 {
+			location.Region = &sarifRegion{StartLine: d.Line}
+		}
+		results[i] = sarifResult{
+			RuleID:    d.Rule,
+			Level:     string(d.Severity),
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		}
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "skillet",
+				InformationURI: "https://github.com/martinemde/skillet",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/command"
+	"github.com/martinemde/skillet/internal/commandpath"
+	"github.com/martinemde/skillet/internal/discovery"
+	"github.com/martinemde/skillet/internal/skillpath"
+)
+
+// DiscoverTargets returns every skill and command on the default search paths,
+// including overshadowed ones, since they're still files someone maintains.
+func DiscoverTargets() ([]Target, error) {
+	var targets []Target
+
+	skillPath, err := skillpath.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize skill path: %w", err)
+	}
+	skills, err := discovery.New(skillPath).Discover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover skills: %w", err)
+	}
+	for _, s := range skills {
+		targets = append(targets, Target{Path: s.Path, Kind: KindSkill})
+	}
+
+	cmdPath, err := commandpath.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize command path: %w", err)
+	}
+	commands, err := command.NewDiscoverer(cmdPath).Discover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover commands: %w", err)
+	}
+	for _, c := range commands {
+		targets = append(targets, Target{Path: c.Path, Kind: KindCommand})
+	}
+
+	return targets, nil
+}
+
+// TargetsFromPaths returns targets for the given files and directories.
+// A directory containing SKILL.md is a skill; other directories are searched
+// for SKILL.md files and for .md files under a commands/ directory.
+func TargetsFromPaths(paths []string) ([]Target, error) {
+	var targets []Target
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint %s: %w", path, err)
+		}
+
+		if !info.IsDir() {
+			targets = append(targets, Target{Path: path, Kind: KindForPath(path)})
+			continue
+		}
+
+		skillFile := filepath.Join(path, skillpath.SkillFile)
+		if _, err := os.Stat(skillFile); err == nil {
+			targets = append(targets, Target{Path: skillFile, Kind: KindSkill})
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if strings.EqualFold(d.Name(), skillpath.SkillFile) {
+				targets = append(targets, Target{Path: p, Kind: KindSkill})
+			} else if strings.HasSuffix(d.Name(), ".md") && inCommandsDir(path, p) {
+				targets = append(targets, Target{Path: p, Kind: KindCommand})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint %s: %w", path, err)
+		}
+	}
+
+	return targets, nil
+}
+
+// inCommandsDir reports whether p is below a directory named "commands",
+// counting root itself
+func inCommandsDir(root, p string) bool {
+	rel, err := filepath.Rel(root, filepath.Dir(p))
+	if err != nil {
+		return false
+	}
+	if filepath.Base(root) == "commands" {
+		return true
+	}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if part == "commands" {
+			return true
+		}
+	}
+	return false
+}
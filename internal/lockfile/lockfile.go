@@ -0,0 +1,123 @@
+// Package lockfile reads and writes skillet.lock, which pins each remote
+// skill a project uses to the SHA-256 of its content and, for git sources,
+// the commit it resolved to.
+package lockfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the lockfile in the project directory
+const FileName = "skillet.lock"
+
+// header starts every written lockfile
+const header = "# Written by skillet lock. Remote skills must match these hashes;\n# run with --update to accept changed content.\n"
+
+// Entry pins one remote skill or command
+type Entry struct {
+	Commit string `yaml:"commit,omitempty"` // Commit a git source resolved to
+	SHA256 string `yaml:"sha256"`           // Hash of the file or bundle
+}
+
+// File is a lockfile keyed by the URL or git source as given to skillet
+type File struct {
+	Skills map[string]Entry `yaml:"skills"`
+
+	path string
+}
+
+// FindDir returns the directory of the nearest skillet.lock in workDir or a
+// parent up to the git root. Without one it returns the git root, so a new
+// lockfile covers the whole project, or workDir outside a git repository.
+func FindDir(workDir string) string {
+	start, err := filepath.Abs(workDir)
+	if err != nil {
+		return workDir
+	}
+	for dir := start; ; {
+		if _, err := os.Stat(filepath.Join(dir, FileName)); err == nil {
+			return dir
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return start
+		}
+		dir = parent
+	}
+}
+
+// Load reads the lockfile in dir. A missing file is an empty lockfile that
+// Save creates.
+func Load(dir string) (*File, error) {
+	path := filepath.Join(dir, FileName)
+	f := &File{Skills: map[string]Entry{}, path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if f.Skills == nil {
+		f.Skills = map[string]Entry{}
+	}
+	return f, nil
+}
+
+// Path returns the lockfile's path
+func (f *File) Path() string {
+	return f.path
+}
+
+// Get returns the entry for a source
+func (f *File) Get(source string) (Entry, bool) {
+	entry, ok := f.Skills[source]
+	return entry, ok
+}
+
+// Set adds or replaces the entry for a source
+func (f *File) Set(source string, entry Entry) {
+	f.Skills[source] = entry
+}
+
+// Save writes the lockfile with sources in sorted order
+func (f *File) Save() error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", FileName, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".skillet-lock-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", FileName, err)
+	}
+	_, err = tmp.Write(append([]byte(header), data...))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", FileName, err)
+	}
+	return nil
+}
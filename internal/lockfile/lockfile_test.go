@@ -0,0 +1,79 @@
+package lockfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSave(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() of a missing lockfile error = %v", err)
+	}
+	if len(f.Skills) != 0 || f.Path() != filepath.Join(dir, FileName) {
+		t.Errorf("Load() = %+v, want an empty lockfile at %s", f, FileName)
+	}
+
+	f.Set("https://example.com/review/SKILL.md", Entry{SHA256: "aaa"})
+	f.Set("github:acme/skills/fmt@v1", Entry{Commit: "c0ffee", SHA256: "bbb"})
+	if err := f.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(f.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "# Written by skillet lock") {
+		t.Errorf("lockfile should start with a header, got:\n%s", content)
+	}
+	if strings.Index(content, "github:") > strings.Index(content, "https:") {
+		t.Errorf("sources should be sorted, got:\n%s", content)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if entry, ok := loaded.Get("github:acme/skills/fmt@v1"); !ok || entry.Commit != "c0ffee" || entry.SHA256 != "bbb" {
+		t.Errorf("Get() = %+v, %t", entry, ok)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte("skills:\n  x:\n    sha: abc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("Load() error = %v, want a parse error for unknown fields", err)
+	}
+}
+
+func TestFindDir(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a lockfile, a new one goes at the git root
+	if got := FindDir(sub); got != root {
+		t.Errorf("FindDir() without a lockfile = %s, want the git root %s", got, root)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "services", FileName), []byte("skills: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := FindDir(sub), filepath.Join(root, "services"); got != want {
+		t.Errorf("FindDir() = %s, want the nearest lockfile's directory %s", got, want)
+	}
+}
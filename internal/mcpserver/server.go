@@ -6,9 +6,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/martinemde/skillet/internal/audit"
+	"github.com/martinemde/skillet/internal/policy"
 	"github.com/martinemde/skillet/internal/promptserver"
 )
 
@@ -46,13 +49,45 @@ func Run() error {
 		),
 	)
 
-	s.AddTool(tool, handlePrompt)
+	s.AddTool(tool, newPromptHandler().handle)
 
 	return server.ServeStdio(s)
 }
 
-// handlePrompt handles incoming prompt requests
-func handlePrompt(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// promptHandler answers permission prompts using the skillet policy and,
+// for tools the policy doesn't decide, the user at the parent's terminal
+type promptHandler struct {
+	policy    *policy.Policy
+	policyErr error // A policy that fails to load denies every tool
+
+	// requestPermission asks the parent process to approve a tool use;
+	// nil when there is no parent to ask
+	requestPermission func(promptserver.PermissionRequest) (string, error)
+
+	// audit records each decision; nil when the run isn't audited
+	audit *audit.Log
+}
+
+// newPromptHandler loads the policy for the working directory Claude runs in,
+// connects to the parent prompt server, and continues the run's audit log
+func newPromptHandler() *promptHandler {
+	h := &promptHandler{}
+
+	workDir, err := os.Getwd()
+	if err == nil {
+		h.policy, err = policy.Load(workDir)
+	}
+	h.policyErr = err
+
+	if client := promptserver.NewClient(); client != nil {
+		h.requestPermission = client.RequestPermission
+	}
+	h.audit = audit.FromEnv()
+	return h
+}
+
+// handle handles incoming prompt requests
+func (h *promptHandler) handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Parse input
 	var input PromptInput
 	inputBytes, err := json.Marshal(req.Params.Arguments)
@@ -65,20 +100,34 @@ func handlePrompt(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolRe
 
 	var output PromptOutput
 
-	switch input.ToolName {
-	case "AskUserQuestion":
+	decision, reason := h.decide(input)
+	switch {
+	case decision != nil:
+		output = *decision
+	case input.ToolName == "AskUserQuestion":
 		output, err = handleAskUserQuestion(input.ToolInput)
 		if err != nil {
 			return nil, err
 		}
 	default:
-		// For all other tools, auto-allow (permission-mode handles restrictions)
+		// Allowed (permission-mode handles other restrictions)
 		output = PromptOutput{
 			Behavior:     "allow",
 			UpdatedInput: input.ToolInput,
 		}
 	}
 
+	if err := h.audit.Write(audit.Record{
+		Type:     audit.TypePermission,
+		Tool:     input.ToolName,
+		Input:    input.ToolInput,
+		Decision: output.Behavior,
+		Reason:   reason,
+	}); err != nil {
+		// The run must not continue unaudited
+		output = PromptOutput{Behavior: "deny", Message: err.Error()}
+	}
+
 	// Marshal output to JSON
 	outputBytes, err := json.Marshal(output)
 	if err != nil {
@@ -88,6 +137,69 @@ func handlePrompt(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolRe
 	return mcp.NewToolResultText(string(outputBytes)), nil
 }
 
+// decide returns a deny response when the policy or the user blocks the tool,
+// or nil when the tool may be used, along with the reason for the audit log.
+// Tools that no policy rule allows are sent to the parent for approval; when
+// the parent can't ask, only tools with an explicit ask decision are denied.
+func (h *promptHandler) decide(input PromptInput) (*PromptOutput, string) {
+	if h.policyErr != nil {
+		message := fmt.Sprintf("Denied because the skillet policy could not be loaded: %v", h.policyErr)
+		return &PromptOutput{Behavior: "deny", Message: message}, message
+	}
+
+	result := policy.Result{Decision: policy.Allow}
+	if h.policy != nil {
+		result = h.policy.Evaluate(input.ToolName, input.ToolInput)
+	}
+
+	switch {
+	case result.Decision == policy.Deny:
+		return &PromptOutput{
+			Behavior: "deny",
+			Message:  fmt.Sprintf("Denied by %s", result.Reason()),
+		}, result.Reason()
+	case input.ToolName == "AskUserQuestion":
+		// The question itself is the prompt
+		return nil, result.Reason()
+	case result.Decision == policy.Ask || result.Implicit():
+		return h.approve(input, result)
+	}
+	return nil, result.Reason()
+}
+
+// approve asks the parent to approve a tool use
+func (h *promptHandler) approve(input PromptInput, result policy.Result) (*PromptOutput, string) {
+	var reason string
+	if result.Decision == policy.Ask {
+		reason = "Requires approval by " + result.Reason()
+	}
+
+	var decision string
+	err := fmt.Errorf("no terminal to ask for approval")
+	if h.requestPermission != nil {
+		decision, err = h.requestPermission(promptserver.PermissionRequest{
+			ToolName: input.ToolName,
+			Input:    input.ToolInput,
+			Reason:   reason,
+		})
+	}
+
+	switch {
+	case err != nil && result.Decision == policy.Ask:
+		message := fmt.Sprintf("%s, and approval failed: %v", reason, err)
+		return &PromptOutput{Behavior: "deny", Message: message}, message
+	case err != nil:
+		// Nobody to ask; keep the behavior of runs without a policy
+		return nil, fmt.Sprintf("%s (%v)", result.Reason(), err)
+	case decision == promptserver.Deny:
+		return &PromptOutput{
+			Behavior: "deny",
+			Message:  "The user denied permission to use " + input.ToolName,
+		}, "user: " + decision
+	}
+	return nil, "user: " + decision
+}
+
 // handleAskUserQuestion processes AskUserQuestion tool calls
 func handleAskUserQuestion(toolInput map[string]any) (PromptOutput, error) {
 	// Get client to connect to parent prompt server
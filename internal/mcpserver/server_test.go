@@ -0,0 +1,121 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/martinemde/skillet/internal/audit"
+	"github.com/martinemde/skillet/internal/policy"
+	"github.com/martinemde/skillet/internal/promptserver"
+)
+
+// callPrompt invokes the prompt handler and decodes its output
+func callPrompt(t *testing.T, h *promptHandler, tool string, input map[string]any) PromptOutput {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"tool_name": tool, "input": input}
+
+	result, err := h.handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handle() error = %v", err)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("unexpected content %T", result.Content[0])
+	}
+	var output PromptOutput
+	if err := json.Unmarshal([]byte(text.Text), &output); err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+func TestHandlePrompt_Policy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, policy.FileName)
+	if err := os.WriteFile(path, []byte("deny:\n  - Bash(rm:*)\nask:\n  - Bash(git push:*)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pol, err := policy.LoadFiles(dir, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		command  string
+		decision string // Parent's answer; empty when there is no parent
+		behavior string
+		message  string
+	}{
+		{"unmatched without parent", "ls", "", "allow", ""},
+		{"unmatched approved once", "ls", promptserver.AllowOnce, "allow", ""},
+		{"unmatched denied by user", "ls", promptserver.Deny, "deny", "The user denied permission to use Bash"},
+		{"denied by rule", "rm -rf /", promptserver.AllowOnce, "deny", "Denied by skillet policy rule Bash(rm:*) in " + path},
+		{"ask without parent", "git push", "", "deny", "Requires approval by skillet policy rule Bash(git push:*)"},
+		{"ask approved", "git push", promptserver.AllowAlways, "allow", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &promptHandler{policy: pol}
+			if tt.decision != "" {
+				h.requestPermission = func(req promptserver.PermissionRequest) (string, error) {
+					if req.ToolName != "Bash" || req.Input["command"] != tt.command {
+						t.Errorf("unexpected request %+v", req)
+					}
+					return tt.decision, nil
+				}
+			}
+
+			output := callPrompt(t, h, "Bash", map[string]any{"command": tt.command})
+			if output.Behavior != tt.behavior {
+				t.Errorf("Behavior = %q, want %q", output.Behavior, tt.behavior)
+			}
+			if !strings.HasPrefix(output.Message, tt.message) {
+				t.Errorf("Message = %q, want prefix %q", output.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestHandlePrompt_PolicyError(t *testing.T) {
+	output := callPrompt(t, &promptHandler{policyErr: errors.New("bad rule")}, "Read", map[string]any{"file_path": "x"})
+	if output.Behavior != "deny" || !strings.Contains(output.Message, "bad rule") {
+		t.Errorf("output = %+v, want deny mentioning the load error", output)
+	}
+}
+
+func TestHandlePrompt_Audit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &promptHandler{
+		audit: log,
+		requestPermission: func(promptserver.PermissionRequest) (string, error) {
+			return promptserver.Deny, nil
+		},
+	}
+
+	callPrompt(t, h, "Bash", map[string]any{"command": "make"})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record audit.Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Type != audit.TypePermission || record.Tool != "Bash" || record.Decision != "deny" || record.Reason != "user: deny" {
+		t.Errorf("unexpected audit record: %+v", record)
+	}
+}
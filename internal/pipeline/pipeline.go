@@ -0,0 +1,308 @@
+// Package pipeline runs skills in a chain defined by a pipeline.yaml. Each
+// step's arguments are templates that can use the results of the steps it
+// depends on; steps whose dependencies are done run in parallel.
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/formatter"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the pipeline `skillet pipeline run` reads when none is given
+const FileName = "pipeline.yaml"
+
+// Pipeline is a set of steps loaded from a pipeline file
+type Pipeline struct {
+	Name  string  `yaml:"name,omitempty"` // Defaults to the file name
+	Steps []*Step `yaml:"steps"`
+
+	// Path is the pipeline file
+	Path string `yaml:"-"`
+}
+
+// Step runs one skill or command. Args and Named values are templates, e.g.
+// "{{ .steps.review.result }}" or "{{ json .steps.plan.output.files }}".
+type Step struct {
+	ID              string            `yaml:"id"`
+	Skill           string            `yaml:"skill"`                       // Skill or command, resolved as on the command line
+	Args            []string          `yaml:"args,omitempty"`              // Positional arguments
+	Named           map[string]string `yaml:"named,omitempty"`             // Named arguments (--name=value)
+	Needs           []string          `yaml:"needs,omitempty"`             // Steps to wait for; defaults to the previous step
+	ContinueOnError bool              `yaml:"continue-on-error,omitempty"` // Keep going if this step fails
+
+	deps  []string // Steps this one waits for
+	args  []*template.Template
+	named map[string]*template.Template
+}
+
+// Deps returns the steps this step waits for: Needs when given (needs: []
+// starts right away), otherwise the step before it
+func (s *Step) Deps() []string {
+	return s.deps
+}
+
+// idPattern keeps step IDs usable in templates as .steps.<id>
+var idPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// stepRef finds the steps a template refers to
+var stepRef = regexp.MustCompile(`\.steps\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Load reads and validates a pipeline file
+func Load(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline: %w", err)
+	}
+
+	// Unknown keys are errors, so a typo like need: doesn't silently fall
+	// back to waiting for the previous step
+	p := &Pipeline{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	p.Path = path
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return p, nil
+}
+
+// validate checks the steps, resolves their dependencies, and parses their templates
+func (p *Pipeline) validate() error {
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("no steps")
+	}
+
+	steps := make(map[string]*Step, len(p.Steps))
+	for i, s := range p.Steps {
+		if s == nil {
+			return fmt.Errorf("step %d is empty", i+1)
+		}
+		if s.ID == "" {
+			return fmt.Errorf("step %d has no id", i+1)
+		}
+		if !idPattern.MatchString(s.ID) {
+			return fmt.Errorf("invalid step id %q (use letters, digits, and underscores)", s.ID)
+		}
+		if steps[s.ID] != nil {
+			return fmt.Errorf("duplicate step id %q", s.ID)
+		}
+		if s.Skill == "" {
+			return fmt.Errorf("step %s has no skill", s.ID)
+		}
+		steps[s.ID] = s
+
+		switch {
+		case s.Needs != nil:
+			s.deps = s.Needs
+		case i > 0:
+			s.deps = []string{p.Steps[i-1].ID}
+		}
+	}
+
+	for _, s := range p.Steps {
+		for _, dep := range s.deps {
+			if steps[dep] == nil {
+				return fmt.Errorf("step %s needs unknown step %q", s.ID, dep)
+			}
+			if dep == s.ID {
+				return fmt.Errorf("step %s needs itself", s.ID)
+			}
+		}
+	}
+	if err := checkCycles(p.Steps, steps); err != nil {
+		return err
+	}
+
+	for _, s := range p.Steps {
+		if err := s.parseTemplates(upstream(s, steps)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCycles reports a dependency cycle between steps
+func checkCycles(order []*Step, steps map[string]*Step) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(steps))
+	var visit func(s *Step) error
+	visit = func(s *Step) error {
+		switch state[s.ID] {
+		case visiting:
+			return fmt.Errorf("steps depend on each other in a cycle through %s", s.ID)
+		case visited:
+			return nil
+		}
+		state[s.ID] = visiting
+		for _, dep := range s.deps {
+			if err := visit(steps[dep]); err != nil {
+				return err
+			}
+		}
+		state[s.ID] = visited
+		return nil
+	}
+	for _, s := range order {
+		if err := visit(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upstream returns every step s waits for, directly or indirectly
+func upstream(s *Step, steps map[string]*Step) map[string]bool {
+	seen := make(map[string]bool)
+	pending := append([]string(nil), s.deps...)
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		pending = append(pending, steps[id].deps...)
+	}
+	return seen
+}
+
+// templateFuncs are available in step templates
+var templateFuncs = template.FuncMap{
+	// json writes a value as JSON, e.g. to pass structured output on
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// parseTemplates parses the step's arguments, which may only refer to
+// steps in available
+func (s *Step) parseTemplates(available map[string]bool) error {
+	parse := func(label, text string) (*template.Template, error) {
+		for _, m := range stepRef.FindAllStringSubmatch(text, -1) {
+			if !available[m[1]] {
+				return nil, fmt.Errorf("step %s %s uses .steps.%s, which it doesn't wait for (add it to needs)", s.ID, label, m[1])
+			}
+		}
+		t, err := template.New(s.ID).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("step %s %s: %w", s.ID, label, err)
+		}
+		return t, nil
+	}
+
+	s.args = make([]*template.Template, len(s.Args))
+	for i, arg := range s.Args {
+		t, err := parse(fmt.Sprintf("argument %d", i+1), arg)
+		if err != nil {
+			return err
+		}
+		s.args[i] = t
+	}
+
+	s.named = make(map[string]*template.Template, len(s.Named))
+	for _, name := range sortedKeys(s.Named) {
+		t, err := parse("--"+name, s.Named[name])
+		if err != nil {
+			return err
+		}
+		s.named[name] = t
+	}
+	return nil
+}
+
+// Input renders the step's arguments using the finished steps' outputs
+func (s *Step) Input(outputs map[string]Output) (arguments.Input, error) {
+	data := map[string]any{"steps": outputsData(outputs)}
+	render := func(t *template.Template) (string, error) {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("failed to render arguments: %w", err)
+		}
+		return buf.String(), nil
+	}
+
+	var input arguments.Input
+	for _, t := range s.args {
+		arg, err := render(t)
+		if err != nil {
+			return input, err
+		}
+		input.Positional = append(input.Positional, arg)
+	}
+	for name, t := range s.named {
+		value, err := render(t)
+		if err != nil {
+			return input, err
+		}
+		if input.Named == nil {
+			input.Named = make(map[string]string, len(s.named))
+		}
+		input.Named[name] = value
+	}
+	return input, nil
+}
+
+// Output is what a finished step hands to the steps after it
+type Output struct {
+	Result  string // Final result text
+	Value   any    // Structured output, or the result parsed as JSON; nil if neither
+	IsError bool
+}
+
+// NewOutput takes a step's output from its session summary
+func NewOutput(summary *formatter.Summary) Output {
+	out := Output{Result: summary.Result, IsError: summary.IsError}
+	if len(summary.StructuredOutput) > 0 {
+		_ = json.Unmarshal(summary.StructuredOutput, &out.Value)
+	} else if err := json.Unmarshal([]byte(summary.Result), &out.Value); err != nil {
+		out.Value = nil
+	}
+	return out
+}
+
+// outputsData exposes outputs to templates as .steps.<id>.result, .output,
+// and .is_error
+func outputsData(outputs map[string]Output) map[string]any {
+	data := make(map[string]any, len(outputs))
+	for id, out := range outputs {
+		data[id] = map[string]any{
+			"result":   out.Result,
+			"output":   out.Value,
+			"is_error": out.IsError,
+		}
+	}
+	return data
+}
+
+// sortedKeys returns m's keys in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,317 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+func writePipeline(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writePipeline(t, `
+steps:
+  - id: plan
+    skill: planner
+    args: [src]
+  - id: lint
+    skill: linter
+    needs: []
+  - id: review
+    skill: reviewer
+    named:
+      lint: "{{ .steps.lint.result }}"
+  - id: report
+    skill: reporter
+    needs: [review, lint]
+    continue-on-error: true
+`)
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if p.Name != "pipeline" {
+		t.Errorf("Name = %q, want pipeline", p.Name)
+	}
+
+	deps := map[string][]string{}
+	for _, s := range p.Steps {
+		deps[s.ID] = s.Deps()
+	}
+	want := map[string][]string{
+		"plan":   nil,
+		"lint":   {},
+		"review": {"lint"},
+		"report": {"review", "lint"},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("Deps = %v, want %v", deps, want)
+	}
+	if !p.Steps[3].ContinueOnError {
+		t.Error("Expected report to continue on error")
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no steps", "name: empty\n", "no steps"},
+		{"no id", "steps:\n  - skill: a\n", "step 1 has no id"},
+		{"bad id", "steps:\n  - id: my-step\n    skill: a\n", `invalid step id "my-step"`},
+		{"duplicate", "steps:\n  - id: a\n    skill: a\n  - id: a\n    skill: b\n", `duplicate step id "a"`},
+		{"no skill", "steps:\n  - id: a\n", "step a has no skill"},
+		{"unknown need", "steps:\n  - id: a\n    skill: a\n    needs: [b]\n", `step a needs unknown step "b"`},
+		{"self", "steps:\n  - id: a\n    skill: a\n    needs: [a]\n", "step a needs itself"},
+		{"cycle", "steps:\n  - id: a\n    skill: a\n    needs: [b]\n  - id: b\n    skill: b\n", "cycle"},
+		{"not upstream", "steps:\n  - id: a\n    skill: a\n  - id: b\n    skill: b\n    needs: []\n    args: ['{{ .steps.a.result }}']\n", "step b argument 1 uses .steps.a"},
+		{"bad template", "steps:\n  - id: a\n    skill: a\n    named:\n      x: '{{ .steps'\n", "step a --x"},
+		{"bad yaml", "steps: [\n", "failed to parse"},
+		{"misspelled needs", "steps:\n  - id: a\n    skill: a\n  - id: b\n    skill: b\n    need: []\n", "field need not found"},
+		{"misspelled continue-on-error", "steps:\n  - id: a\n    skill: a\n    continue_on_error: true\n", "field continue_on_error not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writePipeline(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStep_Input(t *testing.T) {
+	p, err := Load(writePipeline(t, `
+steps:
+  - id: plan
+    skill: planner
+  - id: fix
+    skill: fixer
+    args: ["{{ .steps.plan.result }}", "{{ range .steps.plan.output.files }}{{ . }} {{ end }}"]
+    named:
+      files: "{{ json .steps.plan.output.files }}"
+`))
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	outputs := map[string]Output{
+		"plan": NewOutput(&formatter.Summary{Result: `{"files": ["a.go", "b.go"]}`}),
+	}
+	got, err := p.Steps[1].Input(outputs)
+	if err != nil {
+		t.Fatalf("Input error: %v", err)
+	}
+	want := arguments.Input{
+		Positional: []string{`{"files": ["a.go", "b.go"]}`, "a.go b.go "},
+		Named:      map[string]string{"files": `["a.go","b.go"]`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Input = %+v, want %+v", got, want)
+	}
+
+	// Text results have no output to index into
+	outputs["plan"] = NewOutput(&formatter.Summary{Result: "just text"})
+	if _, err := p.Steps[1].Input(outputs); err == nil {
+		t.Error("Expected an error rendering output fields of a text result")
+	}
+}
+
+func TestNewOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary formatter.Summary
+		want    any
+	}{
+		{"structured", formatter.Summary{Result: "done", StructuredOutput: json.RawMessage(`{"ok": true}`)}, map[string]any{"ok": true}},
+		{"json result", formatter.Summary{Result: `[1, 2]`}, []any{1.0, 2.0}},
+		{"text", formatter.Summary{Result: "done"}, nil},
+	}
+	for _, tt := range tests {
+		if got := NewOutput(&tt.summary); !reflect.DeepEqual(got.Value, tt.want) {
+			t.Errorf("%s: Value = %#v, want %#v", tt.name, got.Value, tt.want)
+		}
+	}
+}
+
+// session returns stream-json for a run that ends with result
+func session(result string, isError bool) string {
+	data, _ := json.Marshal(result)
+	return fmt.Sprintf(`{"type":"system","subtype":"init","session_id":"s","tools":[],"model":"m","cwd":"/tmp"}
+{"type":"result","subtype":"success","is_error":%t,"result":%s,"session_id":"s"}
+`, isError, data)
+}
+
+func TestRun(t *testing.T) {
+	p, err := Load(writePipeline(t, `
+steps:
+  - id: plan
+    skill: planner
+  - id: left
+    skill: worker
+    args: ["left {{ .steps.plan.result }}"]
+    needs: [plan]
+  - id: right
+    skill: worker
+    args: ["right {{ .steps.plan.result }}"]
+    needs: [plan]
+  - id: join
+    skill: joiner
+    args: ["{{ .steps.left.result }}+{{ .steps.right.result }}"]
+    needs: [left, right]
+`))
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	var mu sync.Mutex
+	var calls []string
+	// left and right wait for each other, so they must run in parallel
+	var both sync.WaitGroup
+	both.Add(2)
+	execute := func(ctx context.Context, step *Step, input arguments.Input, stdout, stderr io.Writer) error {
+		mu.Lock()
+		calls = append(calls, step.ID+": "+strings.Join(input.Positional, " "))
+		mu.Unlock()
+		if step.ID == "left" || step.ID == "right" {
+			both.Done()
+			both.Wait()
+		}
+		result := strings.ToUpper(step.ID)
+		if len(input.Positional) > 0 {
+			result = input.Positional[0]
+		}
+		_, err := io.WriteString(stdout, session(result, false))
+		return err
+	}
+
+	var out bytes.Buffer
+	results := Run(context.Background(), p, Options{Execute: execute, Output: &out, Color: "never"})
+
+	for _, r := range results {
+		if r.Status() != StatusOK {
+			t.Errorf("step %s status = %s (%v)", r.Step.ID, r.Status(), r.Err)
+		}
+	}
+	if got := results[3].Summary.Result; got != "left PLAN+right PLAN" {
+		t.Errorf("join result = %q, want both branches' results", got)
+	}
+	if calls[0] != "plan: " || calls[3] != "join: left PLAN+right PLAN" {
+		t.Errorf("Unexpected calls: %q", calls)
+	}
+
+	// Each step's output stays together
+	got := out.String()
+	for _, id := range []string{"plan", "left", "right", "join"} {
+		header := strings.Index(got, "▸ "+id)
+		footer := strings.Index(got, "✓ "+id)
+		if header < 0 || footer < header {
+			t.Fatalf("Missing output for %s:\n%s", id, got)
+		}
+		if next := strings.Index(got[header+1:], "▸ "); next >= 0 && header+1+next < footer {
+			t.Errorf("Output of %s is interleaved with another step:\n%s", id, got)
+		}
+	}
+}
+
+func TestRun_StopsOnFailure(t *testing.T) {
+	p, err := Load(writePipeline(t, `
+steps:
+  - id: flaky
+    skill: a
+    continue-on-error: true
+  - id: broken
+    skill: b
+  - id: never
+    skill: c
+`))
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	execute := func(ctx context.Context, step *Step, input arguments.Input, stdout, stderr io.Writer) error {
+		switch step.ID {
+		case "flaky":
+			_, err := io.WriteString(stdout, session("tests failed", true))
+			return err
+		case "broken":
+			_, _ = io.WriteString(stderr, "boom\n")
+			return errors.New("exit status 1")
+		}
+		t.Errorf("step %s should not run", step.ID)
+		return nil
+	}
+
+	var out bytes.Buffer
+	results := Run(context.Background(), p, Options{Execute: execute, Output: &out, Color: "never"})
+
+	var statuses []string
+	for _, r := range results {
+		statuses = append(statuses, r.Status())
+	}
+	if want := []string{StatusFailed, StatusFailed, StatusSkipped}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+	if err := results[1].Err; err == nil || err.Error() != "exit status 1: boom" {
+		t.Errorf("error = %v, want stderr included", err)
+	}
+	if got := out.String(); !strings.Contains(got, "✗ flaky") || !strings.Contains(got, "tests failed") || !strings.Contains(got, "- never skipped") {
+		t.Errorf("Unexpected output:\n%s", got)
+	}
+}
+
+func TestView(t *testing.T) {
+	var out bytes.Buffer
+	v := newView(&out)
+	first, second, third := v.open(), v.open(), v.open()
+
+	_, _ = io.WriteString(second, "second\n")
+	_, _ = io.WriteString(first, "first\n")
+	_, _ = io.WriteString(third, "third\n")
+	if got := out.String(); got != "first\n" {
+		t.Errorf("Only the first lane should be live, got %q", got)
+	}
+
+	// A lane that finishes while waiting is flushed once the lanes before it finish
+	second.close()
+	first.close()
+	if got := out.String(); got != "first\nsecond\nthird\n" {
+		t.Errorf("After first closes = %q", got)
+	}
+	_, _ = io.WriteString(third, "more\n")
+	third.close()
+	if got := out.String(); got != "first\nsecond\nthird\nmore\n" {
+		t.Errorf("Third lane should be live, got %q", got)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	if got := formatElapsed(1500 * time.Millisecond); got != "1.5s" {
+		t.Errorf("formatElapsed = %q", got)
+	}
+	if got := formatElapsed(90 * time.Second); got != "1m30s" {
+		t.Errorf("formatElapsed = %q", got)
+	}
+}
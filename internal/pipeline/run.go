@@ -0,0 +1,204 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/formatter"
+)
+
+// ExecuteFunc runs a step's skill with the rendered arguments, writing
+// stream-json to stdout
+type ExecuteFunc func(ctx context.Context, step *Step, input arguments.Input, stdout, stderr io.Writer) error
+
+// Options configures a pipeline run
+type Options struct {
+	Execute   ExecuteFunc
+	Output    io.Writer // Combined formatted view of every step
+	Verbose   bool      // Show thinking and tool details
+	ShowUsage bool      // Show token usage after each step
+	Color     string    // Color mode: "auto", "always", or "never"
+}
+
+// Step states
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped" // Not run because an earlier step failed
+)
+
+// Result is the outcome of a step
+type Result struct {
+	Step    *Step
+	Summary *formatter.Summary // Parsed session; nil if the step didn't run
+	Err     error              // Set when the step couldn't run or its stream failed
+	Skipped bool
+	Elapsed time.Duration
+}
+
+// Status summarizes how the step ended
+func (r *Result) Status() string {
+	switch {
+	case r.Skipped:
+		return StatusSkipped
+	case r.Err != nil, r.Summary == nil, !r.Summary.Completed, r.Summary.IsError:
+		return StatusFailed
+	default:
+		return StatusOK
+	}
+}
+
+// Styles for the lines around each step; colors follow the global profile
+// set by color.ConfigureColorProfile
+var (
+	headerStyle = lipgloss.NewStyle().Bold(true)
+	okStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	failStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	dimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// Run runs the pipeline's steps, each as soon as the steps it needs are
+// done. After a step fails without continue-on-error, running steps finish
+// but no new ones start. Results are returned in pipeline order.
+func Run(ctx context.Context, p *Pipeline, opts Options) []*Result {
+	view := newView(opts.Output)
+	outputs := make(map[string]Output)
+	results := make(map[string]*Result, len(p.Steps))
+	started := make(map[string]bool, len(p.Steps))
+	done := make(chan *Result)
+	running := 0
+	stopped := false
+
+	for {
+		for _, s := range p.Steps {
+			if stopped || started[s.ID] || !ready(s, results) {
+				continue
+			}
+			started[s.ID] = true
+			running++
+
+			// Steps only see the outputs of steps that finished before they start
+			stepOutputs := make(map[string]Output, len(outputs))
+			for id, out := range outputs {
+				stepOutputs[id] = out
+			}
+			l := view.open()
+			go func() {
+				done <- runStep(ctx, s, stepOutputs, l, opts)
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		r := <-done
+		running--
+		results[r.Step.ID] = r
+		if r.Summary != nil {
+			outputs[r.Step.ID] = NewOutput(r.Summary)
+		}
+		if r.Status() != StatusOK && !r.Step.ContinueOnError {
+			stopped = true
+		}
+	}
+
+	ordered := make([]*Result, len(p.Steps))
+	for i, s := range p.Steps {
+		if r, ok := results[s.ID]; ok {
+			ordered[i] = r
+			continue
+		}
+		ordered[i] = &Result{Step: s, Skipped: true}
+		_, _ = fmt.Fprintf(opts.Output, "%s %s %s\n", dimStyle.Render("-"), s.ID, dimStyle.Render(StatusSkipped))
+	}
+	return ordered
+}
+
+// ready reports whether every step s needs has finished
+func ready(s *Step, results map[string]*Result) bool {
+	for _, dep := range s.deps {
+		if results[dep] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// runStep runs a single step, formatting its stream into its lane
+func runStep(ctx context.Context, s *Step, outputs map[string]Output, l *lane, opts Options) *Result {
+	start := time.Now()
+	result := &Result{Step: s}
+	_, _ = fmt.Fprintf(l, "%s %s\n", headerStyle.Render("▸ "+s.ID), dimStyle.Render(s.Skill))
+	defer func() {
+		result.Elapsed = time.Since(start)
+		elapsed := dimStyle.Render(formatElapsed(result.Elapsed))
+		if result.Status() == StatusOK {
+			_, _ = fmt.Fprintf(l, "%s %s %s\n\n", okStyle.Render("✓"), s.ID, elapsed)
+		} else {
+			_, _ = fmt.Fprintf(l, "%s %s %s %s\n\n", failStyle.Render("✗"), s.ID, elapsed, failStyle.Render(failure(result)))
+		}
+		l.close()
+	}()
+
+	input, err := s.Input(outputs)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if ctx.Err() != nil {
+		result.Err = ctx.Err()
+		return result
+	}
+
+	cfg := formatter.FormatterConfig{Output: l, ShowUsage: opts.ShowUsage, Color: opts.Color}
+	var form formatter.Formatter = formatter.NewTerminalFormatter(cfg)
+	if opts.Verbose {
+		form = formatter.NewVerboseTerminalFormatter(cfg)
+	}
+	forward := make(chan formatter.StreamEvent)
+	formatErr := make(chan error, 1)
+	go func() {
+		formatErr <- form.Format(forward)
+	}()
+
+	parser := formatter.NewStreamParser("", "", opts.Verbose) // The step header names the skill
+	execute := func(stdout, stderr io.Writer) error {
+		return opts.Execute(ctx, s, input, stdout, stderr)
+	}
+	result.Summary, result.Err = formatter.RunStream(parser, execute, func(event formatter.StreamEvent) {
+		forward <- event
+	})
+	close(forward)
+	if err := <-formatErr; err != nil && result.Err == nil {
+		result.Err = fmt.Errorf("formatting failed: %w", err)
+	}
+	return result
+}
+
+// failure returns a one-line reason a step didn't succeed
+func failure(r *Result) string {
+	var msg string
+	switch {
+	case r.Err != nil:
+		msg = r.Err.Error()
+	case r.Summary != nil && r.Summary.IsError:
+		msg = r.Summary.Result
+	default:
+		msg = "no result"
+	}
+	msg, _, _ = strings.Cut(strings.TrimSpace(msg), "\n")
+	return msg
+}
+
+// formatElapsed formats a step's duration
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// view combines the formatted output of steps running at the same time.
+// The earliest started step writes straight through; the others buffer
+// until every step started before them has finished, so each step's output
+// stays together and a sequential pipeline streams live.
+type view struct {
+	mu     sync.Mutex
+	w      io.Writer
+	active []*lane // In start order; the first is live
+}
+
+// lane is one step's share of the view
+type lane struct {
+	v        *view
+	buf      bytes.Buffer
+	live     bool
+	finished bool
+}
+
+func newView(w io.Writer) *view {
+	return &view{w: w}
+}
+
+// open adds a lane for a step that's starting
+func (v *view) open() *lane {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	l := &lane{v: v, live: len(v.active) == 0}
+	v.active = append(v.active, l)
+	return l
+}
+
+// Write writes to the view when the lane is live, and buffers otherwise
+func (l *lane) Write(p []byte) (int, error) {
+	l.v.mu.Lock()
+	defer l.v.mu.Unlock()
+	if l.live {
+		return l.v.w.Write(p)
+	}
+	return l.buf.Write(p)
+}
+
+// close marks the lane's step finished and hands the view to the next lane
+func (l *lane) close() {
+	v := l.v
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	l.finished = true
+	if !l.live {
+		return
+	}
+
+	// Flush steps that finished while waiting, then go live with the next
+	for len(v.active) > 0 && v.active[0].finished {
+		head := v.active[0]
+		v.active = v.active[1:]
+		if !head.live {
+			_, _ = v.w.Write(head.buf.Bytes())
+		}
+	}
+	if len(v.active) > 0 {
+		next := v.active[0]
+		_, _ = v.w.Write(next.buf.Bytes())
+		next.buf.Reset()
+		next.live = true
+	}
+}
@@ -0,0 +1,291 @@
+// Package policy decides whether Claude may use a tool, based on allow, ask,
+// and deny rules in skillet policy files. Rules use Claude Code's permission
+// rule syntax, such as "Bash(rm:*)" or "Write(/etc/**)".
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/toolrule"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the policy file name inside a .claude directory
+const FileName = "skillet-policy.yaml"
+
+// Decision is the outcome of evaluating a tool use
+type Decision string
+
+// Decisions, in order of precedence: deny beats ask beats allow
+const (
+	Deny  Decision = "deny"
+	Ask   Decision = "ask"
+	Allow Decision = "allow"
+)
+
+// strictness ranks decisions so defaults from later files can only tighten
+var strictness = map[Decision]int{Allow: 0, Ask: 1, Deny: 2}
+
+// File is the format of a policy file
+type File struct {
+	Default Decision `yaml:"default,omitempty"` // Decision when no rule matches (default allow)
+	Allow   []string `yaml:"allow,omitempty"`
+	Ask     []string `yaml:"ask,omitempty"`
+	Deny    []string `yaml:"deny,omitempty"`
+}
+
+// Policy is the combined rules of the user and project policy files
+type Policy struct {
+	workDir       string
+	defaultResult Result
+	rules         map[Decision][]rule
+}
+
+// rule is a parsed rule and the file it came from
+type rule struct {
+	rule   toolrule.Rule
+	source string
+}
+
+// Result is a decision and the rule that made it
+type Result struct {
+	Decision Decision
+	Rule     string // Matching rule; empty when the default applied
+	Source   string // Policy file of the rule or default; empty for the built-in default
+}
+
+// Reason describes why the decision was made, for messages to Claude and the user
+func (r Result) Reason() string {
+	switch {
+	case r.Rule != "":
+		return fmt.Sprintf("skillet policy rule %s in %s", r.Rule, r.Source)
+	case r.Source != "":
+		return fmt.Sprintf("skillet policy default in %s", r.Source)
+	default:
+		return "skillet policy default"
+	}
+}
+
+// Implicit reports whether the result is the built-in default, meaning
+// no policy file had a rule or default for the tool use
+func (r Result) Implicit() bool {
+	return r.Rule == "" && r.Source == ""
+}
+
+// Paths returns the policy files for a working directory, lowest priority first:
+// ~/.claude/skillet-policy.yaml, then <workDir>/.claude/skillet-policy.yaml
+func Paths(workDir string) ([]string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return []string{
+		filepath.Join(home, ".claude", FileName),
+		filepath.Join(workDir, ".claude", FileName),
+	}, nil
+}
+
+// Load reads the user and project policy files for a working directory
+func Load(workDir string) (*Policy, error) {
+	paths, err := Paths(workDir)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFiles(workDir, paths...)
+}
+
+// LoadFiles combines policy files, lowest priority first. Missing files are
+// skipped. Rules from every file apply, and the strictest default wins, so a
+// project file can't loosen the user's default.
+func LoadFiles(workDir string, paths ...string) (*Policy, error) {
+	p := &Policy{
+		workDir:       workDir,
+		defaultResult: Result{Decision: Allow},
+		rules:         make(map[Decision][]rule),
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if abs, err := filepath.Abs(path); err == nil {
+			if seen[abs] {
+				continue
+			}
+			seen[abs] = true
+		}
+
+		f, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			continue
+		}
+		if err := p.add(f, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// readFile parses a policy file, returning nil if it doesn't exist
+func readFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	f := &File{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// add merges a policy file's rules into the policy
+func (p *Policy) add(f *File, source string) error {
+	switch f.Default {
+	case "":
+	case Allow, Ask, Deny:
+		if p.defaultResult.Implicit() || strictness[f.Default] > strictness[p.defaultResult.Decision] {
+			p.defaultResult = Result{Decision: f.Default, Source: source}
+		}
+	default:
+		return fmt.Errorf("%s: invalid default %q (must be allow, ask, or deny)", source, f.Default)
+	}
+
+	for decision, list := range map[Decision][]string{Allow: f.Allow, Ask: f.Ask, Deny: f.Deny} {
+		for _, s := range list {
+			r, err := toolrule.Parse(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", source, err)
+			}
+			p.rules[decision] = append(p.rules[decision], rule{rule: r, source: source})
+		}
+	}
+	return nil
+}
+
+// Evaluate decides whether a tool may be used with the given input.
+// Deny and ask rules match if any command in a Bash command line matches,
+// including those in subshells and command substitutions; allow rules must
+// match every one.
+func (p *Policy) Evaluate(tool string, input map[string]any) Result {
+	key, target := toolrule.InputTarget(input)
+	targets := []string{target}
+	if key == "command" {
+		targets = splitCommand(target)
+	}
+
+	for _, decision := range []Decision{Deny, Ask} {
+		for _, t := range targets {
+			if r, ok := p.match(decision, tool, key, t); ok {
+				return Result{Decision: decision, Rule: r.rule.String(), Source: r.source}
+			}
+		}
+	}
+
+	var allowed *rule
+	for _, t := range targets {
+		r, ok := p.match(Allow, tool, key, t)
+		if !ok {
+			allowed = nil
+			break
+		}
+		allowed = &r
+	}
+	if allowed != nil {
+		return Result{Decision: Allow, Rule: allowed.rule.String(), Source: allowed.source}
+	}
+
+	return p.defaultResult
+}
+
+// match returns the first rule for a decision that matches a tool use
+func (p *Policy) match(decision Decision, tool, key, target string) (rule, bool) {
+	for _, r := range p.rules[decision] {
+		if p.matches(r.rule, tool, key, target) {
+			return r, true
+		}
+	}
+	return rule{}, false
+}
+
+// matches checks a rule against a tool use. Path specifiers starting with
+// "/" or "~/" match absolute paths; others match paths relative to the
+// working directory.
+func (p *Policy) matches(r toolrule.Rule, tool, key, target string) bool {
+	if r.Tool != tool {
+		return false
+	}
+	if r.Specifier == "" || !strings.HasSuffix(key, "path") {
+		return r.Matches(tool, target)
+	}
+
+	// Clean every path so ".." can't step out of an allowed directory or
+	// around a denied one
+	abs := target
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(p.workDir, abs)
+	}
+	abs = filepath.Clean(abs)
+
+	spec := r.Specifier
+	if rest, ok := strings.CutPrefix(spec, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			spec = filepath.ToSlash(home) + "/" + rest
+		}
+	}
+	if filepath.IsAbs(spec) {
+		return toolrule.Rule{Tool: tool, Specifier: spec}.Matches(tool, abs)
+	}
+
+	rel, err := filepath.Rel(p.workDir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return false
+	}
+	return r.Matches(tool, filepath.ToSlash(rel))
+}
+
+// splitCommand splits a shell command into the commands it runs: on &&, ||,
+// &, ;, | and newlines, and around subshells, $(...) and backticks. Redirects
+// like 2>&1 aren't split.
+func splitCommand(command string) []string {
+	var parts []string
+	var part strings.Builder
+	flush := func() {
+		if p := strings.TrimSpace(part.String()); p != "" {
+			parts = append(parts, p)
+		}
+		part.Reset()
+	}
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == '&' && (i > 0 && (command[i-1] == '>' || command[i-1] == '<') || i+1 < len(command) && command[i+1] == '>'):
+			part.WriteByte(c)
+		case c == '$' && i+1 < len(command) && command[i+1] == '(':
+			flush()
+		case strings.IndexByte("&|;\n()`", c) >= 0:
+			flush()
+		default:
+			part.WriteByte(c)
+		}
+	}
+	flush()
+	if len(parts) == 0 {
+		return []string{command}
+	}
+	return parts
+}
@@ -0,0 +1,181 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePolicy writes a policy file and returns its path
+func writePolicy(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, ".claude", FileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEvaluate(t *testing.T) {
+	workDir := t.TempDir()
+	path := writePolicy(t, workDir, `
+allow:
+  - Bash(git status)
+  - Bash(go test:*)
+  - Read
+  - Write(`+workDir+`/src/**)
+ask:
+  - Bash(git push:*)
+deny:
+  - Bash(rm:*)
+  - Write(/etc/**)
+  - Edit(secrets/**)
+`)
+	p, err := LoadFiles(workDir, path)
+	if err != nil {
+		t.Fatalf("LoadFiles() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		tool  string
+		input map[string]any
+		want  Decision
+		rule  string
+	}{
+		{"denied command", "Bash", map[string]any{"command": "rm -rf build"}, Deny, "Bash(rm:*)"},
+		{"denied in compound command", "Bash", map[string]any{"command": "go test ./... && rm -rf build"}, Deny, "Bash(rm:*)"},
+		{"denied in background command", "Bash", map[string]any{"command": "ls & rm -rf x"}, Deny, "Bash(rm:*)"},
+		{"denied in command substitution", "Bash", map[string]any{"command": "echo $(rm -rf x)"}, Deny, "Bash(rm:*)"},
+		{"denied in backticks", "Bash", map[string]any{"command": "echo `rm x`"}, Deny, "Bash(rm:*)"},
+		{"denied in subshell", "Bash", map[string]any{"command": "(rm x)"}, Deny, "Bash(rm:*)"},
+		{"allowed command with redirect", "Bash", map[string]any{"command": "go test ./... 2>&1"}, Allow, "Bash(go test:*)"},
+		{"ask command", "Bash", map[string]any{"command": "git push origin main"}, Ask, "Bash(git push:*)"},
+		{"allowed command", "Bash", map[string]any{"command": "go test ./..."}, Allow, "Bash(go test:*)"},
+		{"allowed compound command", "Bash", map[string]any{"command": "git status; go test ./..."}, Allow, "Bash(go test:*)"},
+		{"partly allowed compound command uses default", "Bash", map[string]any{"command": "go test ./... | tee out"}, Allow, ""},
+		{"denied absolute path", "Write", map[string]any{"file_path": "/etc/hosts"}, Deny, "Write(/etc/**)"},
+		{"denied path through ..", "Write", map[string]any{"file_path": "/tmp/../etc/passwd"}, Deny, "Write(/etc/**)"},
+		{"allowed directory escaped with ..", "Write", map[string]any{"file_path": workDir + "/src/" + strings.Repeat("../", strings.Count(workDir, "/")+1) + "etc/passwd"}, Deny, "Write(/etc/**)"},
+		{"allowed absolute path", "Write", map[string]any{"file_path": filepath.Join(workDir, "src", "main.go")}, Allow, "Write(" + workDir + "/src/**)"},
+		{"relative rule matches absolute path in workdir", "Edit", map[string]any{"file_path": filepath.Join(workDir, "secrets", "key")}, Deny, "Edit(secrets/**)"},
+		{"relative rule matches relative path", "Edit", map[string]any{"file_path": "secrets/key"}, Deny, "Edit(secrets/**)"},
+		{"relative rule ignores paths outside workdir", "Edit", map[string]any{"file_path": "/tmp/secrets/key"}, Allow, ""},
+		{"tool without specifier", "Read", map[string]any{"file_path": "/etc/hosts"}, Allow, "Read"},
+		{"unmatched tool", "WebFetch", map[string]any{"url": "https://example.com"}, Allow, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Evaluate(tt.tool, tt.input)
+			if got.Decision != tt.want {
+				t.Errorf("Decision = %q, want %q", got.Decision, tt.want)
+			}
+			if got.Rule != tt.rule {
+				t.Errorf("Rule = %q, want %q", got.Rule, tt.rule)
+			}
+			if tt.rule != "" && got.Source != path {
+				t.Errorf("Source = %q, want %q", got.Source, path)
+			}
+		})
+	}
+}
+
+func TestLoadFiles_Layers(t *testing.T) {
+	userDir := t.TempDir()
+	workDir := t.TempDir()
+	userPath := writePolicy(t, userDir, "default: ask\ndeny:\n  - Bash(sudo:*)\n")
+	projectPath := writePolicy(t, workDir, "default: deny\nallow:\n  - Read\n")
+
+	p, err := LoadFiles(workDir, userPath, projectPath)
+	if err != nil {
+		t.Fatalf("LoadFiles() error = %v", err)
+	}
+
+	if got := p.Evaluate("Bash", map[string]any{"command": "sudo ls"}); got.Decision != Deny || got.Source != userPath {
+		t.Errorf("user rule = %+v, want deny from %s", got, userPath)
+	}
+	if got := p.Evaluate("Read", map[string]any{"file_path": "x"}); got.Decision != Allow {
+		t.Errorf("project rule = %+v, want allow", got)
+	}
+	got := p.Evaluate("Write", map[string]any{"file_path": "x"})
+	if got.Decision != Deny || got.Source != projectPath {
+		t.Errorf("default = %+v, want project default deny", got)
+	}
+	if !strings.Contains(got.Reason(), "default in "+projectPath) {
+		t.Errorf("Reason() = %q", got.Reason())
+	}
+}
+
+func TestLoadFiles_DefaultOnlyTightens(t *testing.T) {
+	userPath := writePolicy(t, t.TempDir(), "default: deny\n")
+	workDir := t.TempDir()
+	projectPath := writePolicy(t, workDir, "default: allow\n")
+
+	p, err := LoadFiles(workDir, userPath, projectPath)
+	if err != nil {
+		t.Fatalf("LoadFiles() error = %v", err)
+	}
+	got := p.Evaluate("Write", map[string]any{"file_path": "x"})
+	if got.Decision != Deny || got.Source != userPath {
+		t.Errorf("default = %+v, want user default deny from %s", got, userPath)
+	}
+}
+
+func TestLoadFiles_Missing(t *testing.T) {
+	p, err := LoadFiles(t.TempDir(), filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFiles() error = %v", err)
+	}
+	got := p.Evaluate("Bash", map[string]any{"command": "rm -rf /"})
+	if got.Decision != Allow || got.Reason() != "skillet policy default" {
+		t.Errorf("Evaluate() = %+v, want built-in allow", got)
+	}
+}
+
+func TestLoadFiles_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid rule", "deny:\n  - Bash(rm\n", "missing ')'"},
+		{"invalid default", "default: maybe\n", `invalid default "maybe"`},
+		{"unknown field", "block:\n  - Bash\n", "field block not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writePolicy(t, dir, tt.content)
+			_, err := LoadFiles(dir, path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFiles() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"cd src && go test ./... || echo failed; make | tee log", []string{"cd src", "go test ./...", "echo failed", "make", "tee log"}},
+		{"sleep 1 & make", []string{"sleep 1", "make"}},
+		{"echo $(date) `whoami`", []string{"echo", "date", "whoami"}},
+		{"(cd src; make)", []string{"cd src", "make"}},
+		{"make >out 2>&1 &>log", []string{"make >out 2>&1 &>log"}},
+		{"echo ${HOME}", []string{"echo ${HOME}"}},
+	}
+	for _, tt := range tests {
+		got := splitCommand(tt.command)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
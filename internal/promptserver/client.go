@@ -8,9 +8,13 @@ import (
 	"time"
 )
 
+// questionTimeout matches the MCP tool call timeout
+const questionTimeout = 60 * time.Second
+
 // Client connects to the parent prompt server via Unix socket
 type Client struct {
 	socketPath string
+	timeout    time.Duration // Deadline for questions; permission requests wait for the human
 }
 
 // NewClient creates a client that connects to the prompt server
@@ -20,16 +24,44 @@ func NewClient() *Client {
 	if socketPath == "" {
 		return nil
 	}
-	return &Client{socketPath: socketPath}
+	return &Client{socketPath: socketPath, timeout: questionTimeout}
 }
 
 // NewClientWithPath creates a client with an explicit socket path
 func NewClientWithPath(socketPath string) *Client {
-	return &Client{socketPath: socketPath}
+	return &Client{socketPath: socketPath, timeout: questionTimeout}
 }
 
 // AskUserQuestion sends questions to the parent and returns answers
 func (c *Client) AskUserQuestion(questions []Question) (map[string]string, error) {
+	resp, err := c.send(Request{
+		Type:      TypeAskUserQuestion,
+		Questions: questions,
+	}, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Answers, nil
+}
+
+// RequestPermission asks the parent to approve a tool use and returns
+// the decision: AllowOnce, AllowAlways, or Deny. It has no deadline, since
+// the agent is blocked on the answer however long the human takes; it ends
+// when the parent answers or goes away.
+func (c *Client) RequestPermission(req PermissionRequest) (string, error) {
+	resp, err := c.send(Request{
+		Type:       TypePermissionRequest,
+		Permission: &req,
+	}, 0)
+	if err != nil {
+		return "", err
+	}
+	return resp.Decision, nil
+}
+
+// send sends a request to the parent and waits for a successful response,
+// for at most timeout when it's nonzero
+func (c *Client) send(req Request, timeout time.Duration) (*Response, error) {
 	// Connect to socket with timeout
 	conn, err := net.DialTimeout("unix", c.socketPath, 5*time.Second)
 	if err != nil {
@@ -37,16 +69,14 @@ func (c *Client) AskUserQuestion(questions []Question) (map[string]string, error
 	}
 	defer func() { _ = conn.Close() }()
 
-	// Set deadline for the entire operation (60 seconds to match MCP timeout)
-	if err := conn.SetDeadline(time.Now().Add(60 * time.Second)); err != nil {
-		return nil, fmt.Errorf("failed to set deadline: %w", err)
+	// Set deadline for the entire operation
+	if timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, fmt.Errorf("failed to set deadline: %w", err)
+		}
 	}
 
 	// Send request
-	req := Request{
-		Type:      "ask_user_question",
-		Questions: questions,
-	}
 	encoder := json.NewEncoder(conn)
 	if err := encoder.Encode(req); err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -63,5 +93,5 @@ func (c *Client) AskUserQuestion(questions []Question) (map[string]string, error
 		return nil, fmt.Errorf("prompt failed: %s", resp.Error)
 	}
 
-	return resp.Answers, nil
+	return &resp, nil
 }
@@ -0,0 +1,61 @@
+package promptserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// slowServer answers each request successfully after delay
+func slowServer(t *testing.T, delay time.Duration) string {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "slow.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				var req Request
+				if err := json.NewDecoder(conn).Decode(&req); err != nil {
+					return
+				}
+				time.Sleep(delay)
+				_ = json.NewEncoder(conn).Encode(Response{Success: true, Decision: AllowOnce})
+			}()
+		}
+	}()
+	return socketPath
+}
+
+func TestClient_Timeouts(t *testing.T) {
+	client := NewClientWithPath(slowServer(t, 200*time.Millisecond))
+	client.timeout = 50 * time.Millisecond
+
+	// A question that isn't answered in time fails
+	_, err := client.AskUserQuestion([]Question{{Question: "Continue?"}})
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("AskUserQuestion() error = %v, want deadline exceeded", err)
+	}
+
+	// A permission request waits for the human however long they take
+	decision, err := client.RequestPermission(PermissionRequest{ToolName: "Bash"})
+	if err != nil {
+		t.Fatalf("RequestPermission() error = %v", err)
+	}
+	if decision != AllowOnce {
+		t.Errorf("RequestPermission() = %q, want %q", decision, AllowOnce)
+	}
+}
@@ -0,0 +1,137 @@
+package promptserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/martinemde/skillet/internal/toolrule"
+)
+
+// maxWriteLines is how much of a Write's content is shown for approval
+const maxWriteLines = 20
+
+// handlePermission asks the user to approve a tool use, unless an earlier
+// "allow always" already covers it
+func (s *Server) handlePermission(req *PermissionRequest) (string, error) {
+	if req == nil {
+		return "", fmt.Errorf("missing permission request")
+	}
+
+	key := approvalKey(req)
+	if s.approved[key] {
+		return AllowAlways, nil
+	}
+	if !s.interactive() {
+		return "", fmt.Errorf("no terminal to ask for approval")
+	}
+
+	decision := s.promptPermission(req)
+	if decision == AllowAlways {
+		s.approved[key] = true
+	}
+	return decision, nil
+}
+
+// promptPermission shows the tool use and asks to allow or deny it.
+// Aborting the form denies the tool.
+func (s *Server) promptPermission(req *PermissionRequest) string {
+	decision := Deny
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("Allow %s?", req.ToolName)).
+				Description(renderPermission(req)).
+				Options(
+					huh.NewOption("Allow once", AllowOnce),
+					huh.NewOption("Allow always for this run", AllowAlways),
+					huh.NewOption("Deny", Deny),
+				).
+				Value(&decision),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return Deny
+	}
+	return decision
+}
+
+// approvalKey identifies the uses an "allow always" approval covers:
+// the same tool with the same command, path, or other key input
+func approvalKey(req *PermissionRequest) string {
+	_, target := toolrule.InputTarget(req.Input)
+	return toolrule.Rule{Tool: req.ToolName, Specifier: target}.String()
+}
+
+// renderPermission describes a tool use for approval: the command for Bash,
+// a diff for edits, and the input for other tools
+func renderPermission(req *PermissionRequest) string {
+	var b strings.Builder
+	if req.Reason != "" {
+		b.WriteString(req.Reason + "\n\n")
+	}
+
+	input := req.Input
+	path, _ := input["file_path"].(string)
+
+	switch req.ToolName {
+	case "Bash":
+		command, _ := input["command"].(string)
+		if description, _ := input["description"].(string); description != "" {
+			b.WriteString(description + "\n")
+		}
+		b.WriteString("$ " + command)
+	case "Edit":
+		b.WriteString(path + "\n")
+		writeDiff(&b, input)
+	case "MultiEdit":
+		b.WriteString(path)
+		edits, _ := input["edits"].([]any)
+		for _, e := range edits {
+			if edit, ok := e.(map[string]any); ok {
+				b.WriteString("\n")
+				writeDiff(&b, edit)
+			}
+		}
+	case "Write":
+		content, _ := input["content"].(string)
+		lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+		b.WriteString(path)
+		for i, line := range lines {
+			if i == maxWriteLines {
+				fmt.Fprintf(&b, "\n… %d more lines", len(lines)-maxWriteLines)
+				break
+			}
+			b.WriteString("\n+ " + line)
+		}
+	default:
+		data, err := json.MarshalIndent(input, "", "  ")
+		if err != nil {
+			return b.String()
+		}
+		b.Write(data)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// writeDiff writes an old_string/new_string replacement as removed and added lines
+func writeDiff(b *strings.Builder, edit map[string]any) {
+	oldString, _ := edit["old_string"].(string)
+	newString, _ := edit["new_string"].(string)
+	for _, line := range strings.Split(oldString, "\n") {
+		b.WriteString("- " + line + "\n")
+	}
+	for _, line := range strings.Split(newString, "\n") {
+		b.WriteString("+ " + line + "\n")
+	}
+}
+
+// stdinIsTerminal reports whether stdin is a terminal the user can answer from
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
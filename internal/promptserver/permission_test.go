@@ -0,0 +1,99 @@
+package promptserver
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderPermission(t *testing.T) {
+	tests := []struct {
+		name string
+		req  PermissionRequest
+		want string
+	}{
+		{
+			name: "bash",
+			req: PermissionRequest{
+				ToolName: "Bash",
+				Input:    map[string]any{"command": "go test ./...", "description": "Run tests"},
+				Reason:   "Requires approval by skillet policy rule Bash(go:*)",
+			},
+			want: "Requires approval by skillet policy rule Bash(go:*)\n\nRun tests\n$ go test ./...",
+		},
+		{
+			name: "edit",
+			req: PermissionRequest{
+				ToolName: "Edit",
+				Input:    map[string]any{"file_path": "main.go", "old_string": "a\nb", "new_string": "c"},
+			},
+			want: "main.go\n- a\n- b\n+ c",
+		},
+		{
+			name: "other tool",
+			req: PermissionRequest{
+				ToolName: "WebFetch",
+				Input:    map[string]any{"url": "https://example.com"},
+			},
+			want: "{\n  \"url\": \"https://example.com\"\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderPermission(&tt.req); got != tt.want {
+				t.Errorf("renderPermission() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderPermission_LongWrite(t *testing.T) {
+	content := strings.Repeat("line\n", maxWriteLines+5)
+	got := renderPermission(&PermissionRequest{
+		ToolName: "Write",
+		Input:    map[string]any{"file_path": "out.txt", "content": content},
+	})
+	if !strings.HasSuffix(got, "… 5 more lines") {
+		t.Errorf("renderPermission() = %q, want truncated content", got)
+	}
+}
+
+func TestRequestPermission(t *testing.T) {
+	s, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.socketPath = filepath.Join(t.TempDir(), "prompt.sock")
+	s.interactive = func() bool { return false }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	client := NewClientWithPath(s.SocketPath())
+	req := PermissionRequest{ToolName: "Bash", Input: map[string]any{"command": "make"}}
+
+	if _, err := client.RequestPermission(req); err == nil || !strings.Contains(err.Error(), "no terminal") {
+		t.Errorf("RequestPermission() error = %v, want no terminal", err)
+	}
+
+	// An earlier "allow always" answers without prompting
+	s.approved[approvalKey(&req)] = true
+	decision, err := client.RequestPermission(req)
+	if err != nil {
+		t.Fatalf("RequestPermission() error = %v", err)
+	}
+	if decision != AllowAlways {
+		t.Errorf("RequestPermission() = %q, want %q", decision, AllowAlways)
+	}
+
+	other := PermissionRequest{ToolName: "Bash", Input: map[string]any{"command": "make install"}}
+	if _, err := client.RequestPermission(other); err == nil {
+		t.Error("RequestPermission() for a different command should not be approved")
+	}
+}
@@ -5,17 +5,39 @@ package promptserver
 // SocketEnvVar is the environment variable name for the socket path
 const SocketEnvVar = "SKILLET_PROMPT_SOCK"
 
+// Request types
+const (
+	TypeAskUserQuestion   = "ask_user_question"
+	TypePermissionRequest = "permission_request"
+)
+
+// Permission decisions
+const (
+	AllowOnce   = "allow_once"
+	AllowAlways = "allow_always" // Allow matching uses for the rest of the run
+	Deny        = "deny"
+)
+
 // Request represents a prompt request from the MCP server to the parent
 type Request struct {
-	Type      string     `json:"type"`      // "ask_user_question"
-	Questions []Question `json:"questions"` // questions to ask
+	Type       string             `json:"type"`                 // "ask_user_question" or "permission_request"
+	Questions  []Question         `json:"questions,omitempty"`  // questions to ask
+	Permission *PermissionRequest `json:"permission,omitempty"` // tool use to approve
 }
 
 // Response represents the parent's response to a prompt request
 type Response struct {
-	Success bool              `json:"success"`
-	Answers map[string]string `json:"answers,omitempty"` // question -> answer
-	Error   string            `json:"error,omitempty"`
+	Success  bool              `json:"success"`
+	Answers  map[string]string `json:"answers,omitempty"`  // question -> answer
+	Decision string            `json:"decision,omitempty"` // permission decision
+	Error    string            `json:"error,omitempty"`
+}
+
+// PermissionRequest asks the user to approve a tool use
+type PermissionRequest struct {
+	ToolName string         `json:"tool_name"`
+	Input    map[string]any `json:"input"`
+	Reason   string         `json:"reason,omitempty"` // Why approval is needed, e.g. a policy rule
 }
 
 // Question represents a single question from AskUserQuestion
@@ -19,6 +19,11 @@ type Server struct {
 	listener   net.Listener
 	mu         sync.Mutex
 	done       chan struct{}
+
+	// approved holds the tool uses allowed for the rest of the run
+	approved map[string]bool
+	// interactive reports whether the user can be prompted
+	interactive func() bool
 }
 
 // New creates a new prompt server with a unique socket path
@@ -27,8 +32,10 @@ func New() (*Server, error) {
 	socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("skillet-%d.sock", os.Getpid()))
 
 	return &Server{
-		socketPath: socketPath,
-		done:       make(chan struct{}),
+		socketPath:  socketPath,
+		done:        make(chan struct{}),
+		approved:    make(map[string]bool),
+		interactive: stdinIsTerminal,
 	}, nil
 }
 
@@ -106,13 +113,20 @@ func (s *Server) handleConnection(conn net.Conn) {
 	// Handle request
 	var resp Response
 	switch req.Type {
-	case "ask_user_question":
+	case TypeAskUserQuestion:
 		answers, err := s.promptUser(req.Questions)
 		if err != nil {
 			resp = Response{Success: false, Error: err.Error()}
 		} else {
 			resp = Response{Success: true, Answers: answers}
 		}
+	case TypePermissionRequest:
+		decision, err := s.handlePermission(req.Permission)
+		if err != nil {
+			resp = Response{Success: false, Error: err.Error()}
+		} else {
+			resp = Response{Success: true, Decision: decision}
+		}
 	default:
 		resp = Response{Success: false, Error: fmt.Sprintf("unknown request type: %s", req.Type)}
 	}
@@ -0,0 +1,362 @@
+package resolver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// manifestFileName lists the files of a skill bundle, next to SKILL.md
+	manifestFileName = "bundle.json"
+
+	maxBundleSize          = 10 * 1024 * 1024 // Archive download limit
+	maxBundleExtractedSize = 50 * 1024 * 1024 // Total size of unpacked files
+	maxBundleFileSize      = 1024 * 1024      // Limit for each file listed in a manifest
+)
+
+// bundleManifest is the format of bundle.json
+type bundleManifest struct {
+	Files []string `json:"files"` // Paths relative to SKILL.md, e.g. "scripts/run.sh"
+}
+
+// isArchiveURL reports whether a URL points to a .tar.gz, .tgz, or .zip bundle
+func isArchiveURL(u *url.URL) bool {
+	p := strings.ToLower(u.Path)
+	return strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".tgz") || strings.HasSuffix(p, ".zip")
+}
+
+// resolveArchive downloads and unpacks a skill bundle archive. The URL
+// fragment selects a skill directory or file inside the archive; without one,
+// the archive must contain exactly one SKILL.md.
+func (r *Resolver) resolveArchive(u *url.URL) (*ResolveResult, error) {
+	download := *u
+	download.Fragment = ""
+	data, _, err := r.fetch(download.String(), maxBundleSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBundleSize {
+		return nil, fmt.Errorf("bundle too large: must be ≤%dMB", maxBundleSize/(1024*1024))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "skillet-bundle-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+
+	// Unpack into a directory named after the archive so a SKILL.md at the
+	// archive root gets the archive's name
+	root := filepath.Join(tmpDir, archiveName(u.Path))
+	if strings.HasSuffix(strings.ToLower(u.Path), ".zip") {
+		err = extractZip(data, root)
+	} else {
+		err = extractTarGz(data, root)
+	}
+	if err == nil {
+		var result *ResolveResult
+		result, err = findBundleResource(root, u.Fragment)
+		if err == nil {
+			result.IsURL = true
+			result.SHA256 = hashBytes(data)
+			result.cleanupPath = tmpDir
+			return result, nil
+		}
+	}
+
+	_ = os.RemoveAll(tmpDir)
+	return nil, err
+}
+
+// archiveName returns the archive file name without its extension
+func archiveName(urlPath string) string {
+	name := path.Base(urlPath)
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if len(name) > len(ext) && strings.EqualFold(name[len(name)-len(ext):], ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return "bundle"
+}
+
+// findBundleResource finds the skill or command in an unpacked bundle or
+// git checkout. The selector is a directory or file path inside root.
+func findBundleResource(root, selector string) (*ResolveResult, error) {
+	if selector != "" {
+		target, err := bundlePath(root, selector)
+		if err != nil {
+			return nil, err
+		}
+		// Archives like GitHub's wrap everything in one top-level directory
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			if entries, _ := os.ReadDir(root); len(entries) == 1 && entries[0].IsDir() {
+				target = filepath.Join(root, entries[0].Name(), filepath.FromSlash(path.Clean(selector)))
+			}
+		}
+
+		info, err := os.Stat(target)
+		if err != nil {
+			return nil, fmt.Errorf("%s not found", selector)
+		}
+		if info.IsDir() {
+			target = filepath.Join(target, skillFileName)
+			if _, err := os.Stat(target); err != nil {
+				return nil, fmt.Errorf("no %s in %s", skillFileName, selector)
+			}
+		}
+		resourceType := ResourceTypeCommand
+		if filepath.Base(target) == skillFileName {
+			resourceType = ResourceTypeSkill
+		}
+		return &ResolveResult{Path: target, Type: resourceType}, nil
+	}
+
+	var skills []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == skillFileName {
+			skills = append(skills, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	switch len(skills) {
+	case 0:
+		return nil, fmt.Errorf("no %s found", skillFileName)
+	case 1:
+		return &ResolveResult{Path: skills[0], Type: ResourceTypeSkill}, nil
+	default:
+		dirs := make([]string, len(skills))
+		for i, s := range skills {
+			rel, _ := filepath.Rel(root, filepath.Dir(s))
+			dirs[i] = filepath.ToSlash(rel)
+		}
+		sort.Strings(dirs)
+		return nil, fmt.Errorf("found %d skills, select one of: %s", len(skills), strings.Join(dirs, ", "))
+	}
+}
+
+// bundlePath joins a relative path from a bundle onto dir, rejecting paths
+// that would escape it
+func bundlePath(dir, name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid path in bundle: %s", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
+
+// extractor writes bundle files while enforcing the extracted size limit
+type extractor struct {
+	root    string
+	written int64
+}
+
+// file writes one regular file from a bundle
+func (e *extractor) file(name string, mode fs.FileMode, r io.Reader) error {
+	target, err := bundlePath(e.root, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to unpack bundle: %w", err)
+	}
+
+	perm := os.FileMode(0o644)
+	if mode&0o111 != 0 {
+		perm = 0o755
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to unpack bundle: %w", err)
+	}
+
+	n, err := io.Copy(f, io.LimitReader(r, maxBundleExtractedSize-e.written+1))
+	e.written += n
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unpack bundle: %w", err)
+	}
+	if e.written > maxBundleExtractedSize {
+		return fmt.Errorf("bundle too large: unpacked files must be ≤%dMB", maxBundleExtractedSize/(1024*1024))
+	}
+	return nil
+}
+
+// extractTarGz unpacks the directories and regular files of a .tar.gz.
+// Links and other special files are skipped.
+func extractTarGz(data []byte, root string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid bundle archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	e := &extractor{root: root}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid bundle archive: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			dir, err := bundlePath(root, header.Name)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("failed to unpack bundle: %w", err)
+			}
+		case tar.TypeReg:
+			if err := e.file(header.Name, header.FileInfo().Mode(), tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractZip unpacks the directories and regular files of a .zip
+func extractZip(data []byte, root string) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("invalid bundle archive: %w", err)
+	}
+
+	e := &extractor{root: root}
+	for _, zf := range zr.File {
+		mode := zf.Mode()
+		switch {
+		case mode.IsDir():
+			dir, err := bundlePath(root, zf.Name)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("failed to unpack bundle: %w", err)
+			}
+		case mode.IsRegular():
+			r, err := zf.Open()
+			if err != nil {
+				return fmt.Errorf("invalid bundle archive: %w", err)
+			}
+			err = e.file(zf.Name, mode, r)
+			_ = r.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fetchManifest downloads bundle.json from a skill's base URL. It returns nil
+// when there is no manifest; responses that aren't JSON are not manifests.
+func (r *Resolver) fetchManifest(baseURL string) (*bundleManifest, error) {
+	data, contentType, err := r.fetch(baseURL+"/"+manifestFileName, maxURLFileSize)
+	if err != nil {
+		return nil, nil
+	}
+	trimmed := bytes.TrimSpace(data)
+	isJSON := strings.Contains(strings.ToLower(contentType), "json") || bytes.HasPrefix(trimmed, []byte("{"))
+	if !isJSON {
+		return nil, nil
+	}
+
+	manifest := &bundleManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", manifestFileName, err)
+	}
+	return manifest, nil
+}
+
+// resolveManifestBundle writes a downloaded SKILL.md and the files listed in
+// its manifest into a temporary skill directory
+func (r *Resolver) resolveManifestBundle(skillContent []byte, baseURL string, manifest *bundleManifest) (*ResolveResult, error) {
+	tmpDir, err := os.MkdirTemp("", "skillet-bundle-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+
+	name := path.Base(baseURL)
+	if name == "" || name == "." || name == "/" || strings.Contains(name, ":") {
+		name = "skill"
+	}
+	skillDir := filepath.Join(tmpDir, name)
+
+	err = r.writeManifestBundle(skillDir, skillContent, baseURL, manifest)
+	var sum string
+	if err == nil {
+		sum, err = HashTree(skillDir)
+	}
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, err
+	}
+
+	return &ResolveResult{
+		Path:        filepath.Join(skillDir, skillFileName),
+		IsURL:       true,
+		Type:        ResourceTypeSkill,
+		SHA256:      sum,
+		cleanupPath: tmpDir,
+	}, nil
+}
+
+// writeManifestBundle downloads each manifest file into skillDir
+func (r *Resolver) writeManifestBundle(skillDir string, skillContent []byte, baseURL string, manifest *bundleManifest) error {
+	e := &extractor{root: skillDir}
+	if err := e.file(skillFileName, 0o644, bytes.NewReader(skillContent)); err != nil {
+		return err
+	}
+
+	for _, file := range manifest.Files {
+		if _, err := bundlePath(skillDir, file); err != nil {
+			return err
+		}
+		fileURL, err := url.JoinPath(baseURL, strings.Split(path.Clean(file), "/")...)
+		if err != nil {
+			return fmt.Errorf("invalid path in bundle: %s", file)
+		}
+
+		data, _, err := r.fetch(fileURL, maxBundleFileSize)
+		if err != nil {
+			return fmt.Errorf("failed to download bundle file %s: %w", file, err)
+		}
+		if len(data) > maxBundleFileSize {
+			return fmt.Errorf("bundle file %s too large: must be ≤1MB", file)
+		}
+
+		// Scripts keep working when they start with a shebang
+		mode := os.FileMode(0o644)
+		if bytes.HasPrefix(data, []byte("#!")) {
+			mode = 0o755
+		}
+		if err := e.file(file, mode, bytes.NewReader(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
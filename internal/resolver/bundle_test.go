@@ -0,0 +1,326 @@
+package resolver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/martinemde/skillet/internal/lockfile"
+)
+
+// bundleFile is a file to put in a test archive
+type bundleFile struct {
+	name    string
+	content string
+	mode    int64
+}
+
+func makeTarGz(t *testing.T, files []bundleFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		mode := f.mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: mode, Size: int64(len(f.content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(f.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeZip(t *testing.T, files []bundleFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(f.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// serve starts a server that returns files by path and 404 otherwise,
+// with downloads cached in a temp directory
+func serve(t *testing.T, files map[string][]byte) *httptest.Server {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+const bundleSkill = "---\nname: review\ndescription: Review code\n---\nRun {baseDir}/scripts/run.sh\n"
+
+func TestResolve_URL_TarGzBundle(t *testing.T) {
+	archive := makeTarGz(t, []bundleFile{
+		{name: "SKILL.md", content: bundleSkill},
+		{name: "scripts/run.sh", content: "#!/bin/sh\necho ok\n", mode: 0o755},
+	})
+	server := serve(t, map[string][]byte{"/review.tar.gz": archive})
+
+	result, err := Resolve(server.URL + "/review.tar.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.IsURL || result.BaseURL != "" || result.Type != ResourceTypeSkill {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if filepath.Base(filepath.Dir(result.Path)) != "review" {
+		t.Errorf("skill should be unpacked into a directory named after the archive, got %s", result.Path)
+	}
+	info, err := os.Stat(filepath.Join(filepath.Dir(result.Path), "scripts", "run.sh"))
+	if err != nil {
+		t.Fatalf("script not unpacked: %v", err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("script should stay executable, got %v", info.Mode())
+	}
+
+	result.Cleanup()
+	if _, err := os.Stat(result.Path); !os.IsNotExist(err) {
+		t.Errorf("Cleanup should remove the bundle, got %v", err)
+	}
+}
+
+func TestResolve_URL_ZipBundleSelector(t *testing.T) {
+	archive := makeZip(t, []bundleFile{
+		{name: "repo-main/skills/review/SKILL.md", content: bundleSkill},
+		{name: "repo-main/skills/lint/SKILL.md", content: bundleSkill},
+		{name: "repo-main/commands/fix.md", content: "Fix it"},
+	})
+	server := serve(t, map[string][]byte{"/main.zip": archive})
+
+	_, err := Resolve(server.URL + "/main.zip")
+	if err == nil || !strings.Contains(err.Error(), "select one of: repo-main/skills/lint, repo-main/skills/review") {
+		t.Errorf("expected an error listing the skills, got: %v", err)
+	}
+
+	// The selector may skip the archive's single top-level directory
+	result, err := Resolve(server.URL + "/main.zip#skills/review")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer result.Cleanup()
+	if !strings.HasSuffix(result.Path, filepath.Join("skills", "review", "SKILL.md")) {
+		t.Errorf("unexpected path: %s", result.Path)
+	}
+
+	result, err = Resolve(server.URL + "/main.zip#repo-main/commands/fix.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer result.Cleanup()
+	if result.Type != ResourceTypeCommand {
+		t.Errorf("expected a command, got %+v", result)
+	}
+}
+
+func TestResolve_URL_BundleUnsafePath(t *testing.T) {
+	archive := makeTarGz(t, []bundleFile{
+		{name: "SKILL.md", content: bundleSkill},
+		{name: "../escape.sh", content: "#!/bin/sh\n"},
+	})
+	server := serve(t, map[string][]byte{"/evil.tgz": archive})
+
+	_, err := Resolve(server.URL + "/evil.tgz")
+	if err == nil || !strings.Contains(err.Error(), "invalid path in bundle") {
+		t.Errorf("expected an invalid path error, got: %v", err)
+	}
+}
+
+func TestResolve_URL_ManifestBundle(t *testing.T) {
+	server := serve(t, map[string][]byte{
+		"/skills/review/SKILL.md":               []byte(bundleSkill),
+		"/skills/review/bundle.json":            []byte(`{"files": ["scripts/run.sh", "references/api.md"]}`),
+		"/skills/review/scripts/run.sh":         []byte("#!/bin/sh\necho ok\n"),
+		"/skills/review/references/api.md":      []byte("# API\n"),
+		"/skills/broken/SKILL.md":               []byte(bundleSkill),
+		"/skills/broken/bundle.json":            []byte(`{"files": ["missing.sh"]}`),
+		"/skills/escape/SKILL.md":               []byte(bundleSkill),
+		"/skills/escape/bundle.json":            []byte(`{"files": ["../../secret"]}`),
+		"/skills/review/references/unlisted.md": []byte("not downloaded"),
+		"/skills/plain/SKILL.md":                []byte(bundleSkill),
+	})
+
+	result, err := Resolve(server.URL + "/skills/review/SKILL.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer result.Cleanup()
+
+	dir := filepath.Dir(result.Path)
+	if filepath.Base(dir) != "review" || result.BaseURL != "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	info, err := os.Stat(filepath.Join(dir, "scripts", "run.sh"))
+	if err != nil || info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("script should be downloaded and executable: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "references", "api.md")); err != nil {
+		t.Errorf("reference not downloaded: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "references", "unlisted.md")); !os.IsNotExist(err) {
+		t.Error("files not in the manifest should not be downloaded")
+	}
+
+	if _, err := Resolve(server.URL + "/skills/broken/SKILL.md"); err == nil || !strings.Contains(err.Error(), "missing.sh") {
+		t.Errorf("expected a download error for missing.sh, got: %v", err)
+	}
+	if _, err := Resolve(server.URL + "/skills/escape/SKILL.md"); err == nil || !strings.Contains(err.Error(), "invalid path in bundle") {
+		t.Errorf("expected an invalid path error, got: %v", err)
+	}
+
+	// Without a manifest the skill is a single file, as before
+	result, err = Resolve(server.URL + "/skills/plain/SKILL.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer result.Cleanup()
+	if result.BaseURL != server.URL+"/skills/plain" {
+		t.Errorf("BaseURL = %q", result.BaseURL)
+	}
+}
+
+func TestResolve_GitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	work := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(work, "skills", "review"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "skills", "review", "SKILL.md"), []byte(bundleSkill), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "skills"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = work
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	result, err := Resolve("git+file://" + work + "#skills/review")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Type != ResourceTypeSkill || !strings.HasSuffix(result.Path, filepath.Join("skills", "review", "SKILL.md")) || result.Commit == "" || result.SHA256 == "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if !strings.HasPrefix(result.Path, filepath.Join(os.Getenv("XDG_CACHE_HOME"), "skillet", "git")) {
+		t.Errorf("checkout should be in the cache, got %s", result.Path)
+	}
+
+	// Cleanup keeps the cached checkout
+	result.Cleanup()
+	if _, err := os.Stat(result.Path); err != nil {
+		t.Errorf("cached checkout removed: %v", err)
+	}
+
+	if _, err := Resolve("git+file://" + work + "#skills/missing"); err == nil || !strings.Contains(err.Error(), "skills/missing not found") {
+		t.Errorf("expected not found error, got: %v", err)
+	}
+
+	// A locked source keeps running its locked commit after the branch moves
+	source := "git+file://" + work + "#skills/review"
+	lock, err := lockfile.Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Set(source, lockfile.Entry{Commit: result.Commit, SHA256: result.SHA256})
+	if err := os.WriteFile(filepath.Join(work, "skills", "review", "SKILL.md"), []byte(bundleSkill+"v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("git", "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-am", "v2")
+	cmd.Dir = work
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+
+	r := NewWithPaths(nil, nil)
+	r.Lock = lock
+	locked, err := r.Resolve(source)
+	if err != nil || locked.Commit != result.Commit {
+		t.Errorf("locked Resolve() = %+v, %v, want commit %s", locked, err, result.Commit)
+	}
+
+	r.Update = true
+	updated, err := r.Resolve(source)
+	if err != nil || updated.Commit == result.Commit {
+		t.Fatalf("Resolve() with Update = %+v, %v, want the new commit", updated, err)
+	}
+	if entry, _ := lock.Get(source); entry.Commit != updated.Commit || entry.SHA256 != updated.SHA256 {
+		t.Errorf("lock entry = %+v, want the updated commit and hash", entry)
+	}
+}
+
+func TestResolve_URL_Offline(t *testing.T) {
+	server := serve(t, map[string][]byte{"/review/SKILL.md": []byte(bundleSkill)})
+	skillURL := server.URL + "/review/SKILL.md"
+
+	offline := NewWithPaths(nil, nil)
+	offline.Offline = true
+	if _, err := offline.Resolve(skillURL); err == nil || !strings.Contains(err.Error(), "not in cache") {
+		t.Errorf("offline Resolve() before caching error = %v, want not in cache", err)
+	}
+
+	result, err := Resolve(skillURL)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	result.Cleanup()
+	server.Close()
+
+	result, err = offline.Resolve(skillURL)
+	if err != nil {
+		t.Fatalf("offline Resolve() error = %v", err)
+	}
+	defer result.Cleanup()
+	content, err := os.ReadFile(result.Path)
+	if err != nil || string(content) != bundleSkill {
+		t.Errorf("offline Resolve() content = %q, %v", content, err)
+	}
+}
@@ -0,0 +1,116 @@
+package resolver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/martinemde/skillet/internal/gitsource"
+)
+
+// IsRemote reports whether input is a URL or git source, the resources
+// skillet.lock can pin
+func IsRemote(input string) bool {
+	return gitsource.IsSource(input) || isURL(input)
+}
+
+// verifyLock checks a remote result against its skillet.lock entry. With
+// Update set, a changed hash replaces the entry instead of failing.
+func (r *Resolver) verifyLock(input string, result *ResolveResult) error {
+	if r.Lock == nil {
+		return nil
+	}
+	entry, ok := r.Lock.Get(input)
+	if !ok || (entry.SHA256 == result.SHA256 && entry.Commit == result.Commit) {
+		return nil
+	}
+	if !r.Update {
+		return fmt.Errorf("%s changed since it was locked: sha256 %s does not match %s in %s (run with --update to accept it)",
+			input, result.SHA256, entry.SHA256, r.Lock.Path())
+	}
+	entry.SHA256 = result.SHA256
+	entry.Commit = result.Commit
+	r.Lock.Set(input, entry)
+	return r.Lock.Save()
+}
+
+// Hash returns the SHA-256 recorded for a remote result, or hashes a local
+// skill's directory or command file
+func (r *ResolveResult) Hash() (string, error) {
+	if r.SHA256 != "" {
+		return r.SHA256, nil
+	}
+	return hashResource(r)
+}
+
+// hashBytes returns the hex SHA-256 of data
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashResource hashes a resolved skill's directory, or a command's file
+func hashResource(result *ResolveResult) (string, error) {
+	if result.Type == ResourceTypeSkill {
+		return HashTree(filepath.Dir(result.Path))
+	}
+	data, err := os.ReadFile(result.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", result.Path, err)
+	}
+	return hashBytes(data), nil
+}
+
+// HashTree hashes the paths, executable bits, and contents of the regular
+// files under dir, so renaming a script or making it executable changes the hash
+func HashTree(dir string) (string, error) {
+	var lines []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		mode := "644"
+		if info.Mode()&0o111 != 0 {
+			mode = "755"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %x\n", mode, filepath.ToSlash(rel), h.Sum(nil)))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", dir, err)
+	}
+
+	sort.Strings(lines)
+	h := sha256.New()
+	for _, line := range lines {
+		_, _ = io.WriteString(h, line)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
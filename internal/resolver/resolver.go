@@ -1,11 +1,11 @@
 package resolver
 
 import (
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -13,7 +13,13 @@ import (
 	"github.com/martinemde/skillet/internal/command"
 	"github.com/martinemde/skillet/internal/commandpath"
 	"github.com/martinemde/skillet/internal/discovery"
+	"github.com/martinemde/skillet/internal/gitsource"
+	"github.com/martinemde/skillet/internal/httpcache"
+	"github.com/martinemde/skillet/internal/index"
+	"github.com/martinemde/skillet/internal/lockfile"
+	"github.com/martinemde/skillet/internal/resourcepath"
 	"github.com/martinemde/skillet/internal/skillpath"
+	"github.com/martinemde/skillet/internal/xdg"
 )
 
 const (
@@ -21,6 +27,15 @@ const (
 	skillFileName  = "SKILL.md"
 )
 
+// Subdirectories of the skillet cache directory
+const (
+	HTTPCacheDir = "http" // Downloaded URLs, revalidated on each run
+	GitCacheDir  = "git"  // Git checkouts keyed by commit
+)
+
+// ErrNotFound is returned when no skill or command matches
+var ErrNotFound = errors.New("skill or command not found")
+
 // ResourceType indicates what type of resource was resolved
 type ResourceType int
 
@@ -36,9 +51,21 @@ const (
 // ResolveResult contains the resolved path and metadata
 type ResolveResult struct {
 	Path    string       // Absolute path to the resolved file
-	IsURL   bool         // True if the path was resolved from a URL
-	BaseURL string       // Base URL for URL-based resources (empty for local files)
+	IsURL   bool         // True if the path was resolved from a URL or git source
+	BaseURL string       // Base URL for URL-based resources (empty for local files and bundles)
 	Type    ResourceType // Type of resource (skill or command)
+	SHA256  string       // Hash of remote content, as recorded in skillet.lock
+	Commit  string       // Commit a git source resolved to
+
+	cleanupPath string // Downloaded file or bundle directory to remove when done
+}
+
+// Cleanup removes anything downloaded to resolve a URL. It is safe to call
+// for local resources.
+func (r *ResolveResult) Cleanup() {
+	if r.cleanupPath != "" {
+		_ = os.RemoveAll(r.cleanupPath)
+	}
 }
 
 // matchSpecificity indicates how well a query matched a resource
@@ -61,6 +88,7 @@ type match struct {
 	specificity  matchSpecificity // how well the query matched
 	namespace    string           // for error messages
 	name         string           // for error messages
+	source       string           // source name, for error messages
 }
 
 // matchCandidate evaluates if a resource matches the query and returns the match or nil
@@ -103,9 +131,21 @@ func matchCandidate(queryNS, queryName, name, namespace, path string, priority i
 type Resolver struct {
 	skillPath *skillpath.Path
 	cmdPath   *commandpath.Path
+
+	// Offline resolves URLs and git sources only from the cache
+	Offline bool
+
+	// Lock pins remote resources to the hashes in skillet.lock; nil disables it
+	Lock *lockfile.File
+	// Update accepts remote content that no longer matches Lock and records it
+	Update bool
+
+	// Index caches discovery for name resolution; nil walks every source
+	Index *index.Index
 }
 
-// New creates a new Resolver with default skill and command paths
+// New creates a new Resolver with default skill and command paths, the
+// working directory's skillet.lock, and the discovery index
 func New() (*Resolver, error) {
 	sp, err := skillpath.New()
 	if err != nil {
@@ -117,9 +157,25 @@ func New() (*Resolver, error) {
 		return nil, fmt.Errorf("failed to initialize command path: %w", err)
 	}
 
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	lock, err := lockfile.Load(lockfile.FindDir(workDir))
+	if err != nil {
+		return nil, err
+	}
+
+	ix, err := index.Default()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Resolver{
 		skillPath: sp,
 		cmdPath:   cp,
+		Lock:      lock,
+		Index:     ix,
 	}, nil
 }
 
@@ -143,7 +199,7 @@ func parseNamespaceQuery(query string) (namespace, name string) {
 
 // Resolve takes a path argument and resolves it to a SKILL.md or command file.
 // Resolution order:
-// 1. If URL: download and validate
+// 1. If URL or git source: download and validate
 // 2. If exact file path exists: use it
 // 3. If directory with SKILL.md exists: use it
 // 4. Namespace-aware discovery:
@@ -152,9 +208,9 @@ func parseNamespaceQuery(query string) (namespace, name string) {
 //   - Score by specificity, priority, and resource type
 //   - Return best match or collision error
 func (r *Resolver) Resolve(input string) (*ResolveResult, error) {
-	// Check if it's a URL
-	if isURL(input) {
-		return resolveURL(input)
+	// Check if it's a git source like github:owner/repo/skill@ref or a URL
+	if IsRemote(input) {
+		return r.resolveRemote(input)
 	}
 
 	// Try exact path
@@ -188,7 +244,7 @@ func (r *Resolver) Resolve(input string) (*ResolveResult, error) {
 		return r.resolveByName(input)
 	}
 
-	return nil, fmt.Errorf("skill or command not found: %s", input)
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, input)
 }
 
 // resolveByName resolves a bare word query using namespace-aware matching
@@ -196,9 +252,17 @@ func (r *Resolver) resolveByName(query string) (*ResolveResult, error) {
 	queryNS, queryName := parseNamespaceQuery(query)
 
 	var matches []match
+	var names []string // every visible name, for suggestions
 
-	// Discover all skills
 	skillDisc := discovery.New(r.skillPath)
+	cmdDisc := command.NewDiscoverer(r.cmdPath)
+	if r.Index != nil {
+		skillDisc = discovery.NewWithFinder(r.skillPath, r.Index.SkillFinder())
+		cmdDisc = command.NewDiscovererWithFinder(r.cmdPath, r.Index.CommandFinder())
+		defer func() { _ = r.Index.Save() }()
+	}
+
+	// Discover all skills
 	skills, err := skillDisc.Discover()
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover skills: %w", err)
@@ -207,12 +271,15 @@ func (r *Resolver) resolveByName(query string) (*ResolveResult, error) {
 	// Collect skill matches
 	for _, skill := range skills {
 		if m := matchCandidate(queryNS, queryName, skill.Name, skill.Namespace, skill.Path, skill.Source.Priority, ResourceTypeSkill, skill.Overshadowed); m != nil {
+			m.source = skill.Source.Name
 			matches = append(matches, *m)
 		}
+		if !skill.Overshadowed {
+			names = append(names, skill.QualifiedName())
+		}
 	}
 
 	// Discover all commands
-	cmdDisc := command.NewDiscoverer(r.cmdPath)
 	commands, err := cmdDisc.Discover()
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover commands: %w", err)
@@ -221,12 +288,19 @@ func (r *Resolver) resolveByName(query string) (*ResolveResult, error) {
 	// Collect command matches
 	for _, cmd := range commands {
 		if m := matchCandidate(queryNS, queryName, cmd.Name, cmd.Namespace, cmd.Path, cmd.Source.Priority, ResourceTypeCommand, cmd.Overshadowed); m != nil {
+			m.source = cmd.Source.Name
 			matches = append(matches, *m)
 		}
+		if !cmd.Overshadowed {
+			names = append(names, cmd.QualifiedName())
+		}
 	}
 
 	if len(matches) == 0 {
-		return nil, fmt.Errorf("skill or command not found: %s (tried exact path, directory with SKILL.md, .claude/skills/<name>/SKILL.md, $HOME/.claude/skills/<name>/SKILL.md, .claude/commands/<name>.md, and $HOME/.claude/commands/<name>.md)", query)
+		if suggestions := suggest(query, names); len(suggestions) > 0 {
+			return nil, fmt.Errorf("%w: %s (did you mean %s?)", ErrNotFound, query, joinOr(suggestions))
+		}
+		return nil, fmt.Errorf("%w: %s (run skillet --list to see what's available)", ErrNotFound, query)
 	}
 
 	// Sort matches by: specificity → priority → resource type (skills before commands)
@@ -241,22 +315,19 @@ func (r *Resolver) resolveByName(query string) (*ResolveResult, error) {
 		return matches[i].resourceType < matches[j].resourceType
 	})
 
-	// Check for ambiguous matches (collision)
-	// Two matches are ambiguous if they have the same specificity and priority
-	if len(matches) > 1 {
-		best := matches[0]
-		// Check if there's another match with same specificity and priority but different namespace
-		for _, m := range matches[1:] {
+	// Check for ambiguous matches (collision). Namespaced fallbacks are
+	// ambiguous when several share the best priority.
+	best := matches[0]
+	if best.specificity == namespacedFallback {
+		var tied []match
+		for _, m := range matches {
 			if m.specificity == best.specificity && m.priority == best.priority {
-				// Only collision if both are namespacedFallback (ambiguous fallback)
-				if best.specificity == namespacedFallback && m.specificity == namespacedFallback {
-					return nil, fmt.Errorf("ambiguous match for %q: found both %s:%s and %s:%s at same priority. Use explicit namespace (e.g., %s:%s or %s:%s)",
-						query, best.namespace, best.name, m.namespace, m.name, best.namespace, queryName, m.namespace, queryName)
-				}
-			} else {
-				break // Rest of matches have lower priority
+				tied = append(tied, m)
 			}
 		}
+		if len(tied) > 1 {
+			return nil, ambiguousError(query, tied)
+		}
 	}
 
 	// Return the best match
@@ -266,6 +337,32 @@ func (r *Resolver) resolveByName(query string) (*ResolveResult, error) {
 	}, nil
 }
 
+// ambiguousError lists every candidate for an ambiguous query with its source
+func ambiguousError(query string, candidates []match) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ambiguous match for %q, use a namespace to pick one:", query)
+	for _, m := range candidates {
+		kind := "skill"
+		if m.resourceType == ResourceTypeCommand {
+			kind = "command"
+		}
+		fmt.Fprintf(&b, "\n  %s:%s (%s from %s: %s)", m.namespace, m.name, kind, m.source, resourcepath.RelativePath(m.path))
+	}
+	return errors.New(b.String())
+}
+
+// joinOr joins names as "a", "a or b", or "a, b, or c"
+func joinOr(names []string) string {
+	switch len(names) {
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + " or " + names[1]
+	default:
+		return strings.Join(names[:len(names)-1], ", ") + ", or " + names[len(names)-1]
+	}
+}
+
 // Resolve is a convenience function that creates a default Resolver and resolves the path.
 // For multiple resolutions or testing, use New() and call Resolve() on the returned Resolver.
 func Resolve(path string) (*ResolveResult, error) {
@@ -276,6 +373,62 @@ func Resolve(path string) (*ResolveResult, error) {
 	return r.Resolve(path)
 }
 
+// resolveRemote resolves a git source or URL and checks it against skillet.lock
+func (r *Resolver) resolveRemote(input string) (*ResolveResult, error) {
+	var result *ResolveResult
+	var err error
+	if gitsource.IsSource(input) {
+		result, err = r.resolveGit(input)
+	} else {
+		result, err = r.resolveURL(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.verifyLock(input, result); err != nil {
+		result.Cleanup()
+		return nil, err
+	}
+	return result, nil
+}
+
+// resolveGit fetches a git source into the cache and finds the skill or
+// command inside it. Cached checkouts are kept between runs. A source locked
+// in skillet.lock fetches its locked commit unless updating.
+func (r *Resolver) resolveGit(input string) (*ResolveResult, error) {
+	src, err := gitsource.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	if r.Lock != nil && !r.Update {
+		if entry, ok := r.Lock.Get(input); ok && entry.Commit != "" {
+			src.Ref = entry.Commit
+		}
+	}
+
+	cacheDir, err := xdg.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	dir, commit, err := src.Fetch(filepath.Join(cacheDir, GitCacheDir), r.Offline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", input, err)
+	}
+
+	result, err := findBundleResource(dir, src.Subdir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", input, err)
+	}
+	result.IsURL = true
+	result.Commit = commit
+	result.SHA256, err = hashResource(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // isURL checks if a string is a valid HTTP(S) URL
 func isURL(s string) bool {
 	u, err := url.Parse(s)
@@ -286,39 +439,30 @@ func isURL(s string) bool {
 	return scheme == "http" || scheme == "https"
 }
 
-// resolveURL downloads a skill from a URL and validates it
-func resolveURL(urlStr string) (*ResolveResult, error) {
+// resolveURL downloads a skill from a URL and validates it. Archive URLs
+// and skills with a bundle.json manifest are unpacked into a local directory.
+func (r *Resolver) resolveURL(urlStr string) (*ResolveResult, error) {
 	// Parse the URL
 	parsedURL, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	// Download the file
-	resp, err := http.Get(urlStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download URL: %w", err)
+	if isArchiveURL(parsedURL) {
+		return r.resolveArchive(parsedURL)
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to download URL: HTTP %d", resp.StatusCode)
+	// Download the file
+	content, contentType, err := r.fetch(urlStr, maxURLFileSize)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check Content-Type to ensure it's text
-	contentType := resp.Header.Get("Content-Type")
 	if contentType != "" && !isTextContentType(contentType) {
 		return nil, fmt.Errorf("URL must point to a text file, got Content-Type: %s", contentType)
 	}
 
-	// Read the response body with size limit
-	content, err := io.ReadAll(io.LimitReader(resp.Body, maxURLFileSize+1))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read URL content: %w", err)
-	}
-
 	// Check size limit
 	if len(content) > maxURLFileSize {
 		return nil, fmt.Errorf("URL content too large: must be ≤25kB, got %d bytes", len(content))
@@ -329,6 +473,28 @@ func resolveURL(urlStr string) (*ResolveResult, error) {
 		return nil, fmt.Errorf("URL content appears to be binary, not text")
 	}
 
+	// Get the base URL (directory containing the file)
+	baseURL := parsedURL.Scheme + "://" + parsedURL.Host + path.Dir(parsedURL.Path)
+
+	// Determine type based on filename in URL (default to skill for URL-based)
+	resourceType := ResourceTypeSkill
+	urlPath := parsedURL.Path
+	if !strings.HasSuffix(strings.ToUpper(urlPath), "/SKILL.MD") && strings.HasSuffix(strings.ToLower(urlPath), ".md") {
+		// It's an .md file but not SKILL.md, treat as command
+		resourceType = ResourceTypeCommand
+	}
+
+	// A bundle.json next to SKILL.md lists the skill's other files
+	if resourceType == ResourceTypeSkill {
+		manifest, err := r.fetchManifest(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			return r.resolveManifestBundle(content, baseURL, manifest)
+		}
+	}
+
 	// Create a temporary file to store the downloaded content
 	tmpFile, err := os.CreateTemp("", "skillet-url-*.md")
 	if err != nil {
@@ -346,25 +512,26 @@ func resolveURL(urlStr string) (*ResolveResult, error) {
 		return nil, fmt.Errorf("failed to close temporary file: %w", err)
 	}
 
-	// Get the base URL (directory containing the file)
-	baseURL := parsedURL.Scheme + "://" + parsedURL.Host + filepath.Dir(parsedURL.Path)
-
-	// Determine type based on filename in URL (default to skill for URL-based)
-	resourceType := ResourceTypeSkill
-	urlPath := parsedURL.Path
-	if !strings.HasSuffix(strings.ToUpper(urlPath), "/SKILL.MD") && strings.HasSuffix(strings.ToLower(urlPath), ".md") {
-		// It's an .md file but not SKILL.md, treat as command
-		resourceType = ResourceTypeCommand
-	}
-
 	return &ResolveResult{
-		Path:    tmpFile.Name(),
-		IsURL:   true,
-		BaseURL: baseURL,
-		Type:    resourceType,
+		Path:        tmpFile.Name(),
+		IsURL:       true,
+		BaseURL:     baseURL,
+		Type:        resourceType,
+		SHA256:      hashBytes(content),
+		cleanupPath: tmpFile.Name(),
 	}, nil
 }
 
+// fetch downloads a URL through the cache, reading at most limit+1 bytes so
+// callers can report content over the limit. It returns the body and Content-Type.
+func (r *Resolver) fetch(urlStr string, limit int64) ([]byte, string, error) {
+	cacheDir, err := xdg.CacheDir()
+	if err != nil {
+		return nil, "", err
+	}
+	return httpcache.New(filepath.Join(cacheDir, HTTPCacheDir), r.Offline).Get(urlStr, limit)
+}
+
 // isTextContentType checks if the Content-Type header indicates text
 func isTextContentType(contentType string) bool {
 	// Remove parameters (e.g., "text/plain; charset=utf-8" -> "text/plain")
@@ -327,6 +327,8 @@ func TestResolve_ExactFilePath_ResourceType(t *testing.T) {
 }
 
 func TestResolve_URL_Success(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
 	// Create a test HTTP server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
@@ -362,6 +364,8 @@ func TestResolve_URL_Success(t *testing.T) {
 }
 
 func TestResolve_URL_TooLarge(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
 	// Create a test HTTP server that returns a file larger than 25kB
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
@@ -382,6 +386,8 @@ func TestResolve_URL_TooLarge(t *testing.T) {
 }
 
 func TestResolve_URL_BinaryContent(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
 	// Create a test HTTP server that returns binary content
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/octet-stream")
@@ -402,6 +408,8 @@ func TestResolve_URL_BinaryContent(t *testing.T) {
 }
 
 func TestResolve_URL_404(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
 	// Create a test HTTP server that returns 404
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -704,6 +712,38 @@ func TestResolver_CollisionError(t *testing.T) {
 	if !strings.Contains(err.Error(), "ambiguous") {
 		t.Errorf("expected ambiguous match error, got: %v", err)
 	}
+	for _, candidate := range []string{"backend:test (skill from project", "frontend:test (skill from project"} {
+		if !strings.Contains(err.Error(), candidate) {
+			t.Errorf("expected the error to list %s, got: %v", candidate, err)
+		}
+	}
+}
+
+func TestResolver_NotFoundSuggestions(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillsDir := filepath.Join(tmpDir, "project", ".claude", "skills")
+	createTestSkill(t, skillsDir, "", "review")
+	createTestSkill(t, skillsDir, "frontend", "deploy")
+
+	r := NewWithPaths(
+		skillpath.NewWithSources([]skillpath.Source{{Path: skillsDir, Name: "project", Priority: 0}}),
+		commandpath.NewWithSources([]commandpath.Source{{Path: filepath.Join(tmpDir, "project", ".claude", "commands"), Name: "project", Priority: 0}}),
+	)
+
+	_, err := r.Resolve("reveiw")
+	if err == nil || !strings.Contains(err.Error(), "did you mean review?") {
+		t.Errorf("expected a suggestion for a typo, got: %v", err)
+	}
+
+	_, err = r.Resolve("deplyo")
+	if err == nil || !strings.Contains(err.Error(), "did you mean frontend:deploy?") {
+		t.Errorf("expected a namespaced suggestion, got: %v", err)
+	}
+
+	_, err = r.Resolve("unrelated")
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("expected no suggestions for an unrelated name, got: %v", err)
+	}
 }
 
 func TestResolver_SkillsBeforeCommands(t *testing.T) {
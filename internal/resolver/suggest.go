@@ -0,0 +1,89 @@
+package resolver
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the number of names offered when resolution fails
+const maxSuggestions = 3
+
+// suggest returns the candidate names closest to query: typos within a few
+// edits first, then names that start with or contain the query. Queries
+// without a namespace are compared with names in any namespace.
+func suggest(query string, candidates []string) []string {
+	query = strings.ToLower(query)
+	queryNS, queryName := parseNamespaceQuery(query)
+	threshold := max(1, len(queryName)/3)
+
+	type scored struct {
+		name  string
+		score int
+	}
+	var found []scored
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+
+		_, name := parseNamespaceQuery(lower)
+		d := editDistance(queryName, name)
+		if queryNS != "" {
+			d = editDistance(query, lower)
+		}
+		switch {
+		case d <= threshold:
+			found = append(found, scored{candidate, d})
+		case len(queryName) >= 3 && (strings.HasPrefix(name, queryName) || strings.Contains(lower, query)):
+			found = append(found, scored{candidate, threshold + 1})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].score != found[j].score {
+			return found[i].score < found[j].score
+		}
+		// Prefer unnamespaced and shorter names
+		if len(found[i].name) != len(found[j].name) {
+			return len(found[i].name) < len(found[j].name)
+		}
+		return found[i].name < found[j].name
+	})
+
+	var names []string
+	for i := 0; i < len(found) && i < maxSuggestions; i++ {
+		names = append(names, found[i].name)
+	}
+	return names
+}
+
+// editDistance returns the Damerau-Levenshtein distance between a and b,
+// counting a swap of adjacent letters as one edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"review", "frontend:review", "reviewer", "deploy", "frontend:test", "backend:test"}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"reveiw", []string{"review", "frontend:review"}},
+		{"deplyo", []string{"deploy"}},
+		{"frontend:tset", []string{"frontend:test"}},
+		{"rev", []string{"review", "reviewer", "frontend:review"}},
+		{"xyz", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := suggest(tt.query, candidates); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggest(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"review", "review", 0},
+		{"reveiw", "review", 1},
+		{"revie", "review", 1},
+		{"deploy", "review", 5},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
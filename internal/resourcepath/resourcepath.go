@@ -5,13 +5,19 @@
 package resourcepath
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/martinemde/skillet/internal/config"
 )
 
 const (
 	// ClaudeDir is the name of the Claude configuration directory
 	ClaudeDir = ".claude"
+	// EnvVar lists extra directories to search, like PATH
+	EnvVar = "SKILLET_PATH"
 )
 
 // Source represents a location where resources can be found
@@ -36,7 +42,11 @@ type Path struct {
 
 // New creates a new resource path with the default sources:
 // 1. Project-scoped: .claude/<subdir> in working directory (priority 0)
-// 2. User-scoped: ~/.claude/<subdir> (priority 1)
+// 2. Parent projects: .claude/<subdir> in each parent up to the git root
+// 3. User-scoped: ~/.claude/<subdir>
+//
+// Directories from SKILLET_PATH and the config file's search-path are
+// searched before or after these.
 func New(subdir string) (*Path, error) {
 	return NewWithWorkDir(subdir, "")
 }
@@ -52,30 +62,162 @@ func NewWithWorkDir(subdir, workDir string) (*Path, error) {
 		}
 	}
 
+	prepend, appended, err := searchDirs(workDir)
+	if err != nil {
+		return nil, err
+	}
+
 	var sources []Source
+	addDirs := func(dirs []config.SearchDir) {
+		for _, d := range dirs {
+			sources = append(sources, Source{
+				Path:      filepath.Join(d.Path, subdir),
+				Name:      d.Name,
+				Priority:  len(sources),
+				Namespace: d.Namespace,
+			})
+		}
+	}
+
+	addDirs(prepend)
 
 	// Add project-scoped source (working directory)
 	projectPath := filepath.Join(workDir, ClaudeDir, subdir)
 	sources = append(sources, Source{
 		Path:     projectPath,
 		Name:     "project",
-		Priority: 0,
+		Priority: len(sources),
 	})
 
+	homeDir, homeErr := os.UserHomeDir()
+
+	// Add parent directories' .claude up to the git root, nearest first
+	for _, dir := range parentProjectDirs(workDir, homeDir) {
+		rel, err := filepath.Rel(workDir, dir)
+		if err != nil {
+			rel = dir
+		}
+		sources = append(sources, Source{
+			Path:     filepath.Join(dir, ClaudeDir, subdir),
+			Name:     "project " + rel,
+			Priority: len(sources),
+		})
+	}
+
 	// Add user-scoped source (home directory)
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
+	if homeErr == nil {
 		userPath := filepath.Join(homeDir, ClaudeDir, subdir)
 		sources = append(sources, Source{
 			Path:     userPath,
 			Name:     "user",
-			Priority: 1,
+			Priority: len(sources),
 		})
 	}
 
+	addDirs(appended)
+
 	return &Path{sources: sources}, nil
 }
 
+// parentProjectDirs returns the parents of workDir that have a .claude
+// directory, walking up to the git root or the filesystem root. The home
+// directory is skipped since its .claude is the user source.
+func parentProjectDirs(workDir, homeDir string) []string {
+	dir, err := filepath.Abs(workDir)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for !isGitRoot(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+		if dir == homeDir {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, ClaudeDir)); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// isGitRoot reports whether dir is the top of a git work tree. .git is a
+// file in worktrees and submodules.
+func isGitRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// searchDirs returns the extra directories to search before and after the
+// project and user directories: SKILLET_PATH's, then the config file's
+func searchDirs(workDir string) (prepend, appended []config.SearchDir, err error) {
+	prepend, appended = ParseEnv(os.Getenv(EnvVar), workDir)
+
+	cfg, err := config.LoadWithWorkDir(workDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, d := range cfg.SearchPath {
+		if d.Position == config.PositionPrepend {
+			prepend = append(prepend, d)
+		} else {
+			appended = append(appended, d)
+		}
+	}
+
+	for _, dirs := range [][]config.SearchDir{prepend, appended} {
+		for i := range dirs {
+			if dirs[i].Name == "" {
+				dirs[i].Name = filepath.Base(dirs[i].Path)
+			}
+			if dirs[i].Name == "project" || dirs[i].Name == "user" {
+				return nil, nil, fmt.Errorf("search path %s: the name %q is reserved", dirs[i].Path, dirs[i].Name)
+			}
+		}
+	}
+	return prepend, appended, nil
+}
+
+// ParseEnv parses a SKILLET_PATH value: directories separated like PATH,
+// each optionally named as name=dir. An empty entry stands for the project
+// and user directories, so "shared:" searches shared first and ":shared"
+// searches it after them. Without an empty entry, directories come first.
+// Relative directories are relative to workDir.
+func ParseEnv(value, workDir string) (prepend, appended []config.SearchDir) {
+	if value == "" {
+		return nil, nil
+	}
+
+	inDefaults := false
+	for _, entry := range filepath.SplitList(value) {
+		if entry == "" {
+			inDefaults = true
+			continue
+		}
+		var d config.SearchDir
+		if name, dir, ok := strings.Cut(entry, "="); ok {
+			d.Name, d.Path = name, dir
+		} else {
+			d.Path = entry
+		}
+		if !filepath.IsAbs(d.Path) {
+			d.Path = filepath.Join(workDir, d.Path)
+		}
+		if inDefaults {
+			d.Position = config.PositionAppend
+			appended = append(appended, d)
+		} else {
+			d.Position = config.PositionPrepend
+			prepend = append(prepend, d)
+		}
+	}
+	return prepend, appended
+}
+
 // NewWithSources creates a Path with custom sources.
 // This is useful for testing or custom configurations.
 func NewWithSources(sources []Source) *Path {
@@ -0,0 +1,334 @@
+// Package scaffold creates new skills from built-in or user templates.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/skill"
+	"github.com/martinemde/skillet/internal/skillpath"
+	"github.com/martinemde/skillet/internal/validation"
+	"github.com/martinemde/skillet/internal/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultTemplate is used when no template is specified
+	DefaultTemplate = "default"
+	// ScopeProject creates the skill in .claude/skills of the working directory
+	ScopeProject = skillpath.ScopeProject
+	// ScopeUser creates the skill in ~/.claude/skills
+	ScopeUser = skillpath.ScopeUser
+)
+
+// resourceDirs are the optional skill directories from the agentskills.io spec
+var resourceDirs = []string{"scripts", "references", "assets"}
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// Config holds the configuration for creating a skill.
+type Config struct {
+	// Name is the skill name, optionally namespaced as "namespace:name"
+	Name string
+	// Description is the frontmatter description (a placeholder is used if empty)
+	Description string
+	// Scope is ScopeProject (default) or ScopeUser
+	Scope string
+	// OutputDir is a custom skills directory (overrides Scope)
+	OutputDir string
+	// Template is the template name (defaults to DefaultTemplate)
+	Template string
+	// WorkDir is the project directory (defaults to the current directory)
+	WorkDir string
+	// Force overwrites an existing skill without error
+	Force bool
+}
+
+// Result contains the outcome of creating a skill.
+type Result struct {
+	// SkillPath is the path to the new SKILL.md
+	SkillPath string
+	// SkillName is the name of the skill
+	SkillName string
+	// Namespace is the namespace (if any)
+	Namespace string
+	// Template is the template the skill was created from
+	Template string
+	// Files lists created files and directories relative to the skill directory
+	Files []string
+}
+
+// templateData is passed to SKILL.md templates
+type templateData struct {
+	Name        string
+	Namespace   string
+	Description string
+	Title       string
+}
+
+// Create writes a new skill from a template.
+func Create(cfg Config) (*Result, error) {
+	namespace, name := splitName(cfg.Name)
+	if err := validation.ValidateName(name, "skill"); err != nil {
+		return nil, err
+	}
+	if namespace != "" {
+		for _, part := range strings.Split(namespace, "/") {
+			if err := validation.ValidateName(part, "namespace"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	templateName := cfg.Template
+	if templateName == "" {
+		templateName = DefaultTemplate
+	}
+	tmpl, err := findTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	skillsDir, err := skillsDir(cfg)
+	if err != nil {
+		return nil, err
+	}
+	skillDir := filepath.Join(skillsDir, filepath.FromSlash(namespace), name)
+	skillPath := filepath.Join(skillDir, skillpath.SkillFile)
+
+	if !cfg.Force {
+		if _, err := os.Stat(skillPath); err == nil {
+			return nil, fmt.Errorf("skill already exists at %s (use --force to overwrite)", skillPath)
+		}
+	}
+
+	description := cfg.Description
+	if description == "" {
+		description = fmt.Sprintf("TODO: Describe what %s does and when Claude should use it", name)
+	}
+	data := templateData{
+		Name:        name,
+		Namespace:   namespace,
+		Description: description,
+		Title:       title(name),
+	}
+
+	_, statErr := os.Stat(skillDir)
+	existed := statErr == nil
+
+	files, err := writeTemplate(tmpl, skillDir, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Catch broken templates before anyone tries to run the skill.
+	// Missing required arguments are expected since none are given here.
+	if _, err := skill.Parse(skillPath, ""); err != nil {
+		var argErr *arguments.Error
+		if !errors.As(err, &argErr) {
+			if !existed {
+				_ = os.RemoveAll(skillDir)
+			}
+			return nil, fmt.Errorf("template %q produced an invalid skill: %w", templateName, err)
+		}
+	}
+
+	return &Result{
+		SkillPath: skillPath,
+		SkillName: name,
+		Namespace: namespace,
+		Template:  templateName,
+		Files:     files,
+	}, nil
+}
+
+// Templates returns the names of all available templates, user templates included.
+func Templates() ([]string, error) {
+	seen := make(map[string]bool)
+
+	entries, err := fs.ReadDir(builtinTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			seen[entry.Name()] = true
+		}
+	}
+
+	if dir, err := UserTemplatesDir(); err == nil {
+		if entries, err := os.ReadDir(dir); err == nil {
+			for _, entry := range entries {
+				if entry.IsDir() {
+					seen[entry.Name()] = true
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// UserTemplatesDir returns the directory searched for user templates
+func UserTemplatesDir() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// findTemplate returns the template filesystem, preferring user templates over built-ins
+func findTemplate(name string) (fs.FS, error) {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	if dir, err := UserTemplatesDir(); err == nil {
+		userDir := filepath.Join(dir, name)
+		if info, err := os.Stat(userDir); err == nil && info.IsDir() {
+			return os.DirFS(userDir), nil
+		}
+	}
+
+	if info, err := fs.Stat(builtinTemplates, path.Join("templates", name)); err == nil && info.IsDir() {
+		sub, err := fs.Sub(builtinTemplates, path.Join("templates", name))
+		if err != nil {
+			return nil, err
+		}
+		return sub, nil
+	}
+
+	available, _ := Templates()
+	return nil, fmt.Errorf("template %q not found (available: %s)", name, strings.Join(available, ", "))
+}
+
+// skillsDir returns the skills directory for the configured scope.
+// Scopes map to the same .claude/skills directories skills are discovered from.
+func skillsDir(cfg Config) (string, error) {
+	if cfg.OutputDir != "" {
+		return cfg.OutputDir, nil
+	}
+	return skillpath.ScopeDir(cfg.Scope, cfg.WorkDir)
+}
+
+// writeTemplate copies the template into skillDir, rendering SKILL.md,
+// and creates the spec's resource directories. Returns the created paths.
+func writeTemplate(tmpl fs.FS, skillDir string, data templateData) ([]string, error) {
+	var files []string
+
+	err := fs.WalkDir(tmpl, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return os.MkdirAll(skillDir, 0o755)
+		}
+
+		target := filepath.Join(skillDir, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+
+		content, err := fs.ReadFile(tmpl, p)
+		if err != nil {
+			return err
+		}
+		if p == skillpath.SkillFile {
+			content, err = render(content, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := os.WriteFile(target, content, fileMode(tmpl, p)); err != nil {
+			return fmt.Errorf("failed to write %s: %w", p, err)
+		}
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write template: %w", err)
+	}
+
+	for _, dir := range resourceDirs {
+		if err := os.MkdirAll(filepath.Join(skillDir, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		files = append(files, dir+"/")
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// render executes a SKILL.md template
+func render(content []byte, data templateData) ([]byte, error) {
+	t, err := template.New(skillpath.SkillFile).Funcs(template.FuncMap{
+		"quote": quoteYAML,
+	}).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fileMode keeps scripts executable; embedded files don't carry their mode
+func fileMode(tmpl fs.FS, p string) fs.FileMode {
+	if strings.HasPrefix(p, "scripts/") {
+		return 0o755
+	}
+	if info, err := fs.Stat(tmpl, p); err == nil && info.Mode()&0o111 != 0 {
+		return 0o755
+	}
+	return 0o644
+}
+
+// quoteYAML renders a string as a YAML scalar, quoting it when needed
+func quoteYAML(s string) string {
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// splitName splits "namespace:name" into a namespace path and a name.
+// Nested namespaces ("a:b:name") become nested directories.
+func splitName(full string) (string, string) {
+	parts := strings.Split(full, ":")
+	name := parts[len(parts)-1]
+	return strings.Join(parts[:len(parts)-1], "/"), name
+}
+
+// title turns a skill name like "code-review" into "Code Review"
+func title(name string) string {
+	words := strings.Split(name, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
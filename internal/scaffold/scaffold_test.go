@@ -0,0 +1,201 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/martinemde/skillet/internal/skill"
+)
+
+func TestCreate_BuiltinTemplates(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	for _, name := range []string{"default", "script", "structured"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			result, err := Create(Config{Name: "my-skill", Template: name, OutputDir: dir})
+			if err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+
+			if result.SkillPath != filepath.Join(dir, "my-skill", "SKILL.md") {
+				t.Errorf("SkillPath = %q", result.SkillPath)
+			}
+			for _, sub := range []string{"scripts", "references", "assets"} {
+				if info, err := os.Stat(filepath.Join(dir, "my-skill", sub)); err != nil || !info.IsDir() {
+					t.Errorf("Expected %s/ to be created", sub)
+				}
+			}
+
+			data, err := os.ReadFile(result.SkillPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "name: my-skill") {
+				t.Errorf("SKILL.md should have the skill name, got:\n%s", data)
+			}
+		})
+	}
+}
+
+func TestCreate_ScriptIsExecutable(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+
+	if _, err := Create(Config{Name: "runner", Template: "script", OutputDir: dir}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "runner", "scripts", "run.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&0o111 == 0 {
+		t.Errorf("scripts/run.sh should be executable, mode %v", info.Mode())
+	}
+}
+
+func TestCreate_DescriptionIsQuoted(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+
+	result, err := Create(Config{Name: "quoted", Description: "Review: files # and more", OutputDir: dir})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	parsed, err := skill.Parse(result.SkillPath, "")
+	if err != nil {
+		t.Fatalf("Created skill should parse: %v", err)
+	}
+	if parsed.Description != "Review: files # and more" {
+		t.Errorf("Description = %q", parsed.Description)
+	}
+}
+
+func TestCreate_ProjectAndUserScope(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	workDir := t.TempDir()
+
+	project, err := Create(Config{Name: "team:lint", WorkDir: workDir})
+	if err != nil {
+		t.Fatalf("Create project skill failed: %v", err)
+	}
+	if want := filepath.Join(workDir, ".claude", "skills", "team", "lint", "SKILL.md"); project.SkillPath != want {
+		t.Errorf("Project SkillPath = %q, want %q", project.SkillPath, want)
+	}
+	if project.Namespace != "team" || project.SkillName != "lint" {
+		t.Errorf("Namespace/name = %q/%q, want team/lint", project.Namespace, project.SkillName)
+	}
+
+	user, err := Create(Config{Name: "lint", Scope: ScopeUser, WorkDir: workDir})
+	if err != nil {
+		t.Fatalf("Create user skill failed: %v", err)
+	}
+	if want := filepath.Join(home, ".claude", "skills", "lint", "SKILL.md"); user.SkillPath != want {
+		t.Errorf("User SkillPath = %q, want %q", user.SkillPath, want)
+	}
+}
+
+func TestCreate_Errors(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+
+	if _, err := Create(Config{Name: "exists", OutputDir: dir}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"invalid name", Config{Name: "Bad_Name", OutputDir: dir}, "invalid skill name"},
+		{"invalid namespace", Config{Name: "Team:ok", OutputDir: dir}, "invalid namespace name"},
+		{"unknown template", Config{Name: "ok", Template: "nope", OutputDir: dir}, `template "nope" not found`},
+		{"template path", Config{Name: "ok", Template: "../default", OutputDir: dir}, "invalid template name"},
+		{"invalid scope", Config{Name: "ok", Scope: "global"}, "invalid scope"},
+		{"already exists", Config{Name: "exists", OutputDir: dir}, "already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Create(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Create() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Create(Config{Name: "exists", OutputDir: dir, Force: true}); err != nil {
+		t.Errorf("Create with Force should overwrite, got %v", err)
+	}
+}
+
+func TestCreate_UserTemplate(t *testing.T) {
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+
+	templateDir := filepath.Join(config, "skillet", "templates", "team")
+	if err := os.MkdirAll(filepath.Join(templateDir, "references"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	skillTemplate := "---\nname: {{.Name}}\ndescription: {{quote .Description}}\nmodel: sonnet\n---\n\nFollow {baseDir}/references/style.md\n"
+	if err := os.WriteFile(filepath.Join(templateDir, "SKILL.md"), []byte(skillTemplate), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, "references", "style.md"), []byte("# Style\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := Templates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "default,script,structured,team" {
+		t.Errorf("Templates() = %v", names)
+	}
+
+	dir := t.TempDir()
+	result, err := Create(Config{Name: "styled", Template: "team", Description: "Apply the team style", OutputDir: dir})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	parsed, err := skill.Parse(result.SkillPath, "")
+	if err != nil {
+		t.Fatalf("Created skill should parse: %v", err)
+	}
+	if parsed.Model != "sonnet" {
+		t.Errorf("Model = %q, want sonnet from user template", parsed.Model)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "styled", "references", "style.md")); err != nil {
+		t.Errorf("Template files should be copied: %v", err)
+	}
+}
+
+func TestCreate_InvalidUserTemplate(t *testing.T) {
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+
+	templateDir := filepath.Join(config, "skillet", "templates", "broken")
+	if err := os.MkdirAll(templateDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, "SKILL.md"), []byte("---\nname: {{.Name}}\n---\n\nNo description\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	_, err := Create(Config{Name: "broken-skill", Template: "broken", OutputDir: dir})
+	if err == nil || !strings.Contains(err.Error(), "produced an invalid skill") {
+		t.Errorf("Expected invalid skill error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "broken-skill")); !os.IsNotExist(err) {
+		t.Error("Invalid skill should be removed")
+	}
+}
@@ -0,0 +1,5 @@
+#!/bin/sh
+# Replace this with the work the skill should do
+set -eu
+
+echo "Running with arguments: $*"
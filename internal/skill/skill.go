@@ -1,21 +1,17 @@
 package skill
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 
+	"github.com/martinemde/skillet/internal/arguments"
 	"github.com/martinemde/skillet/internal/frontmatter"
 	"github.com/martinemde/skillet/internal/validation"
 	"gopkg.in/yaml.v3"
 )
 
-var (
-	// argumentsRegex matches $ARGUMENTS variable references
-	argumentsRegex = regexp.MustCompile(`\$ARGUMENTS`)
-)
-
 // Skill represents a parsed SKILL.md file
 type Skill struct {
 	// Claude Code spec fields
@@ -30,6 +26,12 @@ type Skill struct {
 	Agent                  string `yaml:"agent,omitempty"`                    // Subagent type when context: fork
 	Hooks                  any    `yaml:"hooks,omitempty"`                    // Hooks scoped to skill lifecycle
 
+	// Skillet extension fields
+	OutputSchema any                     `yaml:"output-schema,omitempty"` // Inline JSON Schema or path relative to BaseDir
+	Arguments    []arguments.Declaration `yaml:"arguments,omitempty"`     // Typed, named arguments
+	MaxCost      float64                 `yaml:"max-cost,omitempty"`      // Stop the run past this estimated cost in USD
+	MaxTurns     int                     `yaml:"max-turns,omitempty"`     // Stop the run past this many turns
+
 	// agentskills.io spec fields
 	License       string            `yaml:"license,omitempty"`
 	Compatibility string            `yaml:"compatibility,omitempty"`
@@ -42,14 +44,20 @@ type Skill struct {
 }
 
 // Parse reads and parses a SKILL.md file
-func Parse(skillPath string, arguments string) (*Skill, error) {
-	return ParseWithBaseDir(skillPath, "", arguments)
+func Parse(skillPath string, args string) (*Skill, error) {
+	return ParseWithBaseDir(skillPath, "", args)
 }
 
 // ParseWithBaseDir reads and parses a SKILL.md file with an optional custom base directory
 // If baseDir is empty, it defaults to the directory containing the skill file
-// The arguments string replaces $ARGUMENTS in the skill content
-func ParseWithBaseDir(skillPath string, baseDir string, arguments string) (*Skill, error) {
+// The args string replaces $ARGUMENTS in the skill content
+func ParseWithBaseDir(skillPath string, baseDir string, args string) (*Skill, error) {
+	return ParseWithInput(skillPath, baseDir, arguments.FromString(args))
+}
+
+// ParseWithInput reads and parses a SKILL.md file, binding input to the declared arguments.
+// Invalid arguments are reported as an *arguments.Error that includes the skill's usage.
+func ParseWithInput(skillPath string, baseDir string, input arguments.Input) (*Skill, error) {
 	// Resolve absolute path
 	absPath, err := filepath.Abs(skillPath)
 	if err != nil {
@@ -80,14 +88,21 @@ func ParseWithBaseDir(skillPath string, baseDir string, arguments string) (*Skil
 		skill.Name = filepath.Base(baseDir)
 	}
 
-	// Interpolate variables
-	skill.Content = interpolateVariables(skill.Content, baseDir, arguments)
-
 	// Validate required fields
 	if err := skill.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	// Bind and validate arguments
+	values, err := arguments.Resolve(skill.Arguments, input)
+	if err != nil {
+		return nil, &arguments.Error{Err: err, Usage: skill.Usage()}
+	}
+
+	// Interpolate variables
+	skill.Content = validation.InterpolateBaseDir(skill.Content, baseDir)
+	skill.Content = arguments.Interpolate(skill.Content, skill.Arguments, values)
+
 	return skill, nil
 }
 
@@ -108,21 +123,36 @@ func parseFrontmatter(data string) (*Skill, error) {
 	return skill, nil
 }
 
-// interpolateVariables replaces variables like {baseDir} and $ARGUMENTS with actual values
-// If $ARGUMENTS is not present in the content and arguments are provided,
-// appends "ARGUMENTS: <value>" to the content per the agentskills.io spec.
-func interpolateVariables(content, baseDir, arguments string) string {
-	content = validation.InterpolateBaseDir(content, baseDir)
+// Usage returns a usage message describing the skill's arguments
+func (s *Skill) Usage() string {
+	return arguments.Usage(s.Name, s.ArgumentHint, s.Arguments)
+}
 
-	if argumentsRegex.MatchString(content) {
-		// $ARGUMENTS is present, replace it with arguments
-		content = argumentsRegex.ReplaceAllString(content, arguments)
-	} else if arguments != "" {
-		// $ARGUMENTS not present but arguments provided, append them
-		content = content + "\n\nARGUMENTS: " + arguments
+// OutputSchemaJSON returns the skill's output schema as JSON, or nil if none is set.
+// An inline schema is converted from YAML; a string is read as a file relative to BaseDir.
+func (s *Skill) OutputSchemaJSON() ([]byte, error) {
+	switch v := s.OutputSchema.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		path := v
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(s.BaseDir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read output-schema: %w", err)
+		}
+		return data, nil
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert output-schema to JSON: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("output-schema must be a mapping or a file path, got %T", v)
 	}
-
-	return content
 }
 
 // IsUserInvocable returns whether the skill should appear in the / menu.
@@ -149,5 +179,17 @@ func (s *Skill) Validate() error {
 		return fmt.Errorf("compatibility too long: max 500 characters, got %d", len(s.Compatibility))
 	}
 
+	if err := arguments.Validate(s.Arguments); err != nil {
+		return err
+	}
+
+	if s.MaxCost < 0 {
+		return fmt.Errorf("max-cost must not be negative, got %g", s.MaxCost)
+	}
+
+	if s.MaxTurns < 0 {
+		return fmt.Errorf("max-turns must not be negative, got %d", s.MaxTurns)
+	}
+
 	return nil
 }
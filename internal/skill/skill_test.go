@@ -1,9 +1,14 @@
 package skill
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/martinemde/skillet/internal/arguments"
+	"github.com/martinemde/skillet/internal/validation"
 )
 
 func TestParse_SimpleSkill(t *testing.T) {
@@ -292,7 +297,7 @@ func TestInterpolateVariables(t *testing.T) {
 	baseDir := "/path/to/skill"
 	content := "Base directory is {baseDir} and config is at {baseDir}/config.json"
 
-	result := interpolateVariables(content, baseDir, "")
+	result := validation.InterpolateBaseDir(content, baseDir)
 
 	expected := "Base directory is /path/to/skill and config is at /path/to/skill/config.json"
 	if result != expected {
@@ -335,7 +340,7 @@ func TestParseWithBaseDir_ExplicitBaseDir(t *testing.T) {
 
 func TestInterpolateVariables_Arguments(t *testing.T) {
 	content := "Process file $ARGUMENTS with options"
-	result := interpolateVariables(content, "/base", "myfile.txt --verbose")
+	result := arguments.Interpolate(content, nil, arguments.Values{Raw: "myfile.txt --verbose"})
 
 	expected := "Process file myfile.txt --verbose with options"
 	if result != expected {
@@ -345,7 +350,7 @@ func TestInterpolateVariables_Arguments(t *testing.T) {
 
 func TestInterpolateVariables_MultipleArguments(t *testing.T) {
 	content := "First: $ARGUMENTS, Second: $ARGUMENTS"
-	result := interpolateVariables(content, "/base", "arg1 arg2")
+	result := arguments.Interpolate(content, nil, arguments.Values{Raw: "arg1 arg2"})
 
 	expected := "First: arg1 arg2, Second: arg1 arg2"
 	if result != expected {
@@ -355,7 +360,7 @@ func TestInterpolateVariables_MultipleArguments(t *testing.T) {
 
 func TestInterpolateVariables_AppendArgumentsWhenNotPresent(t *testing.T) {
 	content := "No arguments placeholder in content"
-	result := interpolateVariables(content, "/base", "myarg --flag")
+	result := arguments.Interpolate(content, nil, arguments.Values{Raw: "myarg --flag"})
 
 	expected := "No arguments placeholder in content\n\nARGUMENTS: myarg --flag"
 	if result != expected {
@@ -365,7 +370,7 @@ func TestInterpolateVariables_AppendArgumentsWhenNotPresent(t *testing.T) {
 
 func TestInterpolateVariables_NoAppendWhenArgumentsEmpty(t *testing.T) {
 	content := "No arguments placeholder in content"
-	result := interpolateVariables(content, "/base", "")
+	result := arguments.Interpolate(content, nil, arguments.Values{})
 
 	// Content should remain unchanged when arguments are empty
 	if result != content {
@@ -375,7 +380,7 @@ func TestInterpolateVariables_NoAppendWhenArgumentsEmpty(t *testing.T) {
 
 func TestInterpolateVariables_NoAppendWhenPlaceholderPresent(t *testing.T) {
 	content := "Use $ARGUMENTS here"
-	result := interpolateVariables(content, "/base", "myarg")
+	result := arguments.Interpolate(content, nil, arguments.Values{Raw: "myarg"})
 
 	// $ARGUMENTS should be replaced, not appended
 	expected := "Use myarg here"
@@ -485,3 +490,73 @@ func TestIsUserInvocable_ExplicitFalse(t *testing.T) {
 		t.Error("IsUserInvocable() should return false when UserInvocable is explicitly false")
 	}
 }
+
+func TestOutputSchemaJSON_Inline(t *testing.T) {
+	skill, err := Parse("../../testdata/output-schema-skill/SKILL.md", "")
+	if err != nil {
+		t.Fatalf("Failed to parse output schema skill: %v", err)
+	}
+
+	data, err := skill.OutputSchemaJSON()
+	if err != nil {
+		t.Fatalf("OutputSchemaJSON() error: %v", err)
+	}
+
+	expected := `{"properties":{"severity":{"enum":["low","medium","high"]}},"required":["severity"],"type":"object"}`
+	if string(data) != expected {
+		t.Errorf("OutputSchemaJSON() = %s, want %s", data, expected)
+	}
+}
+
+func TestOutputSchemaJSON_File(t *testing.T) {
+	dir := t.TempDir()
+	schema := `{"type":"object"}`
+	if err := os.WriteFile(filepath.Join(dir, "schema.json"), []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	skill := &Skill{OutputSchema: "schema.json", BaseDir: dir}
+	data, err := skill.OutputSchemaJSON()
+	if err != nil {
+		t.Fatalf("OutputSchemaJSON() error: %v", err)
+	}
+	if string(data) != schema {
+		t.Errorf("OutputSchemaJSON() = %s, want %s", data, schema)
+	}
+}
+
+func TestOutputSchemaJSON_None(t *testing.T) {
+	skill := &Skill{Name: "test-skill", Description: "Test"}
+	data, err := skill.OutputSchemaJSON()
+	if err != nil || data != nil {
+		t.Errorf("OutputSchemaJSON() = %s, %v; want nil, nil", data, err)
+	}
+}
+
+func TestParseWithInput_Arguments(t *testing.T) {
+	input := arguments.Input{
+		Positional: []string{"skill.go"},
+		Named:      map[string]string{"limit": "2"},
+	}
+	skill, err := ParseWithInput("../../testdata/arguments-skill/SKILL.md", "", input)
+	if err != nil {
+		t.Fatalf("Failed to parse arguments skill: %v", err)
+	}
+
+	expected := "Review skill.go in fast mode with at most 2 comments."
+	if !strings.Contains(skill.Content, expected) {
+		t.Errorf("Content should contain %q, got: %s", expected, skill.Content)
+	}
+}
+
+func TestParseWithInput_MissingRequiredArgument(t *testing.T) {
+	_, err := ParseWithInput("../../testdata/arguments-skill/SKILL.md", "", arguments.Input{})
+
+	var argErr *arguments.Error
+	if !errors.As(err, &argErr) {
+		t.Fatalf("Expected *arguments.Error, got %v", err)
+	}
+	if !strings.Contains(argErr.Usage, "Usage: skillet arguments-skill <file>") {
+		t.Errorf("Error should carry the skill's usage, got: %s", argErr.Usage)
+	}
+}
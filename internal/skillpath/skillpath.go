@@ -5,6 +5,7 @@
 package skillpath
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/martinemde/skillet/internal/pluginpath"
@@ -18,6 +19,10 @@ const (
 	SkillsDir = "skills"
 	// SkillFile is the filename for skill definitions
 	SkillFile = "SKILL.md"
+	// ScopeProject is the skills directory of the working directory
+	ScopeProject = "project"
+	// ScopeUser is ~/.claude/skills
+	ScopeUser = "user"
 )
 
 // Source represents a location where skills can be found
@@ -44,10 +49,10 @@ func NewWithWorkDir(workDir string) (*Path, error) {
 		return nil, err
 	}
 
-	// Load plugin sources (priority 2+, after project and user)
+	// Load plugin sources (lowest priority, after project, user, and search path)
 	plugins, err := pluginpath.Load()
 	if err == nil && len(plugins) > 0 {
-		pluginSources := pluginpath.SkillSources(plugins, 2)
+		pluginSources := pluginpath.SkillSources(plugins, len(p.Sources()))
 		p.AppendSources(pluginSources)
 	}
 
@@ -65,3 +70,26 @@ func NewWithSources(sources []Source) *Path {
 func SkillPath(sourceDir, skillName string) string {
 	return filepath.Join(sourceDir, skillName, SkillFile)
 }
+
+// ScopeDir returns the skills directory for ScopeProject (the default when
+// scope is empty) or ScopeUser. If workDir is empty, the current working
+// directory is used.
+func ScopeDir(scope, workDir string) (string, error) {
+	if scope == "" {
+		scope = ScopeProject
+	}
+	if scope != ScopeProject && scope != ScopeUser {
+		return "", fmt.Errorf("invalid scope %q (must be %s or %s)", scope, ScopeProject, ScopeUser)
+	}
+
+	p, err := resourcepath.NewWithWorkDir(SkillsDir, workDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine skills directory: %w", err)
+	}
+	for _, source := range p.Sources() {
+		if source.Name == scope {
+			return source.Path, nil
+		}
+	}
+	return "", fmt.Errorf("failed to determine %s skills directory", scope)
+}
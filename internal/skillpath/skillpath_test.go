@@ -59,6 +59,99 @@ func TestNewWithWorkDir(t *testing.T) {
 	}
 }
 
+func TestNewWithWorkDir_SearchPath(t *testing.T) {
+	workDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(workDir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("SKILLET_PATH", "shared=/opt/shared"+string(os.PathListSeparator)+string(os.PathListSeparator)+"vendor")
+
+	configDir := filepath.Join(configHome, "skillet")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := "search-path:\n  - path: " + filepath.Join(workDir, "tools") + "\n    namespace: tools\n  - path: /opt/first\n    position: prepend\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := NewWithWorkDir(workDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Source{
+		{Path: "/opt/shared/skills", Name: "shared"},
+		{Path: "/opt/first/skills", Name: "first"},
+		{Path: filepath.Join(workDir, ClaudeDir, SkillsDir), Name: "project"},
+		{Path: "", Name: "user"},
+		{Path: filepath.Join(workDir, "vendor", "skills"), Name: "vendor"},
+		{Path: filepath.Join(workDir, "tools", "skills"), Name: "tools", Namespace: "tools"},
+	}
+	sources := path.Sources()
+	if len(sources) < len(want) {
+		t.Fatalf("expected at least %d sources, got %+v", len(want), sources)
+	}
+	for i, w := range want {
+		got := sources[i]
+		if got.Name != w.Name || got.Namespace != w.Namespace || got.Priority != i || (w.Path != "" && got.Path != w.Path) {
+			t.Errorf("sources[%d] = %+v, want %+v at priority %d", i, got, w, i)
+		}
+	}
+}
+
+func TestNewWithWorkDir_ReservedSearchPathName(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SKILLET_PATH", "project=/opt/shared")
+
+	if _, err := NewWithWorkDir(t.TempDir()); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("expected reserved name error, got %v", err)
+	}
+}
+
+func TestNewWithWorkDir_ParentProjects(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SKILLET_PATH", "")
+
+	root := t.TempDir()
+	workDir := filepath.Join(root, "services", "api")
+	for _, dir := range []string{
+		filepath.Join(root, ".git"),
+		filepath.Join(root, ClaudeDir, SkillsDir),
+		filepath.Join(root, "services", ClaudeDir),
+		filepath.Join(workDir, "handlers"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path, err := NewWithWorkDir(filepath.Join(workDir, "handlers"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Source{
+		{Path: filepath.Join(workDir, "handlers", ClaudeDir, SkillsDir), Name: "project"},
+		{Path: filepath.Join(root, "services", ClaudeDir, SkillsDir), Name: "project ../.."},
+		{Path: filepath.Join(root, ClaudeDir, SkillsDir), Name: "project ../../.."},
+	}
+	sources := path.Sources()
+	if len(sources) < len(want) {
+		t.Fatalf("expected at least %d sources, got %+v", len(want), sources)
+	}
+	for i, w := range want {
+		if got := sources[i]; got.Path != w.Path || got.Name != w.Name || got.Priority != i {
+			t.Errorf("sources[%d] = %+v, want %+v at priority %d", i, got, w, i)
+		}
+	}
+	if len(sources) > len(want) && sources[len(want)].Name != "user" {
+		t.Errorf("expected the walk to stop at the git root, got %+v", sources[len(want)])
+	}
+}
+
 func TestNewWithSources(t *testing.T) {
 	customSources := []Source{
 		{Path: "/custom/path1", Name: "custom1", Priority: 0},
@@ -0,0 +1,203 @@
+// Package toolrule parses Claude Code tool permission rules such as
+// "Read", "Bash(git diff:*)", or "mcp__github__create_issue".
+package toolrule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KnownTools are the built-in Claude Code tools that rules can refer to
+var KnownTools = []string{
+	"AskUserQuestion", "Bash", "BashOutput", "Edit", "ExitPlanMode", "Glob",
+	"Grep", "KillShell", "LS", "MultiEdit", "NotebookEdit", "NotebookRead",
+	"Read", "SlashCommand", "Skill", "Task", "TodoWrite", "WebFetch",
+	"WebSearch", "Write",
+}
+
+var (
+	// toolNameRegex matches a tool name, including MCP tools like mcp__server__tool
+	toolNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+)
+
+// Rule is a single permission rule: a tool name with an optional specifier
+type Rule struct {
+	Tool      string // Tool name, e.g. "Bash"
+	Specifier string // Text inside the parentheses, e.g. "git diff:*"; empty means any use
+}
+
+// String returns the rule in Claude Code syntax
+func (r Rule) String() string {
+	if r.Specifier == "" {
+		return r.Tool
+	}
+	return r.Tool + "(" + r.Specifier + ")"
+}
+
+// IsMCP reports whether the rule refers to an MCP server tool
+func (r Rule) IsMCP() bool {
+	return strings.HasPrefix(r.Tool, "mcp__")
+}
+
+// IsKnown reports whether the rule refers to a built-in tool or an MCP tool
+func (r Rule) IsKnown() bool {
+	if r.IsMCP() {
+		return true
+	}
+	for _, tool := range KnownTools {
+		if r.Tool == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether a tool use matches the rule. The target is the
+// command, file path, or other key input of the use. A specifier ending in
+// ":*" matches by prefix, and "*" elsewhere matches any text.
+func (r Rule) Matches(tool, target string) bool {
+	if r.Tool != tool {
+		return false
+	}
+	if r.Specifier == "" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(r.Specifier, ":*"); ok {
+		return strings.HasPrefix(target, prefix)
+	}
+	return wildcardMatch(r.Specifier, target)
+}
+
+// targetKeys are the tool input fields a specifier matches, in order of preference
+var targetKeys = []string{"command", "file_path", "notebook_path", "path", "url", "pattern", "query"}
+
+// InputTarget returns the tool input a specifier matches against: the
+// command for Bash, the file path for file tools, the URL for WebFetch, and so on.
+// The key is returned so callers can treat paths specially.
+func InputTarget(input map[string]any) (key, target string) {
+	for _, key := range targetKeys {
+		if value, ok := input[key].(string); ok {
+			return key, value
+		}
+	}
+	return "", ""
+}
+
+// Parse parses a single rule like "Bash(npm test:*)"
+func Parse(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Rule{}, fmt.Errorf("empty rule")
+	}
+
+	open := strings.Index(s, "(")
+	if open == -1 {
+		if strings.Contains(s, ")") {
+			return Rule{}, fmt.Errorf("invalid rule %q: unmatched ')'", s)
+		}
+		if !toolNameRegex.MatchString(s) {
+			return Rule{}, fmt.Errorf("invalid rule %q: not a tool name", s)
+		}
+		return Rule{Tool: s}, nil
+	}
+
+	tool := s[:open]
+	if !toolNameRegex.MatchString(tool) {
+		return Rule{}, fmt.Errorf("invalid rule %q: not a tool name", s)
+	}
+	if !strings.HasSuffix(s, ")") {
+		return Rule{}, fmt.Errorf("invalid rule %q: missing ')'", s)
+	}
+
+	specifier := s[open+1 : len(s)-1]
+	if strings.TrimSpace(specifier) == "" {
+		return Rule{}, fmt.Errorf("invalid rule %q: empty specifier", s)
+	}
+	if depth(specifier) != 0 {
+		return Rule{}, fmt.Errorf("invalid rule %q: unbalanced parentheses", s)
+	}
+
+	return Rule{Tool: tool, Specifier: specifier}, nil
+}
+
+// Split splits an allowed-tools value into rule strings. Rules are separated
+// by commas or whitespace; separators inside parentheses are part of the rule.
+func Split(s string) []string {
+	var rules []string
+	var current strings.Builder
+	level := 0
+
+	flush := func() {
+		if rule := strings.TrimSpace(current.String()); rule != "" {
+			rules = append(rules, rule)
+		}
+		current.Reset()
+	}
+
+	for _, r := range s {
+		switch {
+		case r == '(':
+			level++
+		case r == ')':
+			level--
+		case level <= 0 && (r == ',' || r == ' ' || r == '\t' || r == '\n'):
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+
+	return rules
+}
+
+// ParseList splits and parses an allowed-tools value
+func ParseList(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, part := range Split(s) {
+		rule, err := Parse(part)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// depth returns the parenthesis nesting left open at the end of s
+func depth(s string) int {
+	level := 0
+	for _, r := range s {
+		switch r {
+		case '(':
+			level++
+		case ')':
+			level--
+			if level < 0 {
+				return level
+			}
+		}
+	}
+	return level
+}
+
+// wildcardMatch matches s against a pattern where "*" matches any text
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i == -1 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
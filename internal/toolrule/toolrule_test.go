@@ -0,0 +1,147 @@
+package toolrule
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Rule
+		wantErr string
+	}{
+		{input: "Read", want: Rule{Tool: "Read"}},
+		{input: " Bash(git diff:*) ", want: Rule{Tool: "Bash", Specifier: "git diff:*"}},
+		{input: "Edit(docs/**)", want: Rule{Tool: "Edit", Specifier: "docs/**"}},
+		{input: "mcp__github__create_issue", want: Rule{Tool: "mcp__github__create_issue"}},
+		{input: "Bash(echo (nested))", want: Rule{Tool: "Bash", Specifier: "echo (nested)"}},
+		{input: "", wantErr: "empty rule"},
+		{input: "Bash(git", wantErr: "missing ')'"},
+		{input: "Bash()", wantErr: "empty specifier"},
+		{input: "Read)", wantErr: "unmatched ')'"},
+		{input: "Bash(a))(", wantErr: "missing ')'"},
+		{input: "Bash(a)) ", wantErr: "unbalanced"},
+		{input: "(git)", wantErr: "not a tool name"},
+		{input: "Read*", wantErr: "not a tool name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"Read Write", []string{"Read", "Write"}},
+		{"Read, Write,Bash(git add:*)", []string{"Read", "Write", "Bash(git add:*)"}},
+		{"Bash(npm run test:*) Edit", []string{"Bash(npm run test:*)", "Edit"}},
+		{"  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := Split(tt.input)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Split(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule_IsKnown(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want bool
+	}{
+		{Rule{Tool: "Read"}, true},
+		{Rule{Tool: "mcp__server__tool"}, true},
+		{Rule{Tool: "Reed"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.IsKnown(); got != tt.want {
+			t.Errorf("%s IsKnown() = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	tests := []struct {
+		rule   string
+		tool   string
+		target string
+		want   bool
+	}{
+		{"Read", "Read", "main.go", true},
+		{"Read", "Write", "main.go", false},
+		{"Bash(git diff:*)", "Bash", "git diff --stat", true},
+		{"Bash(git diff:*)", "Bash", "git status", false},
+		{"Bash(go test)", "Bash", "go test", true},
+		{"Bash(go test)", "Bash", "go test ./...", false},
+		{"Write(*.md)", "Write", "docs/README.md", true},
+		{"Write(*.md)", "Write", "main.go", false},
+		{"Edit(src/*/main.go)", "Edit", "src/cmd/main.go", true},
+	}
+
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+		}
+		if got := rule.Matches(tt.tool, tt.target); got != tt.want {
+			t.Errorf("%s Matches(%q, %q) = %v, want %v", tt.rule, tt.tool, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	rules, err := ParseList("Read, Bash(git status)")
+	if err != nil {
+		t.Fatalf("ParseList error: %v", err)
+	}
+	if len(rules) != 2 || rules[1].String() != "Bash(git status)" {
+		t.Errorf("ParseList = %v", rules)
+	}
+
+	if _, err := ParseList("Read Bash(git"); err == nil {
+		t.Error("ParseList should fail on an invalid rule")
+	}
+}
+
+func TestInputTarget(t *testing.T) {
+	tests := []struct {
+		input   map[string]any
+		wantKey string
+		want    string
+	}{
+		{map[string]any{"command": "ls -la", "description": "List"}, "command", "ls -la"},
+		{map[string]any{"file_path": "/tmp/a.go", "content": "x"}, "file_path", "/tmp/a.go"},
+		{map[string]any{"url": "https://example.com", "prompt": "x"}, "url", "https://example.com"},
+		{map[string]any{"todos": []any{}}, "", ""},
+	}
+
+	for _, tt := range tests {
+		key, got := InputTarget(tt.input)
+		if key != tt.wantKey || got != tt.want {
+			t.Errorf("InputTarget(%v) = %q, %q, want %q, %q", tt.input, key, got, tt.wantKey, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Package xdg locates skillet's configuration and cache directories following
+// the XDG Base Directory specification, falling back to ~/.config and ~/.cache.
+package xdg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AppName is the subdirectory used for skillet's files
+const AppName = "skillet"
+
+// ConfigDir returns skillet's configuration directory:
+// $XDG_CONFIG_HOME/skillet, or ~/.config/skillet when unset
+func ConfigDir() (string, error) {
+	return appDir("XDG_CONFIG_HOME", ".config")
+}
+
+// CacheDir returns skillet's cache directory:
+// $XDG_CACHE_HOME/skillet, or ~/.cache/skillet when unset
+func CacheDir() (string, error) {
+	return appDir("XDG_CACHE_HOME", ".cache")
+}
+
+// appDir returns skillet's directory under an XDG base directory. Relative
+// values are ignored, as the specification requires.
+func appDir(envVar, homeFallback string) (string, error) {
+	if dir := os.Getenv(envVar); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, AppName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, homeFallback, AppName), nil
+}
@@ -0,0 +1,61 @@
+package xdg
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigDir_XDGConfigHome(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/config")
+
+	dir, err := ConfigDir()
+	if err != nil {
+		t.Fatalf("ConfigDir() error: %v", err)
+	}
+	if dir != "/tmp/config/skillet" {
+		t.Errorf("ConfigDir() = %q, want /tmp/config/skillet", dir)
+	}
+}
+
+func TestConfigDir_DefaultsToHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	dir, err := ConfigDir()
+	if err != nil {
+		t.Fatalf("ConfigDir() error: %v", err)
+	}
+	if want := filepath.Join(home, ".config", "skillet"); dir != want {
+		t.Errorf("ConfigDir() = %q, want %q", dir, want)
+	}
+}
+
+func TestConfigDir_IgnoresRelativeXDGConfigHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "relative/config")
+
+	dir, err := ConfigDir()
+	if err != nil {
+		t.Fatalf("ConfigDir() error: %v", err)
+	}
+	if want := filepath.Join(home, ".config", "skillet"); dir != want {
+		t.Errorf("ConfigDir() = %q, want %q", dir, want)
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Setenv("XDG_CACHE_HOME", "/tmp/cache")
+	if dir, err := CacheDir(); err != nil || dir != "/tmp/cache/skillet" {
+		t.Errorf("CacheDir() = %q, %v, want /tmp/cache/skillet", dir, err)
+	}
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	if dir, err := CacheDir(); err != nil || dir != filepath.Join(home, ".cache", "skillet") {
+		t.Errorf("CacheDir() = %q, %v, want ~/.cache/skillet", dir, err)
+	}
+}